@@ -11,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 )
 
 // AppServer 应用服务器结构体，封装所有服务和处理器
@@ -20,6 +21,7 @@ type AppServer struct {
 	router             *gin.Engine
 	httpServer         *http.Server
 	authToken          string
+	background         backgroundRunner
 }
 
 // NewAppServer 创建新的应用服务器实例
@@ -53,6 +55,22 @@ func (s *AppServer) Start(port string) error {
 		}
 	}()
 
+	pruneCache(s.xiaohongshuService.cache)
+	s.xiaohongshuService.recoverScheduled()
+	s.background.Start(backgroundJob{
+		name:     "watchlist",
+		interval: configs.DurationFromEnv("XHS_WATCH_INTERVAL", defaultWatchInterval, minWatchInterval),
+		run:      s.xiaohongshuService.sampleWatchlist,
+	}, backgroundJob{
+		name:     "monitor",
+		interval: configs.DurationFromEnv("XHS_MONITOR_INTERVAL", defaultMonitorInterval, minMonitorInterval),
+		run:      s.xiaohongshuService.runSavedSearches,
+	}, backgroundJob{
+		name:     "schedule",
+		interval: configs.DurationFromEnv("XHS_SCHEDULE_INTERVAL", defaultScheduleInterval, minScheduleInterval),
+		run:      s.xiaohongshuService.runScheduledPublishes,
+	})
+
 	// 等待中断信号
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -60,6 +78,8 @@ func (s *AppServer) Start(port string) error {
 
 	logrus.Infof("正在关闭服务器...")
 
+	s.background.Stop()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
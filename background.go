@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// backgroundJob 随服务启停的定时任务
+type backgroundJob struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context)
+}
+
+// backgroundRunner 管理后台任务：Start 起各自的 goroutine，Stop 取消并等它们退出
+type backgroundRunner struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Start 启动任务。每个任务先等一个周期再跑第一轮，避免服务频繁重启时集中访问站点
+func (r *backgroundRunner) Start(jobs ...backgroundJob) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	for _, job := range jobs {
+		r.wg.Add(1)
+		go func(job backgroundJob) {
+			defer r.wg.Done()
+			runPeriodically(ctx, job)
+		}(job)
+	}
+}
+
+// Stop 通知任务退出并等待当前一轮结束
+func (r *backgroundRunner) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+}
+
+func runPeriodically(ctx context.Context, job backgroundJob) {
+	logrus.Infof("后台任务 %s 已启动，间隔 %s", job.name, job.interval)
+
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Infof("后台任务 %s 已停止", job.name)
+			return
+		case <-ticker.C:
+			runJobOnce(ctx, job)
+		}
+	}
+}
+
+// runJobOnce 单轮出错不能带走整个服务
+func runJobOnce(ctx context.Context, job backgroundJob) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("后台任务 %s panic: %v\n%s", job.name, r, debug.Stack())
+		}
+	}()
+
+	start := time.Now()
+	job.run(ctx)
+	logrus.Debugf("后台任务 %s 本轮耗时 %s", job.name, time.Since(start))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestBackgroundRunner 按周期执行，单轮 panic 不影响后续，Stop 后不再执行。
+func TestBackgroundRunner(t *testing.T) {
+	var runs atomic.Int32
+
+	var r backgroundRunner
+	r.Start(backgroundJob{
+		name:     "test",
+		interval: 10 * time.Millisecond,
+		run: func(ctx context.Context) {
+			if runs.Add(1) == 1 {
+				panic("boom")
+			}
+		},
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runs.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	r.Stop()
+
+	if runs.Load() < 3 {
+		t.Fatalf("panic 后应继续执行, runs=%d", runs.Load())
+	}
+
+	stopped := runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	if runs.Load() != stopped {
+		t.Errorf("Stop 后不应再执行")
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/cache"
+)
+
+// 缓存类型
+const (
+	cacheKindFeedDetail  = "feed_detail"
+	cacheKindUserProfile = "user_profile"
+	cacheKindSearch      = "search"
+)
+
+// cacheTTLs 各类型的有效期。
+// 搜索结果变得最快；主页的笔记列表和粉丝数一般半小时内变化不大。
+var cacheTTLs = map[string]time.Duration{
+	cacheKindFeedDetail:  10 * time.Minute,
+	cacheKindUserProfile: 30 * time.Minute,
+	cacheKindSearch:      5 * time.Minute,
+}
+
+// 数据来源
+const (
+	cacheSourceFresh  = "fresh"
+	cacheSourceCached = "cache"
+)
+
+// CacheInfo 响应里说明数据是刚抓的还是缓存的
+type CacheInfo struct {
+	Source     string     `json:"source"` // fresh | cache
+	CachedAt   *time.Time `json:"cached_at,omitempty"`
+	AgeSeconds int        `json:"age_seconds,omitempty"`
+}
+
+// withCache 按策略读缓存，未命中时调用 fetch 抓取并写回缓存。
+// 写缓存失败只记日志，不影响本次结果。
+func withCache[T any](store *cache.Store, mode cache.Mode, kind, key string, fetch func() (*T, error)) (*T, *CacheInfo, error) {
+	if store == nil {
+		v, err := fetch()
+		return v, &CacheInfo{Source: cacheSourceFresh}, err
+	}
+
+	if mode != cache.ModeBypass {
+		var cached T
+		storedAt, err := store.Get(kind, key, cacheTTLs[kind], &cached)
+		switch {
+		case err == nil:
+			return &cached, &CacheInfo{
+				Source:     cacheSourceCached,
+				CachedAt:   &storedAt,
+				AgeSeconds: int(time.Since(storedAt).Seconds()),
+			}, nil
+		case !errors.Is(err, cache.ErrMiss):
+			logrus.Warnf("读取缓存失败: kind=%s key=%s: %v", kind, key, err)
+		}
+
+		if mode == cache.ModeOnly {
+			return nil, nil, fmt.Errorf("缓存中没有未过期的数据（有效期 %s），请改用 cache=prefer", cacheTTLs[kind])
+		}
+	}
+
+	v, err := fetch()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := store.Put(kind, key, v); err != nil {
+		logrus.Warnf("写入缓存失败: kind=%s key=%s: %v", kind, key, err)
+	}
+	if _, err := store.PruneIfDue(kind, cacheTTLs[kind]); err != nil {
+		logrus.Warnf("清理过期缓存失败: kind=%s: %v", kind, err)
+	}
+	return v, &CacheInfo{Source: cacheSourceFresh}, nil
+}
+
+// pruneCache 启动时清掉各类型的过期缓存，缓存目录不会随运行时间无限增长
+func pruneCache(store *cache.Store) {
+	for kind, ttl := range cacheTTLs {
+		n, err := store.Prune(kind, ttl)
+		if err != nil {
+			logrus.Warnf("清理过期缓存失败: kind=%s: %v", kind, err)
+			continue
+		}
+		if n > 0 {
+			logrus.Infof("已清理 %d 个过期缓存: kind=%s", n, kind)
+		}
+	}
+}
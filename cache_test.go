@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/cache"
+)
+
+// TestWithCache 覆盖三种策略：prefer 命中后不再抓取，bypass 总是抓取并刷新，only 未命中时报错且不抓取。
+func TestWithCache(t *testing.T) {
+	store := cache.NewStore(t.TempDir())
+
+	calls := 0
+	fetch := func() (*string, error) {
+		calls++
+		v := "v" + string(rune('0'+calls))
+		return &v, nil
+	}
+
+	_, _, err := withCache(store, cache.ModeOnly, cacheKindFeedDetail, "k", fetch)
+	require.Error(t, err)
+	assert.Equal(t, 0, calls, "only 不应访问站点")
+
+	got, info, err := withCache(store, cache.ModePrefer, cacheKindFeedDetail, "k", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", *got)
+	assert.Equal(t, cacheSourceFresh, info.Source)
+
+	got, info, err = withCache(store, cache.ModePrefer, cacheKindFeedDetail, "k", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", *got)
+	assert.Equal(t, cacheSourceCached, info.Source)
+	assert.NotNil(t, info.CachedAt)
+	assert.Equal(t, 1, calls)
+
+	got, info, err = withCache(store, cache.ModeBypass, cacheKindFeedDetail, "k", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", *got)
+	assert.Equal(t, cacheSourceFresh, info.Source)
+
+	got, _, err = withCache(store, cache.ModeOnly, cacheKindFeedDetail, "k", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", *got, "bypass 抓到的结果应刷新缓存")
+
+	_, _, err = withCache(store, cache.ModePrefer, cacheKindSearch, "boom", func() (*string, error) {
+		return nil, errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+}
@@ -0,0 +1,114 @@
+// publish 从 Markdown 稿件发布图文，适合稿件放在 Git 里、由 CI 或本机直接发布的场景。
+//
+//	go run ./cmd/publish -file posts/weekend.md -dry-run
+//	go run ./cmd/publish -file posts/weekend.md
+//
+// 稿件格式见 pkg/postfile。与服务的发布接口走同一套 publishing 流程，
+// 发布前检查、图片预处理和参数校验都一样。需要先用 cmd/login 登录。
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/postfile"
+	"github.com/xpzouying/xiaohongshu-mcp/publishing"
+)
+
+func main() {
+	var (
+		file     string
+		headless bool
+		dryRun   bool
+	)
+	flag.StringVar(&file, "file", "", "Markdown 稿件路径")
+	flag.BoolVar(&headless, "headless", true, "是否无头模式")
+	flag.BoolVar(&dryRun, "dry-run", false, "只解析并校验稿件，打印结果，不发布")
+	flag.Parse()
+
+	if file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	post, err := postfile.Load(file)
+	if err != nil {
+		logrus.Fatalf("解析稿件失败: %v", err)
+	}
+	if err := post.Validate(time.Now()); err != nil {
+		logrus.Fatalf("稿件校验失败: %v", err)
+	}
+
+	req := &publishing.Request{
+		Title:      post.Title,
+		Content:    post.Content,
+		Images:     post.Images,
+		Tags:       post.Tags,
+		ScheduleAt: post.ScheduleAt,
+		IsOriginal: post.IsOriginal,
+		Visibility: post.Visibility,
+		Products:   post.Products,
+		Location:   post.Location,
+
+		Collection:       post.Collection,
+		CreateCollection: post.CreateCollection,
+		Preprocess:       post.Preprocess,
+	}
+
+	if dryRun {
+		report, err := publishing.Lint(req.Title, req.Content, req.Tags)
+		if err != nil {
+			logrus.Fatalf("发布前检查失败: %v", err)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]any{"post": post, "lint": report}); err != nil {
+			logrus.Fatalf("输出失败: %v", err)
+		}
+		if err := report.Err(); err != nil {
+			logrus.Fatalf("%v", err)
+		}
+		return
+	}
+
+	// 与服务发布接口同一套检查：标题、定时范围、发布前检查、图片下载和预处理
+	prepared, err := publishing.Prepare(req, time.Now())
+	if err != nil {
+		logrus.Fatalf("发布前准备失败: %v", err)
+	}
+
+	// 与服务共用会话文件里的 seed，发布时的指纹与登录时一致
+	store := cookies.NewLoadCookie(cookies.GetCookiesFilePath())
+
+	b := browser.NewBrowser(headless,
+		browser.WithFingerprintSeed(configs.ResolveFingerprintSeed(store)),
+		browser.WithProxy(configs.ProxyFromEnv()),
+	)
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	result, err := publishing.Publish(context.Background(), page, prepared.Content)
+	if err != nil {
+		logrus.Fatalf("发布失败: %v", err)
+	}
+
+	logrus.Infof("发布成功: %s", post.Title)
+	if result.Note != nil {
+		logrus.Infof("笔记ID: %s 链接: %s", result.Note.NoteID, result.Note.URL)
+	}
+	for _, i := range prepared.Lint {
+		logrus.Warnf("发布前检查 [%s] %s: %s", i.Severity, i.Rule, i.Message)
+	}
+	if len(result.FailedMentions) > 0 {
+		logrus.Warnf("以下 @ 用户未能选中，已按普通文本发出: %v", result.FailedMentions)
+	}
+}
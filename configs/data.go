@@ -0,0 +1,34 @@
+package configs
+
+import (
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultDataDir 持久数据的默认目录，与 cookies.json 一样放在当前目录下
+const defaultDataDir = "data"
+
+// GetDataPath 持久数据目录（关注列表、互动数序列等），不能丢，所以不放临时目录。
+// XHS_DATA_DIR 优先。
+func GetDataPath() string {
+	if dir := os.Getenv("XHS_DATA_DIR"); dir != "" {
+		return dir
+	}
+	return defaultDataDir
+}
+
+// DurationFromEnv 从环境变量解析时长（如 30m、2h）。未设返回 def；非法或小于 min 时告警并返回 def。
+func DurationFromEnv(name string, def, min time.Duration) time.Duration {
+	s := os.Getenv(name)
+	if s == "" {
+		return def
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < min {
+		logrus.Warnf("invalid %s=%q (minimum %s), fallback to %s", name, s, min, def)
+		return def
+	}
+	return d
+}
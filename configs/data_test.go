@@ -0,0 +1,37 @@
+package configs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDataPath(t *testing.T) {
+	t.Setenv("XHS_DATA_DIR", "")
+	assert.Equal(t, "data", GetDataPath())
+
+	t.Setenv("XHS_DATA_DIR", "/var/lib/xhs")
+	assert.Equal(t, "/var/lib/xhs", GetDataPath())
+}
+
+// TestDurationFromEnv 非法值和低于下限的值都回退默认值，避免误配成秒级轮询。
+func TestDurationFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want time.Duration
+	}{
+		{name: "未设用默认值", env: "", want: time.Hour},
+		{name: "合法时长", env: "30m", want: 30 * time.Minute},
+		{name: "非法回退默认值", env: "abc", want: time.Hour},
+		{name: "低于下限回退默认值", env: "10s", want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XHS_TEST_INTERVAL", tt.env)
+			assert.Equal(t, tt.want, DurationFromEnv("XHS_TEST_INTERVAL", time.Hour, 5*time.Minute))
+		})
+	}
+}
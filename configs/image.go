@@ -6,9 +6,33 @@ import (
 )
 
 const (
-	ImagesDir = "xiaohongshu_images"
+	ImagesDir    = "xiaohongshu_images"
+	DownloadsDir = "xiaohongshu_downloads"
+	ExportsDir   = "xiaohongshu_exports"
+	CacheDir     = "xiaohongshu_cache"
+	VideosDir    = "xiaohongshu_videos"
 )
 
 func GetImagesPath() string {
 	return filepath.Join(os.TempDir(), ImagesDir)
 }
+
+// GetDownloadsPath 笔记媒体的默认归档目录，调用方未指定目录时使用。
+func GetDownloadsPath() string {
+	return filepath.Join(os.TempDir(), DownloadsDir)
+}
+
+// GetExportsPath 笔记导出（Markdown 等）的默认目录，调用方未指定目录时使用。
+func GetExportsPath() string {
+	return filepath.Join(os.TempDir(), ExportsDir)
+}
+
+// GetCachePath 抓取结果的缓存目录。放在临时目录：缓存丢了只是多抓一次。
+func GetCachePath() string {
+	return filepath.Join(os.TempDir(), CacheDir)
+}
+
+// GetVideosPath 远程视频下载到本地待发布的临时目录，发布后即删除。
+func GetVideosPath() string {
+	return filepath.Join(os.TempDir(), VideosDir)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/drafts"
+)
+
+// saveDraft 把图片复制进草稿目录，存一份本地草稿，不打开浏览器。
+// 创作中心的草稿箱存在浏览器本地，服务每次都起新浏览器，在那里「暂存离开」的草稿关掉浏览器就没了，
+// 所以草稿只存在本地，发布时重新填表。标题、合集参数和发布前检查在进来之前已经做过，
+// 地点、商品这些只有页面能校验的内容到发布草稿时才知道
+func (s *XiaohongshuService) saveDraft(req *PublishRequest, imagePaths []string) (*PublishResponse, error) {
+	id := drafts.NewID()
+	dir := s.drafts.AssetDir(id)
+
+	images, err := copyImages(dir, imagePaths)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	draft := drafts.Draft{
+		ID:         id,
+		Title:      req.Title,
+		Content:    req.Content,
+		Images:     images,
+		Tags:       req.Tags,
+		IsOriginal: req.IsOriginal,
+		Visibility: req.Visibility,
+		Products:   req.Products,
+		Location:   req.Location,
+
+		Collection:       req.Collection,
+		CreateCollection: req.CreateCollection,
+	}
+	if err := s.drafts.Save(draft); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	logrus.Infof("已存草稿 %s: title=%s", id, req.Title)
+
+	return &PublishResponse{
+		Title:   req.Title,
+		Content: req.Content,
+		Images:  len(images),
+		Status:  "已存草稿",
+		DraftID: id,
+	}, nil
+}
+
+// ListDrafts 本地草稿，新的在前
+func (s *XiaohongshuService) ListDrafts() ([]drafts.Draft, error) {
+	return s.drafts.List()
+}
+
+// PublishDraft 按草稿重新填表发布，成功后删除草稿。scheduleAt 非空时定时发布
+func (s *XiaohongshuService) PublishDraft(ctx context.Context, id, scheduleAt string) (*PublishResponse, error) {
+	draft, ok, err := s.drafts.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("草稿 %s 不存在", id)
+	}
+
+	resp, err := s.PublishContent(ctx, &PublishRequest{
+		Title:      draft.Title,
+		Content:    draft.Content,
+		Images:     draft.Images,
+		Tags:       draft.Tags,
+		ScheduleAt: scheduleAt,
+		IsOriginal: draft.IsOriginal,
+		Visibility: draft.Visibility,
+		Products:   draft.Products,
+		Location:   draft.Location,
+
+		Collection:       draft.Collection,
+		CreateCollection: draft.CreateCollection,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 已经发出去了，删草稿失败只记日志，不能让调用方以为没发布而重试
+	if _, err := s.drafts.Delete(id); err != nil {
+		logrus.Warnf("草稿 %s 已发布，但删除失败: %v", id, err)
+	}
+	resp.DraftID = id
+	return resp, nil
+}
+
+// DeleteDraft 删除草稿及其图片
+func (s *XiaohongshuService) DeleteDraft(id string) error {
+	deleted, err := s.drafts.Delete(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("草稿 %s 不存在", id)
+	}
+	return nil
+}
+
+// copyImages 把图片按顺序复制到草稿或定时任务目录，返回新路径
+func copyImages(dir string, paths []string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建图片目录失败: %w", err)
+	}
+
+	images := make([]string, 0, len(paths))
+	for i, src := range paths {
+		dst := filepath.Join(dir, fmt.Sprintf("%02d%s", i+1, strings.ToLower(filepath.Ext(src))))
+		if err := copyFile(src, dst); err != nil {
+			return nil, fmt.Errorf("复制图片 %s 失败: %w", src, err)
+		}
+		images = append(images, dst)
+	}
+	return images, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
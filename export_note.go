@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/export"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// ExportNoteRequest 导出笔记请求
+type ExportNoteRequest struct {
+	FeedID          string `json:"feed_id" binding:"required"`
+	XsecToken       string `json:"xsec_token" binding:"required"`
+	Dir             string `json:"dir,omitempty"`              // 导出目录，为空则使用默认导出目录
+	IncludeComments bool   `json:"include_comments,omitempty"` // 是否附上评论区（含二级回复）
+	CommentLimit    int    `json:"comment_limit,omitempty"`    // 最多加载的一级评论数，0 用默认值
+}
+
+// ExportNoteResponse 导出笔记响应
+type ExportNoteResponse struct {
+	Path      string   `json:"path"`       // Markdown 文件路径
+	AssetsDir string   `json:"assets_dir"` // 媒体目录，与 .md 同级
+	Assets    []string `json:"assets"`
+	Failed    int      `json:"failed"`   // 下载失败的媒体数，正文里改用远程地址
+	Comments  int      `json:"comments"` // 导出的一级评论数
+}
+
+// ExportNote 把笔记导出为 <dir>/<feed_id>.md，图片存进同级的 <feed_id>.assets 目录。
+// 目录结构可以直接放进 Obsidian 库。
+func (s *XiaohongshuService) ExportNote(ctx context.Context, req *ExportNoteRequest) (*ExportNoteResponse, error) {
+	config := xiaohongshu.DefaultCommentLoadConfig()
+	if req.IncludeComments {
+		config.ClickMoreReplies = true
+		if req.CommentLimit > 0 {
+			config.MaxCommentItems = req.CommentLimit
+		}
+	}
+
+	b := newBrowser()
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	action := xiaohongshu.NewFeedDetailAction(page)
+	detail, err := action.GetFeedDetailWithConfig(ctx, req.FeedID, req.XsecToken, req.IncludeComments, config)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := req.Dir
+	if dir == "" {
+		dir = configs.GetExportsPath()
+	}
+	assetsName := req.FeedID + ".assets"
+	assetsDir := filepath.Join(dir, assetsName)
+
+	d, err := downloader.NewMediaDownloader(assetsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ExportNoteResponse{AssetsDir: assetsDir}
+
+	var assets []export.Asset
+	for _, item := range planNoteMedia(detail.Note.ImageList) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		link := item.URL
+		path, err := saveNoteMediaItem(ctx, d, item, detail.Note.ImageList[item.Index-1])
+		if err != nil {
+			logrus.Warnf("导出笔记媒体失败: feed=%s index=%d type=%s: %v", req.FeedID, item.Index, item.Type, err)
+			resp.Failed++
+		} else {
+			// 链接用相对路径，整个目录搬走后依然能显示
+			link = assetsName + "/" + filepath.Base(path)
+			resp.Assets = append(resp.Assets, path)
+		}
+		assets = append(assets, export.Asset{Index: item.Index, Type: item.Type, Link: link})
+	}
+
+	opts := export.MarkdownOptions{Assets: assets, ExportedAt: time.Now()}
+	if req.IncludeComments {
+		opts.Comments = detail.Comments.List
+		resp.Comments = len(opts.Comments)
+	}
+
+	content, err := export.Markdown(detail.Note, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Path = filepath.Join(dir, req.FeedID+".md")
+	if err := os.WriteFile(resp.Path, content, 0644); err != nil {
+		return nil, fmt.Errorf("写入 Markdown 失败: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ExportCommentsRequest 导出评论请求
+type ExportCommentsRequest struct {
+	FeedID    string `json:"feed_id" binding:"required"`
+	XsecToken string `json:"xsec_token" binding:"required"`
+	Format    string `json:"format,omitempty"` // csv(默认) | xlsx
+	Path      string `json:"path,omitempty"`   // 输出文件路径，为空则写到默认导出目录下的 <feed_id>_comments.<format>
+	Limit     int    `json:"limit,omitempty"`  // 最多加载的一级评论数，0 用默认值
+}
+
+// ExportCommentsResponse 导出评论响应，只给统计和路径，评论本身在文件里
+type ExportCommentsResponse struct {
+	Path     string `json:"path"`
+	Format   string `json:"format"`
+	Rows     int    `json:"rows"`      // 总行数（不含表头）
+	TopLevel int    `json:"top_level"` // 其中一级评论数
+	HasMore  bool   `json:"has_more"`  // 页面上是否还有没加载的评论
+}
+
+// ExportComments 加载笔记评论（含二级回复），摊平后逐行写成 CSV 或 XLSX
+func (s *XiaohongshuService) ExportComments(ctx context.Context, req *ExportCommentsRequest) (*ExportCommentsResponse, error) {
+	format, err := export.ParseSheetFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	path := req.Path
+	if path == "" {
+		path = filepath.Join(configs.GetExportsPath(), fmt.Sprintf("%s_comments.%s", req.FeedID, format))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	config := xiaohongshu.DefaultCommentLoadConfig()
+	config.ClickMoreReplies = true
+	if req.Limit > 0 {
+		config.MaxCommentItems = req.Limit
+	}
+
+	b := newBrowser()
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	action := xiaohongshu.NewFeedDetailAction(page)
+	detail, err := action.GetFeedDetailWithConfig(ctx, req.FeedID, req.XsecToken, true, config)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := export.FlattenComments(detail.Comments.List)
+	if err := writeCommentSheet(path, format, rows); err != nil {
+		return nil, err
+	}
+
+	return &ExportCommentsResponse{
+		Path:     path,
+		Format:   string(format),
+		Rows:     len(rows),
+		TopLevel: len(detail.Comments.List),
+		HasMore:  detail.Comments.HasMore,
+	}, nil
+}
+
+// writeCommentSheet 先写临时文件再改名，写到一半失败不会留下残缺的表格
+func writeCommentSheet(path string, format export.SheetFormat, rows []export.CommentRow) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	defer os.Remove(tmp)
+
+	w, err := export.NewCommentWriter(f, format)
+	if err == nil {
+		for _, row := range rows {
+			if err = w.Write(row); err != nil {
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("写入表格失败: %w", err)
+	}
+
+	return os.Rename(tmp, path)
+}
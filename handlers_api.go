@@ -1,10 +1,13 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/cache"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhscdn"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 
 	"github.com/gin-gonic/gin"
@@ -100,6 +103,29 @@ func (s *AppServer) publishHandler(c *gin.Context) {
 		return
 	}
 
+	if req.Draft {
+		respondSuccess(c, result, "存草稿成功")
+		return
+	}
+	respondSuccess(c, result, "发布成功")
+}
+
+// publishFromFileHandler 从 Markdown 稿件发布
+func (s *AppServer) publishFromFileHandler(c *gin.Context) {
+	var req PublishFromFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.PublishFromFile(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "PUBLISH_FAILED",
+			"发布失败", err.Error())
+		return
+	}
+
 	respondSuccess(c, result, "发布成功")
 }
 
@@ -122,9 +148,29 @@ func (s *AppServer) publishVideoHandler(c *gin.Context) {
 	respondSuccess(c, result, "视频发布成功")
 }
 
+// imageOptionsFromQuery 从 image_format、image_width 查询参数解析封面地址的格式和尺寸
+func imageOptionsFromQuery(c *gin.Context) (xhscdn.Options, error) {
+	width := 0
+	if raw := c.Query("image_width"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return xhscdn.Options{}, fmt.Errorf("image_width 必须是整数: %s", raw)
+		}
+		width = n
+	}
+	return parseImageOptions(c.Query("image_format"), width)
+}
+
 // listFeedsHandler 获取Feeds列表
 func (s *AppServer) listFeedsHandler(c *gin.Context) {
-	result, err := s.xiaohongshuService.ListFeeds(c.Request.Context())
+	images, err := imageOptionsFromQuery(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.ListFeeds(c.Request.Context(), images)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "LIST_FEEDS_FAILED",
 			"获取Feeds列表失败", err.Error())
@@ -136,8 +182,9 @@ func (s *AppServer) listFeedsHandler(c *gin.Context) {
 
 // searchFeedsHandler 搜索Feeds
 func (s *AppServer) searchFeedsHandler(c *gin.Context) {
-	var keyword string
+	var keyword, cacheMode string
 	var filters xiaohongshu.FilterOption
+	var images xhscdn.Options
 
 	switch c.Request.Method {
 	case http.MethodPost:
@@ -150,8 +197,24 @@ func (s *AppServer) searchFeedsHandler(c *gin.Context) {
 		}
 		keyword = searchReq.Keyword
 		filters = searchReq.Filters
+		cacheMode = searchReq.Cache
+		opts, err := parseImageOptions(searchReq.ImageFormat, searchReq.ImageWidth)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", err.Error())
+			return
+		}
+		images = opts
 	default:
 		keyword = c.Query("keyword")
+		cacheMode = c.Query("cache")
+		opts, err := imageOptionsFromQuery(c)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", err.Error())
+			return
+		}
+		images = opts
 	}
 
 	if keyword == "" {
@@ -160,7 +223,14 @@ func (s *AppServer) searchFeedsHandler(c *gin.Context) {
 		return
 	}
 
-	result, err := s.xiaohongshuService.SearchFeeds(c.Request.Context(), keyword, filters)
+	mode, err := cache.ParseMode(cacheMode)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.SearchFeeds(c.Request.Context(), keyword, mode, images, filters)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "SEARCH_FEEDS_FAILED",
 			"搜索Feeds失败", err.Error())
@@ -179,21 +249,31 @@ func (s *AppServer) getFeedDetailHandler(c *gin.Context) {
 		return
 	}
 
-	var result *FeedDetailResponse
-	var err error
+	images, err := parseImageOptions(req.ImageFormat, req.ImageWidth)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+	mode, err := cache.ParseMode(req.Cache)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
 
+	config := xiaohongshu.DefaultCommentLoadConfig()
 	if req.CommentConfig != nil {
-		config := xiaohongshu.CommentLoadConfig{
+		config = xiaohongshu.CommentLoadConfig{
 			ClickMoreReplies:    req.CommentConfig.ClickMoreReplies,
 			MaxRepliesThreshold: req.CommentConfig.MaxRepliesThreshold,
 			MaxCommentItems:     req.CommentConfig.MaxCommentItems,
 			ScrollSpeed:         req.CommentConfig.ScrollSpeed,
 		}
-		result, err = s.xiaohongshuService.GetFeedDetailWithConfig(c.Request.Context(), req.FeedID, req.XsecToken, req.LoadAllComments, config)
-	} else {
-		result, err = s.xiaohongshuService.GetFeedDetail(c.Request.Context(), req.FeedID, req.XsecToken, req.LoadAllComments)
 	}
 
+	result, err := s.xiaohongshuService.GetFeedDetailWithConfig(c.Request.Context(), req.FeedID, req.XsecToken, req.LoadAllComments, config, images, mode)
+
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "GET_FEED_DETAIL_FAILED",
 			"获取Feed详情失败", err.Error())
@@ -212,7 +292,20 @@ func (s *AppServer) userProfileHandler(c *gin.Context) {
 		return
 	}
 
-	result, err := s.xiaohongshuService.UserProfile(c.Request.Context(), req.UserID, req.XsecToken, req.Tab)
+	mode, err := cache.ParseMode(req.Cache)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+	images, err := parseImageOptions(req.ImageFormat, req.ImageWidth)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.UserProfile(c.Request.Context(), req.UserID, req.XsecToken, req.Tab, mode, images)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "GET_USER_PROFILE_FAILED",
 			"获取用户主页失败", err.Error())
@@ -261,6 +354,435 @@ func (s *AppServer) replyCommentHandler(c *gin.Context) {
 	respondSuccess(c, result, result.Message)
 }
 
+// getCommentRepliesHandler 获取单条评论的全部回复
+func (s *AppServer) getCommentRepliesHandler(c *gin.Context) {
+	var req CommentRepliesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.GetCommentReplies(c.Request.Context(), req.FeedID, req.XsecToken, req.CommentID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "GET_COMMENT_REPLIES_FAILED",
+			"获取评论回复失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"data": result}, "获取评论回复成功")
+}
+
+// downloadNoteMediaHandler 下载笔记图片与实况视频
+func (s *AppServer) downloadNoteMediaHandler(c *gin.Context) {
+	var req DownloadNoteMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.DownloadNoteMedia(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "DOWNLOAD_NOTE_MEDIA_FAILED",
+			"下载笔记媒体失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"data": result}, "下载笔记媒体成功")
+}
+
+// downloadVideoHandler 下载视频笔记的视频
+func (s *AppServer) downloadVideoHandler(c *gin.Context) {
+	var req DownloadVideoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.DownloadVideo(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "DOWNLOAD_VIDEO_FAILED",
+			"下载视频失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"data": result}, "下载视频成功")
+}
+
+// getVideoSubtitlesHandler 获取视频字幕
+func (s *AppServer) getVideoSubtitlesHandler(c *gin.Context) {
+	var req VideoSubtitlesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.GetVideoSubtitles(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "GET_SUBTITLES_FAILED",
+			"获取视频字幕失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"data": result}, "获取视频字幕成功")
+}
+
+// exportNoteHandler 导出笔记为 Markdown
+func (s *AppServer) exportNoteHandler(c *gin.Context) {
+	var req ExportNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.ExportNote(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "EXPORT_NOTE_FAILED",
+			"导出笔记失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"data": result}, "导出笔记成功")
+}
+
+// exportCommentsHandler 导出评论为表格
+func (s *AppServer) exportCommentsHandler(c *gin.Context) {
+	var req ExportCommentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.ExportComments(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "EXPORT_COMMENTS_FAILED",
+			"导出评论失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"data": result}, "导出评论成功")
+}
+
+// watchNoteHandler 关注笔记
+func (s *AppServer) watchNoteHandler(c *gin.Context) {
+	var req WatchNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.WatchNote(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "WATCH_NOTE_FAILED",
+			"关注笔记失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"data": result}, "关注笔记成功")
+}
+
+// unwatchNoteHandler 取消关注笔记
+func (s *AppServer) unwatchNoteHandler(c *gin.Context) {
+	feedID := c.Param("feed_id")
+	if err := s.xiaohongshuService.UnwatchNote(feedID); err != nil {
+		respondError(c, http.StatusInternalServerError, "UNWATCH_NOTE_FAILED",
+			"取消关注失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"feed_id": feedID}, "取消关注成功")
+}
+
+// listWatchedNotesHandler 关注列表
+func (s *AppServer) listWatchedNotesHandler(c *gin.Context) {
+	notes, err := s.xiaohongshuService.ListWatchedNotes()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "LIST_WATCHED_NOTES_FAILED",
+			"获取关注列表失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"notes": notes, "count": len(notes)}, "获取关注列表成功")
+}
+
+// noteMetricsHistoryHandler 互动数序列
+func (s *AppServer) noteMetricsHistoryHandler(c *gin.Context) {
+	var sinceHours int
+	if v := c.Query("since_hours"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", "since_hours 需为非负整数")
+			return
+		}
+		sinceHours = n
+	}
+
+	result, err := s.xiaohongshuService.NoteMetricsHistory(c.Param("feed_id"), sinceHours)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "NOTE_METRICS_HISTORY_FAILED",
+			"获取互动数序列失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"data": result}, "获取互动数序列成功")
+}
+
+// saveSearchHandler 保存搜索
+func (s *AppServer) saveSearchHandler(c *gin.Context) {
+	var req SaveSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.SaveSearch(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "SAVE_SEARCH_FAILED",
+			"保存搜索失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"data": result}, "保存搜索成功")
+}
+
+// deleteSavedSearchHandler 删除保存的搜索
+func (s *AppServer) deleteSavedSearchHandler(c *gin.Context) {
+	id := c.Param("id")
+	if err := s.xiaohongshuService.DeleteSavedSearch(id); err != nil {
+		respondError(c, http.StatusInternalServerError, "DELETE_SAVED_SEARCH_FAILED",
+			"删除保存的搜索失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"search_id": id}, "删除保存的搜索成功")
+}
+
+// listSavedSearchesHandler 保存的搜索列表
+func (s *AppServer) listSavedSearchesHandler(c *gin.Context) {
+	searches, err := s.xiaohongshuService.ListSavedSearches()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "LIST_SAVED_SEARCHES_FAILED",
+			"获取保存的搜索失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"searches": searches, "count": len(searches)}, "获取保存的搜索成功")
+}
+
+// searchAlertsHandler 新笔记提醒，GET 用查询参数，POST 用 JSON
+func (s *AppServer) searchAlertsHandler(c *gin.Context) {
+	var req SearchAlertsRequest
+	if c.Request.Method == http.MethodPost {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", err.Error())
+			return
+		}
+	} else {
+		req.SearchID = c.Query("search_id")
+		req.UnreadOnly = c.Query("unread_only") == "true"
+		req.MarkRead = c.Query("mark_read") == "true"
+		if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+			req.Limit = limit
+		}
+	}
+
+	result, err := s.xiaohongshuService.SearchAlerts(&req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "SEARCH_ALERTS_FAILED",
+			"获取新笔记提醒失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"data": result}, "获取新笔记提醒成功")
+}
+
+// listDraftsHandler 草稿列表
+func (s *AppServer) listDraftsHandler(c *gin.Context) {
+	list, err := s.xiaohongshuService.ListDrafts()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "LIST_DRAFTS_FAILED",
+			"获取草稿列表失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"drafts": list, "count": len(list)}, "获取草稿列表成功")
+}
+
+// publishDraftHandler 发布草稿，body 可选，只有 schedule_at
+func (s *AppServer) publishDraftHandler(c *gin.Context) {
+	var req struct {
+		ScheduleAt string `json:"schedule_at,omitempty"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", err.Error())
+			return
+		}
+	}
+
+	result, err := s.xiaohongshuService.PublishDraft(c.Request.Context(), c.Param("id"), req.ScheduleAt)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "PUBLISH_DRAFT_FAILED",
+			"发布草稿失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "发布草稿成功")
+}
+
+// deleteDraftHandler 删除草稿
+func (s *AppServer) deleteDraftHandler(c *gin.Context) {
+	id := c.Param("id")
+	if err := s.xiaohongshuService.DeleteDraft(id); err != nil {
+		respondError(c, http.StatusInternalServerError, "DELETE_DRAFT_FAILED",
+			"删除草稿失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"draft_id": id}, "删除草稿成功")
+}
+
+// deleteNoteHandler 删除自己的笔记，需带 ?confirm=true
+func (s *AppServer) deleteNoteHandler(c *gin.Context) {
+	feedID := c.Param("feed_id")
+	confirm := c.Query("confirm") == "true"
+
+	deleted, err := s.xiaohongshuService.DeleteNote(c.Request.Context(), feedID, confirm)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if !confirm {
+			status = http.StatusBadRequest
+		}
+		respondError(c, status, "DELETE_NOTE_FAILED", "删除笔记失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, deleted, "删除笔记成功")
+}
+
+// editNoteHandler 编辑自己的笔记，body 只填要改的字段
+func (s *AppServer) editNoteHandler(c *gin.Context) {
+	var req EditNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+	req.FeedID = c.Param("feed_id")
+
+	result, err := s.xiaohongshuService.EditNote(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "EDIT_NOTE_FAILED",
+			"编辑笔记失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, result, "编辑笔记成功")
+}
+
+// schedulePublishHandler 安排本地定时发布
+func (s *AppServer) schedulePublishHandler(c *gin.Context) {
+	var req SchedulePublishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	job, err := s.xiaohongshuService.SchedulePublish(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "SCHEDULE_PUBLISH_FAILED",
+			"安排定时发布失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, job, "安排定时发布成功")
+}
+
+// listScheduledHandler 定时任务列表
+func (s *AppServer) listScheduledHandler(c *gin.Context) {
+	list, err := s.xiaohongshuService.ListScheduled()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "LIST_SCHEDULED_FAILED",
+			"获取定时任务列表失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"jobs": list, "count": len(list)}, "获取定时任务列表成功")
+}
+
+// cancelScheduledHandler 取消定时任务
+func (s *AppServer) cancelScheduledHandler(c *gin.Context) {
+	job, err := s.xiaohongshuService.CancelScheduled(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "CANCEL_SCHEDULED_FAILED",
+			"取消定时任务失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, job, "取消定时任务成功")
+}
+
+// rescheduleHandler 修改定时任务的发布时间
+func (s *AppServer) rescheduleHandler(c *gin.Context) {
+	var req RescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	job, err := s.xiaohongshuService.Reschedule(c.Param("id"), req.PublishAt)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "RESCHEDULE_FAILED",
+			"修改发布时间失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, job, "修改发布时间成功")
+}
+
+// lintPostHandler 发布前检查，不打开浏览器
+func (s *AppServer) lintPostHandler(c *gin.Context) {
+	var req LintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	report, err := s.xiaohongshuService.LintPost(&req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "LINT_FAILED",
+			"发布前检查失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, report, "发布前检查完成")
+}
+
+// listCollectionsHandler 合集列表
+func (s *AppServer) listCollectionsHandler(c *gin.Context) {
+	list, err := s.xiaohongshuService.ListCollections(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "LIST_COLLECTIONS_FAILED",
+			"获取合集列表失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"collections": list, "count": len(list)}, "获取合集列表成功")
+}
+
 // likeFeedHandler 点赞/取消点赞
 func (s *AppServer) likeFeedHandler(c *gin.Context) {
 	var req LikeFeedRequest
@@ -324,8 +846,15 @@ func healthHandler(c *gin.Context) {
 
 // myProfileHandler 我的信息
 func (s *AppServer) myProfileHandler(c *gin.Context) {
+	images, err := imageOptionsFromQuery(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
 	// 获取当前登录用户信息
-	result, err := s.xiaohongshuService.GetMyProfile(c.Request.Context(), c.Query("tab"))
+	result, err := s.xiaohongshuService.GetMyProfile(c.Request.Context(), c.Query("tab"), images)
 	if err != nil {
 		respondError(c, http.StatusInternalServerError, "GET_MY_PROFILE_FAILED",
 			"获取我的主页失败", err.Error())
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/lint"
+	"github.com/xpzouying/xiaohongshu-mcp/publishing"
+)
+
+// LintRequest 发布前检查请求
+type LintRequest struct {
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// LintPost 检查标题、正文和话题，不打开浏览器
+func (s *XiaohongshuService) LintPost(req *LintRequest) (*lint.Report, error) {
+	return publishing.Lint(req.Title, req.Content, req.Tags)
+}
@@ -10,6 +10,9 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/cache"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/imageprep"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/lint"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
@@ -27,6 +30,19 @@ func parseVisibility(args map[string]interface{}) string {
 	return ""
 }
 
+// options 转成预处理选项，未填时返回 nil
+func (a *ImagePrepArgs) options() *imageprep.Options {
+	if a == nil {
+		return nil
+	}
+	return &imageprep.Options{
+		Aspect:  imageprep.Aspect(a.Aspect),
+		Fit:     imageprep.Fit(a.Fit),
+		MaxSide: a.MaxSide,
+		Quality: a.Quality,
+	}
+}
+
 // handleCheckLoginStatus 处理检查登录状态
 func (s *AppServer) handleCheckLoginStatus(ctx context.Context) *MCPToolResult {
 	logrus.Info("MCP: 检查登录状态")
@@ -154,8 +170,12 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 	visibility := parseVisibility(args)
 
 	isOriginal, _ := args["is_original"].(bool)
+	draft, _ := args["draft"].(bool)
+	location, _ := args["location"].(string)
+	collection, _ := args["collection"].(string)
+	createCollection, _ := args["create_collection"].(bool)
 
-	logrus.Infof("MCP: 发布内容 - 标题: %s, 图片数量: %d, 标签数量: %d, 定时: %s, 原创: %v, visibility: %s, 商品: %v", title, len(imagePaths), len(tags), scheduleAt, isOriginal, visibility, products)
+	logrus.Infof("MCP: 发布内容 - 标题: %s, 图片数量: %d, 标签数量: %d, 定时: %s, 原创: %v, visibility: %s, 商品: %v, 草稿: %v", title, len(imagePaths), len(tags), scheduleAt, isOriginal, visibility, products, draft)
 
 	req := &PublishRequest{
 		Title:      title,
@@ -166,6 +186,14 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 		IsOriginal: isOriginal,
 		Visibility: visibility,
 		Products:   products,
+		Location:   location,
+		Draft:      draft,
+
+		Collection:       collection,
+		CreateCollection: createCollection,
+	}
+	if prep, ok := args["preprocess"].(*ImagePrepArgs); ok {
+		req.Preprocess = prep.options()
 	}
 
 	result, err := s.xiaohongshuService.PublishContent(ctx, req)
@@ -179,7 +207,11 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 		}
 	}
 
-	resultText := fmt.Sprintf("内容发布成功: %+v", result)
+	resultText := fmt.Sprintf("内容发布成功: %+v", result) + publishedNoteText(result.NoteID, result.XsecToken, result.URL)
+	if draft {
+		resultText = fmt.Sprintf("已存草稿，草稿ID: %s，可用 publish_draft 发布: %+v", result.DraftID, result)
+	}
+	resultText += mentionWarning(result.FailedMentions) + lintWarning(result.Lint)
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
@@ -216,7 +248,7 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "发布失败: 缺少本地视频文件路径",
+				Text: "发布失败: 缺少视频文件路径或URL",
 			}},
 			IsError: true,
 		}
@@ -225,6 +257,15 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 	scheduleAt, _ := args["schedule_at"].(string)
 	visibility := parseVisibility(args)
 
+	location, _ := args["location"].(string)
+	collection, _ := args["collection"].(string)
+	createCollection, _ := args["create_collection"].(bool)
+	cover, _ := args["cover"].(string)
+	var coverTime *float64
+	if v, ok := args["cover_time"].(float64); ok {
+		coverTime = &v
+	}
+
 	logrus.Infof("MCP: 发布视频 - 标题: %s, 标签数量: %d, 定时: %s, visibility: %s, 商品: %v", title, len(tags), scheduleAt, visibility, products)
 
 	req := &PublishVideoRequest{
@@ -235,6 +276,12 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 		ScheduleAt: scheduleAt,
 		Visibility: visibility,
 		Products:   products,
+		Location:   location,
+		Cover:      cover,
+		CoverTime:  coverTime,
+
+		Collection:       collection,
+		CreateCollection: createCollection,
 	}
 
 	result, err := s.xiaohongshuService.PublishVideo(ctx, req)
@@ -248,7 +295,8 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 		}
 	}
 
-	resultText := fmt.Sprintf("视频发布成功: %+v", result)
+	resultText := fmt.Sprintf("视频发布成功: %+v", result) + publishedNoteText(result.NoteID, result.XsecToken, result.URL) +
+		mentionWarning(result.FailedMentions) + lintWarning(result.Lint)
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
@@ -258,10 +306,21 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 }
 
 // handleListFeeds 处理获取Feeds列表
-func (s *AppServer) handleListFeeds(ctx context.Context) *MCPToolResult {
+func (s *AppServer) handleListFeeds(ctx context.Context, args ListFeedsArgs) *MCPToolResult {
 	logrus.Info("MCP: 获取Feeds列表")
 
-	result, err := s.xiaohongshuService.ListFeeds(ctx)
+	images, err := parseImageOptions(args.ImageFormat, args.ImageWidth)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "获取Feeds列表失败: " + err.Error(),
+			}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.ListFeeds(ctx, images)
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
@@ -315,7 +374,28 @@ func (s *AppServer) handleSearchFeeds(ctx context.Context, args SearchFeedsArgs)
 		Location:    args.Filters.Location,
 	}
 
-	result, err := s.xiaohongshuService.SearchFeeds(ctx, args.Keyword, filter)
+	mode, err := cache.ParseMode(args.Cache)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "搜索Feeds失败: " + err.Error(),
+			}},
+			IsError: true,
+		}
+	}
+	images, err := parseImageOptions(args.ImageFormat, args.ImageWidth)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "搜索Feeds失败: " + err.Error(),
+			}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.SearchFeeds(ctx, args.Keyword, mode, images, filter)
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
@@ -429,9 +509,34 @@ func (s *AppServer) handleGetFeedDetail(ctx context.Context, args map[string]any
 		config.ScrollSpeed = raw
 	}
 
-	logrus.Infof("MCP: 获取Feed详情 - Feed ID: %s, loadAllComments=%v, config=%+v", feedID, loadAll, config)
+	imageFormat, _ := args["image_format"].(string)
+	imageWidth, _ := args["image_width"].(int)
+	images, err := parseImageOptions(imageFormat, imageWidth)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "获取Feed详情失败: " + err.Error(),
+			}},
+			IsError: true,
+		}
+	}
+
+	cacheMode, _ := args["cache"].(string)
+	mode, err := cache.ParseMode(cacheMode)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "获取Feed详情失败: " + err.Error(),
+			}},
+			IsError: true,
+		}
+	}
+
+	logrus.Infof("MCP: 获取Feed详情 - Feed ID: %s, loadAllComments=%v, config=%+v, images=%+v, cache=%s", feedID, loadAll, config, images, mode)
 
-	result, err := s.xiaohongshuService.GetFeedDetailWithConfig(ctx, feedID, xsecToken, loadAll, config)
+	result, err := s.xiaohongshuService.GetFeedDetailWithConfig(ctx, feedID, xsecToken, loadAll, config, images, mode)
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
@@ -491,7 +596,32 @@ func (s *AppServer) handleUserProfile(ctx context.Context, args map[string]any)
 
 	tab, _ := args["tab"].(string)
 
-	result, err := s.xiaohongshuService.UserProfile(ctx, userID, xsecToken, tab)
+	cacheMode, _ := args["cache"].(string)
+	mode, err := cache.ParseMode(cacheMode)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "获取用户主页失败: " + err.Error(),
+			}},
+			IsError: true,
+		}
+	}
+
+	imageFormat, _ := args["image_format"].(string)
+	imageWidth, _ := args["image_width"].(int)
+	images, err := parseImageOptions(imageFormat, imageWidth)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "获取用户主页失败: " + err.Error(),
+			}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.UserProfile(ctx, userID, xsecToken, tab, mode, images)
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
@@ -643,7 +773,7 @@ func (s *AppServer) handlePostComment(ctx context.Context, args map[string]inter
 		}
 	}
 
-	resultText := fmt.Sprintf("评论发表成功 - Feed ID: %s", result.FeedID)
+	resultText := fmt.Sprintf("评论发表成功 - Feed ID: %s", result.FeedID) + mentionWarning(result.FailedMentions)
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
@@ -714,7 +844,8 @@ func (s *AppServer) handleReplyComment(ctx context.Context, args map[string]inte
 		}
 	}
 
-	responseText := fmt.Sprintf("评论回复成功 - Feed ID: %s, Comment ID: %s, User ID: %s", result.FeedID, result.TargetCommentID, result.TargetUserID)
+	responseText := fmt.Sprintf("评论回复成功 - Feed ID: %s, Comment ID: %s, User ID: %s", result.FeedID, result.TargetCommentID, result.TargetUserID) +
+		mentionWarning(result.FailedMentions)
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
@@ -723,11 +854,635 @@ func (s *AppServer) handleReplyComment(ctx context.Context, args map[string]inte
 	}
 }
 
+// handleGetCommentReplies 获取单条评论的全部回复
+func (s *AppServer) handleGetCommentReplies(ctx context.Context, args CommentRepliesArgs) *MCPToolResult {
+	logrus.Infof("MCP: 获取评论回复 feed=%s comment=%s", args.FeedID, args.CommentID)
+
+	if args.FeedID == "" || args.XsecToken == "" || args.CommentID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取评论回复失败: 缺少feed_id、xsec_token或comment_id参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.GetCommentReplies(ctx, args.FeedID, args.XsecToken, args.CommentID)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取评论回复失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "获取评论回复")
+}
+
+// handleDownloadNoteMedia 下载笔记图片与实况视频
+func (s *AppServer) handleDownloadNoteMedia(ctx context.Context, args DownloadNoteMediaArgs) *MCPToolResult {
+	logrus.Infof("MCP: 下载笔记媒体 feed=%s dir=%s", args.FeedID, args.Dir)
+
+	if args.FeedID == "" || args.XsecToken == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "下载笔记媒体失败: 缺少feed_id或xsec_token参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.DownloadNoteMedia(ctx, &DownloadNoteMediaRequest{
+		FeedID:    args.FeedID,
+		XsecToken: args.XsecToken,
+		Dir:       args.Dir,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "下载笔记媒体失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "下载笔记媒体")
+}
+
+// handleDownloadVideo 下载视频笔记的视频
+func (s *AppServer) handleDownloadVideo(ctx context.Context, args DownloadVideoArgs) *MCPToolResult {
+	logrus.Infof("MCP: 下载视频 feed=%s policy=%s codec=%s", args.FeedID, args.Policy, args.Codec)
+
+	if args.FeedID == "" || args.XsecToken == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "下载视频失败: 缺少feed_id或xsec_token参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.DownloadVideo(ctx, &DownloadVideoRequest{
+		FeedID:    args.FeedID,
+		XsecToken: args.XsecToken,
+		Policy:    args.Policy,
+		Codec:     args.Codec,
+		Dir:       args.Dir,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "下载视频失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "下载视频")
+}
+
+// handleGetVideoSubtitles 获取视频字幕
+func (s *AppServer) handleGetVideoSubtitles(ctx context.Context, args VideoSubtitlesArgs) *MCPToolResult {
+	logrus.Infof("MCP: 获取视频字幕 feed=%s language=%s format=%s", args.FeedID, args.Language, args.Format)
+
+	if args.FeedID == "" || args.XsecToken == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取视频字幕失败: 缺少feed_id或xsec_token参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.GetVideoSubtitles(ctx, &VideoSubtitlesRequest{
+		FeedID:          args.FeedID,
+		XsecToken:       args.XsecToken,
+		Language:        args.Language,
+		Format:          args.Format,
+		StripTimestamps: args.StripTimestamps,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取视频字幕失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "获取视频字幕")
+}
+
+// handleExportNote 导出笔记为 Markdown
+func (s *AppServer) handleExportNote(ctx context.Context, args ExportNoteArgs) *MCPToolResult {
+	logrus.Infof("MCP: 导出笔记 feed=%s include_comments=%v", args.FeedID, args.IncludeComments)
+
+	if args.FeedID == "" || args.XsecToken == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "导出笔记失败: 缺少feed_id或xsec_token参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.ExportNote(ctx, &ExportNoteRequest{
+		FeedID:          args.FeedID,
+		XsecToken:       args.XsecToken,
+		Dir:             args.Dir,
+		IncludeComments: args.IncludeComments,
+		CommentLimit:    args.CommentLimit,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "导出笔记失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "导出笔记")
+}
+
+// handleExportComments 导出评论为表格
+func (s *AppServer) handleExportComments(ctx context.Context, args ExportCommentsArgs) *MCPToolResult {
+	logrus.Infof("MCP: 导出评论 feed=%s format=%s", args.FeedID, args.Format)
+
+	if args.FeedID == "" || args.XsecToken == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "导出评论失败: 缺少feed_id或xsec_token参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.ExportComments(ctx, &ExportCommentsRequest{
+		FeedID:    args.FeedID,
+		XsecToken: args.XsecToken,
+		Format:    args.Format,
+		Path:      args.Path,
+		Limit:     args.Limit,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "导出评论失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "导出评论")
+}
+
+// handleWatchNote 关注笔记
+func (s *AppServer) handleWatchNote(ctx context.Context, args WatchNoteArgs) *MCPToolResult {
+	logrus.Infof("MCP: 关注笔记 feed=%s label=%s", args.FeedID, args.Label)
+
+	if args.FeedID == "" || args.XsecToken == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "关注笔记失败: 缺少feed_id或xsec_token参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.WatchNote(ctx, &WatchNoteRequest{
+		FeedID:    args.FeedID,
+		XsecToken: args.XsecToken,
+		Label:     args.Label,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "关注笔记失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "关注笔记")
+}
+
+// handleUnwatchNote 取消关注笔记
+func (s *AppServer) handleUnwatchNote(_ context.Context, args UnwatchNoteArgs) *MCPToolResult {
+	logrus.Infof("MCP: 取消关注笔记 feed=%s", args.FeedID)
+
+	if args.FeedID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "取消关注失败: 缺少feed_id参数"}},
+			IsError: true,
+		}
+	}
+
+	if err := s.xiaohongshuService.UnwatchNote(args.FeedID); err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "取消关注失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("已取消关注笔记 %s，已采集的序列保留", args.FeedID)}},
+	}
+}
+
+// handleListWatchedNotes 关注列表
+func (s *AppServer) handleListWatchedNotes(_ context.Context) *MCPToolResult {
+	logrus.Info("MCP: 获取关注列表")
+
+	notes, err := s.xiaohongshuService.ListWatchedNotes()
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取关注列表失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(map[string]any{"notes": notes, "count": len(notes)}, "获取关注列表")
+}
+
+// handleNoteMetricsHistory 互动数序列
+func (s *AppServer) handleNoteMetricsHistory(_ context.Context, args NoteMetricsHistoryArgs) *MCPToolResult {
+	logrus.Infof("MCP: 获取互动数序列 feed=%s since_hours=%d", args.FeedID, args.SinceHours)
+
+	if args.FeedID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取互动数序列失败: 缺少feed_id参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.NoteMetricsHistory(args.FeedID, args.SinceHours)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取互动数序列失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "获取互动数序列")
+}
+
+// handleSaveSearch 保存搜索
+func (s *AppServer) handleSaveSearch(ctx context.Context, args SaveSearchArgs) *MCPToolResult {
+	logrus.Infof("MCP: 保存搜索 keyword=%s", args.Keyword)
+
+	if args.Keyword == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "保存搜索失败: 缺少keyword参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.SaveSearch(ctx, &SaveSearchRequest{
+		Keyword: args.Keyword,
+		Filters: xiaohongshu.FilterOption{
+			SortBy:      args.Filters.SortBy,
+			NoteType:    args.Filters.NoteType,
+			PublishTime: args.Filters.PublishTime,
+			SearchScope: args.Filters.SearchScope,
+			Location:    args.Filters.Location,
+		},
+		Webhook: args.Webhook,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "保存搜索失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "保存搜索")
+}
+
+// handleDeleteSavedSearch 删除保存的搜索
+func (s *AppServer) handleDeleteSavedSearch(_ context.Context, args DeleteSavedSearchArgs) *MCPToolResult {
+	logrus.Infof("MCP: 删除保存的搜索 id=%s", args.SearchID)
+
+	if args.SearchID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "删除保存的搜索失败: 缺少search_id参数"}},
+			IsError: true,
+		}
+	}
+
+	if err := s.xiaohongshuService.DeleteSavedSearch(args.SearchID); err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "删除保存的搜索失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("已删除保存的搜索 %s，已有提醒保留", args.SearchID)}},
+	}
+}
+
+// handleListSavedSearches 保存的搜索列表
+func (s *AppServer) handleListSavedSearches(_ context.Context) *MCPToolResult {
+	logrus.Info("MCP: 获取保存的搜索")
+
+	searches, err := s.xiaohongshuService.ListSavedSearches()
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取保存的搜索失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(map[string]any{"searches": searches, "count": len(searches)}, "获取保存的搜索")
+}
+
+// handleSearchAlerts 查询新笔记提醒
+func (s *AppServer) handleSearchAlerts(_ context.Context, args SearchAlertsArgs) *MCPToolResult {
+	logrus.Infof("MCP: 获取新笔记提醒 search_id=%s unread_only=%v", args.SearchID, args.UnreadOnly)
+
+	result, err := s.xiaohongshuService.SearchAlerts(&SearchAlertsRequest{
+		SearchID:   args.SearchID,
+		UnreadOnly: args.UnreadOnly,
+		Limit:      args.Limit,
+		MarkRead:   args.MarkRead,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取新笔记提醒失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "获取新笔记提醒")
+}
+
+// handleListDrafts 草稿列表
+func (s *AppServer) handleListDrafts(_ context.Context) *MCPToolResult {
+	logrus.Info("MCP: 获取草稿列表")
+
+	list, err := s.xiaohongshuService.ListDrafts()
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取草稿列表失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(map[string]any{"drafts": list, "count": len(list)}, "获取草稿列表")
+}
+
+// handlePublishDraft 发布草稿
+func (s *AppServer) handlePublishDraft(ctx context.Context, args PublishDraftArgs) *MCPToolResult {
+	logrus.Infof("MCP: 发布草稿 id=%s schedule_at=%s", args.DraftID, args.ScheduleAt)
+
+	if args.DraftID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "发布草稿失败: 缺少draft_id参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.PublishDraft(ctx, args.DraftID, args.ScheduleAt)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "发布草稿失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "发布草稿")
+}
+
+// handleDeleteDraft 删除草稿
+func (s *AppServer) handleDeleteDraft(_ context.Context, args DraftIDArgs) *MCPToolResult {
+	logrus.Infof("MCP: 删除草稿 id=%s", args.DraftID)
+
+	if args.DraftID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "删除草稿失败: 缺少draft_id参数"}},
+			IsError: true,
+		}
+	}
+
+	if err := s.xiaohongshuService.DeleteDraft(args.DraftID); err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "删除草稿失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("已删除草稿 %s", args.DraftID)}},
+	}
+}
+
+// handlePublishFromFile 从 Markdown 稿件发布
+func (s *AppServer) handlePublishFromFile(ctx context.Context, args PublishFromFileArgs) *MCPToolResult {
+	logrus.Infof("MCP: 从稿件发布 path=%s draft=%v dry_run=%v", args.Path, args.Draft, args.DryRun)
+
+	if args.Path == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "从稿件发布失败: 缺少path参数"}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.PublishFromFile(ctx, &PublishFromFileRequest{
+		Path:   args.Path,
+		Draft:  args.Draft,
+		DryRun: args.DryRun,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "从稿件发布失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "从稿件发布")
+}
+
+// mentionWarning 有 @ 用户没选中时附在结果后面提醒，全部选中返回空串
+func mentionWarning(failed []string) string {
+	if len(failed) == 0 {
+		return ""
+	}
+	return "\n⚠️ 以下 @ 用户未在联想列表中找到，已按普通文本发出: " + strings.Join(failed, "、")
+}
+
+// lintWarning 发布前检查没有拦下的问题附在结果后面，没有问题返回空串
+func lintWarning(issues []lint.Issue) string {
+	if len(issues) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n⚠️ 发布前检查提示:")
+	for _, i := range issues {
+		fmt.Fprintf(&b, "\n- [%s] %s", i.Severity, i.Message)
+	}
+	return b.String()
+}
+
+// handleDeleteNote 删除自己的笔记
+func (s *AppServer) handleDeleteNote(ctx context.Context, args DeleteNoteArgs) *MCPToolResult {
+	logrus.Infof("MCP: 删除笔记 feed_id=%s confirm=%v", args.FeedID, args.Confirm)
+
+	deleted, err := s.xiaohongshuService.DeleteNote(ctx, args.FeedID, args.Confirm)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "删除笔记失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("已删除笔记 %s「%s」", deleted.NoteID, deleted.Title)}},
+	}
+}
+
+// handleEditNote 编辑自己的笔记
+func (s *AppServer) handleEditNote(ctx context.Context, args EditNoteArgs) *MCPToolResult {
+	logrus.Infof("MCP: 编辑笔记 feed_id=%s", args.FeedID)
+
+	result, err := s.xiaohongshuService.EditNote(ctx, &EditNoteRequest{
+		FeedID:     args.FeedID,
+		Title:      args.Title,
+		Content:    args.Content,
+		Tags:       args.Tags,
+		Visibility: args.Visibility,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "编辑笔记失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(result, "编辑笔记")
+}
+
+// handleSchedulePublish 安排本地定时发布
+func (s *AppServer) handleSchedulePublish(ctx context.Context, args SchedulePublishArgs) *MCPToolResult {
+	logrus.Infof("MCP: 安排定时发布 title=%s publish_at=%s", args.Title, args.PublishAt)
+
+	job, err := s.xiaohongshuService.SchedulePublish(ctx, &SchedulePublishRequest{
+		PublishRequest: PublishRequest{
+			Title:      args.Title,
+			Content:    args.Content,
+			Images:     args.Images,
+			Tags:       args.Tags,
+			IsOriginal: args.IsOriginal,
+			Visibility: args.Visibility,
+			Products:   args.Products,
+			Location:   args.Location,
+			Preprocess: args.Preprocess.options(),
+
+			Collection:       args.Collection,
+			CreateCollection: args.CreateCollection,
+		},
+		PublishAt: args.PublishAt,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "安排定时发布失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(job, "安排定时发布")
+}
+
+// handleListScheduledPublishes 定时任务列表
+func (s *AppServer) handleListScheduledPublishes(_ context.Context) *MCPToolResult {
+	logrus.Info("MCP: 获取定时任务列表")
+
+	list, err := s.xiaohongshuService.ListScheduled()
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取定时任务列表失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(map[string]any{"jobs": list, "count": len(list)}, "获取定时任务列表")
+}
+
+// handleCancelScheduledPublish 取消定时任务
+func (s *AppServer) handleCancelScheduledPublish(_ context.Context, args ScheduleIDArgs) *MCPToolResult {
+	logrus.Infof("MCP: 取消定时任务 id=%s", args.ScheduleID)
+
+	if args.ScheduleID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "取消定时任务失败: 缺少schedule_id参数"}},
+			IsError: true,
+		}
+	}
+
+	job, err := s.xiaohongshuService.CancelScheduled(args.ScheduleID)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "取消定时任务失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(job, "取消定时任务")
+}
+
+// handleReschedulePublish 修改定时任务的发布时间
+func (s *AppServer) handleReschedulePublish(_ context.Context, args RescheduleArgs) *MCPToolResult {
+	logrus.Infof("MCP: 修改定时任务 id=%s publish_at=%s", args.ScheduleID, args.PublishAt)
+
+	if args.ScheduleID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "修改发布时间失败: 缺少schedule_id参数"}},
+			IsError: true,
+		}
+	}
+
+	job, err := s.xiaohongshuService.Reschedule(args.ScheduleID, args.PublishAt)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "修改发布时间失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(job, "修改发布时间")
+}
+
+// handleLintPost 发布前检查
+func (s *AppServer) handleLintPost(_ context.Context, args LintPostArgs) *MCPToolResult {
+	logrus.Infof("MCP: 发布前检查 title=%s", args.Title)
+
+	report, err := s.xiaohongshuService.LintPost(&LintRequest{
+		Title:   args.Title,
+		Content: args.Content,
+		Tags:    args.Tags,
+	})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "发布前检查失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(report, "发布前检查")
+}
+
+// publishedNoteText 发布结果里单独列出笔记 ID 和 xsec_token，方便接着调用 get_feed_detail 等工具
+func publishedNoteText(noteID, xsecToken, url string) string {
+	if noteID == "" {
+		return "\n⚠️ 笔记已发布，但未能从创作中心笔记列表获取笔记ID，可稍后用 get_my_profile 查看"
+	}
+	return fmt.Sprintf("\n笔记ID: %s\nxsec_token: %s\n链接: %s", noteID, xsecToken, url)
+}
+
+// handleListCollections 合集列表
+func (s *AppServer) handleListCollections(ctx context.Context) *MCPToolResult {
+	logrus.Info("MCP: 获取合集列表")
+
+	list, err := s.xiaohongshuService.ListCollections(ctx)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "获取合集列表失败: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	return marshalMCPResult(map[string]any{"collections": list, "count": len(list)}, "获取合集列表")
+}
+
 // handleGetMyProfile 获取当前登录用户主页
-func (s *AppServer) handleGetMyProfile(ctx context.Context, tab string) *MCPToolResult {
-	logrus.Infof("MCP: 获取我的主页 tab=%s", tab)
+func (s *AppServer) handleGetMyProfile(ctx context.Context, args MyProfileArgs) *MCPToolResult {
+	logrus.Infof("MCP: 获取我的主页 tab=%s", args.Tab)
+
+	images, err := parseImageOptions(args.ImageFormat, args.ImageWidth)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "获取我的主页失败: " + err.Error(),
+			}},
+			IsError: true,
+		}
+	}
 
-	result, err := s.xiaohongshuService.GetMyProfile(ctx, tab)
+	result, err := s.xiaohongshuService.GetMyProfile(ctx, args.Tab, images)
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
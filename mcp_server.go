@@ -17,31 +17,60 @@ func boolPtr(b bool) *bool { return &b }
 
 // PublishContentArgs 发布内容的参数
 type PublishContentArgs struct {
-	Title      string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
-	Content    string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
-	Images     []string `json:"images" jsonschema:"图片路径列表（至少需要1张图片）。支持两种方式：1. HTTP/HTTPS图片链接（自动下载）；2. 本地图片绝对路径（推荐，如:/Users/user/image.jpg）"`
-	Tags       []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
-	ScheduleAt string   `json:"schedule_at,omitempty" jsonschema:"定时发布时间（可选），ISO8601格式如 2024-01-20T10:30:00+08:00，支持1小时至14天内。不填则立即发布"`
-	IsOriginal bool     `json:"is_original,omitempty" jsonschema:"是否声明原创（可选），true为声明原创，false或不填则不声明"`
-	Visibility string   `json:"visibility,omitempty" jsonschema:"可见范围（可选），支持: 公开可见(默认)、仅自己可见、仅互关好友可见。不填则默认公开可见"`
-	Products   []string `json:"products,omitempty" jsonschema:"商品关键词列表（可选），用于绑定带货商品。填写商品名称或商品ID，系统会自动搜索并选择第一个匹配结果。需账号已开通商品功能。示例: [面膜, 防晒霜SPF50]"`
+	Title      string         `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
+	Content    string         `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可。用 @{昵称} @ 用户，如 感谢 @{小红薯} 的推荐"`
+	Images     []string       `json:"images" jsonschema:"图片路径列表（至少需要1张图片）。支持两种方式：1. HTTP/HTTPS图片链接（自动下载）；2. 本地图片绝对路径（推荐，如:/Users/user/image.jpg）"`
+	Tags       []string       `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	ScheduleAt string         `json:"schedule_at,omitempty" jsonschema:"定时发布时间（可选），ISO8601格式如 2024-01-20T10:30:00+08:00，支持1小时至14天内。不填则立即发布"`
+	IsOriginal bool           `json:"is_original,omitempty" jsonschema:"是否声明原创（可选），true为声明原创，false或不填则不声明"`
+	Visibility string         `json:"visibility,omitempty" jsonschema:"可见范围（可选），支持: 公开可见(默认)、仅自己可见、仅互关好友可见。不填则默认公开可见"`
+	Products   []string       `json:"products,omitempty" jsonschema:"商品关键词列表（可选），用于绑定带货商品。填写商品名称或商品ID，系统会自动搜索并选择第一个匹配结果。需账号已开通商品功能。示例: [面膜, 防晒霜SPF50]"`
+	Location   string         `json:"location,omitempty" jsonschema:"地点（可选），在发布页「添加地点」中搜索并选中最匹配的地点，如 星巴克(国贸店)。没有足够确定的匹配时发布失败并返回候选列表"`
+	Draft      bool           `json:"draft,omitempty" jsonschema:"只存草稿不发布（可选）。草稿只保存在本服务本地，不进创作中心草稿箱，返回 draft_id，之后用 publish_draft 发布。不能与 schedule_at 同时使用"`
+	Preprocess *ImagePrepArgs `json:"preprocess,omitempty" jsonschema:"上传前的图片预处理（可选）。开启后统一转成 JPEG、去掉 EXIF/GPS 元数据、按拍摄方向摆正、缩小过大的图，并把整组图片统一到同一比例。不填则原样上传"`
+
+	Collection       string `json:"collection,omitempty" jsonschema:"合集名称（可选），发布时把笔记加入该合集。已有合集可用 list_collections 查看；合集不存在时发布失败并返回已有合集，除非 create_collection=true"`
+	CreateCollection bool   `json:"create_collection,omitempty" jsonschema:"合集不存在时自动新建（可选），需同时指定 collection"`
+}
+
+// ImagePrepArgs 图片预处理参数
+type ImagePrepArgs struct {
+	Aspect  string `json:"aspect,omitempty" jsonschema:"统一比例: 3:4|1:1|4:3。不填按第一张图的比例（限制在 3:4 到 4:3 之间）"`
+	Fit     string `json:"fit,omitempty" jsonschema:"比例不一致时: pad(默认,补白边不丢内容)|crop(居中裁剪)"`
+	MaxSide int    `json:"max_side,omitempty" jsonschema:"长边像素上限，默认4096，范围512-8192"`
+	Quality int    `json:"quality,omitempty" jsonschema:"JPEG 质量 1-100，默认90"`
 }
 
 // PublishVideoArgs 发布视频的参数（仅支持本地单个视频文件）
 type PublishVideoArgs struct {
 	Title      string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
-	Content    string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
-	Video      string   `json:"video" jsonschema:"本地视频绝对路径（仅支持单个视频文件，如:/Users/user/video.mp4）"`
+	Content    string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可。用 @{昵称} @ 用户，如 感谢 @{小红薯} 的推荐"`
+	Video      string   `json:"video" jsonschema:"视频文件（仅支持单个视频），本地绝对路径如 /Users/user/video.mp4，或 http(s) URL（会先下载到本地，发布后删除）"`
 	Tags       []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
 	ScheduleAt string   `json:"schedule_at,omitempty" jsonschema:"定时发布时间（可选），ISO8601格式如 2024-01-20T10:30:00+08:00，支持1小时至14天内。不填则立即发布"`
 	Visibility string   `json:"visibility,omitempty" jsonschema:"可见范围（可选），支持: 公开可见(默认)、仅自己可见、仅互关好友可见。不填则默认公开可见"`
 	Products   []string `json:"products,omitempty" jsonschema:"商品关键词列表（可选），用于绑定带货商品。填写商品名称或商品ID，系统会自动搜索并选择第一个匹配结果。需账号已开通商品功能。示例: [面膜, 防晒霜SPF50]"`
+	Location   string   `json:"location,omitempty" jsonschema:"地点（可选），在发布页「添加地点」中搜索并选中最匹配的地点，如 星巴克(国贸店)。没有足够确定的匹配时发布失败并返回候选列表"`
+	Cover      string   `json:"cover,omitempty" jsonschema:"自定义封面（可选），本地图片绝对路径或图片URL。不填则由平台自动选帧"`
+	CoverTime  *float64 `json:"cover_time,omitempty" jsonschema:"封面时间点（可选），取视频第几秒的画面作为封面，如 3.5。与 cover 二选一"`
+
+	Collection       string `json:"collection,omitempty" jsonschema:"合集名称（可选），发布时把笔记加入该合集。已有合集可用 list_collections 查看；合集不存在时发布失败并返回已有合集，除非 create_collection=true"`
+	CreateCollection bool   `json:"create_collection,omitempty" jsonschema:"合集不存在时自动新建（可选），需同时指定 collection"`
+}
+
+// ListFeedsArgs 获取首页 Feeds 的参数
+type ListFeedsArgs struct {
+	ImageFormat string `json:"image_format,omitempty" jsonschema:"封面地址的图片格式: webp|jpg|png，不填保持原格式"`
+	ImageWidth  int    `json:"image_width,omitempty" jsonschema:"封面等比缩放到的宽度（像素），不填为原图"`
 }
 
 // SearchFeedsArgs 搜索内容的参数
 type SearchFeedsArgs struct {
-	Keyword string       `json:"keyword" jsonschema:"搜索关键词"`
-	Filters FilterOption `json:"filters,omitempty" jsonschema:"筛选选项"`
+	Keyword     string       `json:"keyword" jsonschema:"搜索关键词"`
+	Filters     FilterOption `json:"filters,omitempty" jsonschema:"筛选选项"`
+	ImageFormat string       `json:"image_format,omitempty" jsonschema:"封面地址的图片格式: webp|jpg|png，不填保持原格式"`
+	ImageWidth  int          `json:"image_width,omitempty" jsonschema:"封面等比缩放到的宽度（像素），不填为原图"`
+	Cache       string       `json:"cache,omitempty" jsonschema:"缓存策略: bypass(默认,总是重新抓取并刷新缓存)|prefer(有未过期的缓存就直接返回,数据可能是几分钟到半小时前的)|only(只读缓存,不访问小红书,没有缓存时报错)。响应的cache.source说明数据是fresh还是cache"`
 }
 
 // FilterOption 筛选选项结构体
@@ -62,25 +91,33 @@ type FeedDetailArgs struct {
 	ClickMoreReplies bool   `json:"click_more_replies,omitempty" jsonschema:"【仅当load_all_comments为true时生效】是否展开二级回复。true展开子评论，false不展开（默认）"`
 	ReplyLimit       int    `json:"reply_limit,omitempty" jsonschema:"【仅当click_more_replies为true时生效】跳过回复数过多的评论。例如10表示跳过超过10条回复的，默认10"`
 	ScrollSpeed      string `json:"scroll_speed,omitempty" jsonschema:"【仅当load_all_comments为true时生效】滚动速度slow慢速、normal正常、fast快速"`
+	ImageFormat      string `json:"image_format,omitempty" jsonschema:"视频首帧、缩略图地址的图片格式: webp|jpg|png，不填保持原格式"`
+	ImageWidth       int    `json:"image_width,omitempty" jsonschema:"视频首帧、缩略图等比缩放到的宽度（像素），不填为原图"`
+	Cache            string `json:"cache,omitempty" jsonschema:"缓存策略: bypass(默认,总是重新抓取并刷新缓存)|prefer(有未过期的缓存就直接返回,数据可能是几分钟到半小时前的)|only(只读缓存,不访问小红书,没有缓存时报错)。响应的cache.source说明数据是fresh还是cache"`
 }
 
 // UserProfileArgs 获取用户主页的参数
 type UserProfileArgs struct {
-	UserID    string `json:"user_id" jsonschema:"小红书用户ID，从Feed列表获取"`
-	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
-	Tab       string `json:"tab,omitempty" jsonschema:"主页 tab: note(笔记,默认)|fav(收藏)|liked(点赞)。收藏和点赞可能被对方设为不公开"`
+	UserID      string `json:"user_id" jsonschema:"小红书用户ID，从Feed列表获取"`
+	XsecToken   string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Tab         string `json:"tab,omitempty" jsonschema:"主页 tab: note(笔记,默认)|fav(收藏)|liked(点赞)。收藏和点赞可能被对方设为不公开"`
+	ImageFormat string `json:"image_format,omitempty" jsonschema:"封面地址的图片格式: webp|jpg|png，不填保持原格式"`
+	ImageWidth  int    `json:"image_width,omitempty" jsonschema:"封面等比缩放到的宽度（像素），不填为原图"`
+	Cache       string `json:"cache,omitempty" jsonschema:"缓存策略: bypass(默认,总是重新抓取并刷新缓存)|prefer(有未过期的缓存就直接返回,数据可能是几分钟到半小时前的)|only(只读缓存,不访问小红书,没有缓存时报错)。响应的cache.source说明数据是fresh还是cache"`
 }
 
 // MyProfileArgs 我的主页参数
 type MyProfileArgs struct {
-	Tab string `json:"tab,omitempty" jsonschema:"主页 tab: note(笔记,默认)|fav(收藏)|liked(点赞)"`
+	Tab         string `json:"tab,omitempty" jsonschema:"主页 tab: note(笔记,默认)|fav(收藏)|liked(点赞)"`
+	ImageFormat string `json:"image_format,omitempty" jsonschema:"封面地址的图片格式: webp|jpg|png，不填保持原格式"`
+	ImageWidth  int    `json:"image_width,omitempty" jsonschema:"封面等比缩放到的宽度（像素），不填为原图"`
 }
 
 // PostCommentArgs 发表评论的参数
 type PostCommentArgs struct {
 	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
 	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
-	Content   string `json:"content" jsonschema:"评论内容"`
+	Content   string `json:"content" jsonschema:"评论内容，用 @{昵称} @ 用户"`
 }
 
 // ReplyCommentArgs 回复评论的参数
@@ -89,7 +126,163 @@ type ReplyCommentArgs struct {
 	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
 	CommentID string `json:"comment_id,omitempty" jsonschema:"目标评论ID，从评论列表获取"`
 	UserID    string `json:"user_id,omitempty" jsonschema:"目标评论用户ID，从评论列表获取"`
-	Content   string `json:"content" jsonschema:"回复内容"`
+	Content   string `json:"content" jsonschema:"回复内容，用 @{昵称} @ 用户"`
+}
+
+// CommentRepliesArgs 获取评论回复的参数
+type CommentRepliesArgs struct {
+	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	CommentID string `json:"comment_id" jsonschema:"一级评论ID，从 get_feed_detail 的评论列表获取"`
+}
+
+// DownloadNoteMediaArgs 下载笔记媒体的参数
+type DownloadNoteMediaArgs struct {
+	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Dir       string `json:"dir,omitempty" jsonschema:"保存目录的绝对路径（可选），不填则保存到系统临时目录下的 xiaohongshu_downloads/<feed_id>"`
+}
+
+// DownloadVideoArgs 下载视频的参数
+type DownloadVideoArgs struct {
+	FeedID    string `json:"feed_id" jsonschema:"小红书视频笔记ID，从Feed列表获取"`
+	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Policy    string `json:"policy,omitempty" jsonschema:"档位挑选策略: best(分辨率最高,默认)|smallest(体积最小)|codec(指定编码里分辨率最高)"`
+	Codec     string `json:"codec,omitempty" jsonschema:"【仅当policy为codec时生效】首选编码: h264|h265|av1，该编码不存在时回落到best"`
+	Dir       string `json:"dir,omitempty" jsonschema:"保存目录的绝对路径（可选），不填则保存到系统临时目录下的 xiaohongshu_downloads/<feed_id>"`
+}
+
+// VideoSubtitlesArgs 获取视频字幕的参数
+type VideoSubtitlesArgs struct {
+	FeedID          string `json:"feed_id" jsonschema:"小红书视频笔记ID，从Feed列表获取"`
+	XsecToken       string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Language        string `json:"language,omitempty" jsonschema:"字幕语种（可选），如 source、en、zh-CN；不填取原始语种，没有该语种时会报出可选语种"`
+	Format          string `json:"format,omitempty" jsonschema:"输出格式: text(纯文本,默认)|srt|vtt"`
+	StripTimestamps bool   `json:"strip_timestamps,omitempty" jsonschema:"【仅当format为text时生效】去掉每行的时间戳，并合并相邻重复行"`
+}
+
+// ExportNoteArgs 导出笔记的参数
+type ExportNoteArgs struct {
+	FeedID          string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken       string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Dir             string `json:"dir,omitempty" jsonschema:"导出目录的绝对路径（可选），如 Obsidian 库里的某个文件夹；不填则导出到系统临时目录下的 xiaohongshu_exports"`
+	IncludeComments bool   `json:"include_comments,omitempty" jsonschema:"是否把评论区（含二级回复）以嵌套引用追加到文末，默认false"`
+	CommentLimit    int    `json:"comment_limit,omitempty" jsonschema:"【仅当include_comments为true时生效】最多加载的一级评论数，默认20"`
+}
+
+// ExportCommentsArgs 导出评论的参数
+type ExportCommentsArgs struct {
+	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Format    string `json:"format,omitempty" jsonschema:"表格格式: csv(默认)|xlsx"`
+	Path      string `json:"path,omitempty" jsonschema:"输出文件的绝对路径（可选），不填则写到系统临时目录下的 xiaohongshu_exports/<feed_id>_comments.<format>"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"最多加载的一级评论数，默认20"`
+}
+
+// WatchNoteArgs 关注笔记的参数
+type WatchNoteArgs struct {
+	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Label     string `json:"label,omitempty" jsonschema:"备注（可选），如活动名、竞品名"`
+}
+
+// UnwatchNoteArgs 取消关注笔记的参数
+type UnwatchNoteArgs struct {
+	FeedID string `json:"feed_id" jsonschema:"关注列表里的笔记ID"`
+}
+
+// NoteMetricsHistoryArgs 互动数序列的参数
+type NoteMetricsHistoryArgs struct {
+	FeedID     string `json:"feed_id" jsonschema:"关注列表里的笔记ID"`
+	SinceHours int    `json:"since_hours,omitempty" jsonschema:"只返回最近多少小时的采样，不填返回全部"`
+}
+
+// SaveSearchArgs 保存搜索的参数
+type SaveSearchArgs struct {
+	Keyword string       `json:"keyword" jsonschema:"搜索关键词"`
+	Filters FilterOption `json:"filters,omitempty" jsonschema:"筛选选项，监控新笔记一般用 sort_by=最新"`
+	Webhook string       `json:"webhook,omitempty" jsonschema:"有新笔记时 POST JSON 到这个 http(s) 地址（可选），不填只进收件箱"`
+}
+
+// DeleteSavedSearchArgs 删除保存的搜索的参数
+type DeleteSavedSearchArgs struct {
+	SearchID string `json:"search_id" jsonschema:"保存的搜索ID，从 list_saved_searches 获取"`
+}
+
+// SearchAlertsArgs 查询新笔记提醒的参数
+type SearchAlertsArgs struct {
+	SearchID   string `json:"search_id,omitempty" jsonschema:"只看某个保存的搜索（可选）"`
+	UnreadOnly bool   `json:"unread_only,omitempty" jsonschema:"只返回未读提醒"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"最多返回多少条，最新的在前，不填返回全部"`
+	MarkRead   bool   `json:"mark_read,omitempty" jsonschema:"把本次返回的提醒标为已读"`
+}
+
+// DraftIDArgs 指定草稿的参数
+type DraftIDArgs struct {
+	DraftID string `json:"draft_id" jsonschema:"草稿ID，从 list_drafts 或存草稿的返回获取"`
+}
+
+// PublishDraftArgs 发布草稿的参数
+type PublishDraftArgs struct {
+	DraftID    string `json:"draft_id" jsonschema:"草稿ID，从 list_drafts 或存草稿的返回获取"`
+	ScheduleAt string `json:"schedule_at,omitempty" jsonschema:"定时发布时间（可选），ISO8601格式如 2024-01-20T10:30:00+08:00，支持1小时至14天内。不填则立即发布"`
+}
+
+// PublishFromFileArgs 从 Markdown 稿件发布的参数
+type PublishFromFileArgs struct {
+	Path   string `json:"path" jsonschema:"Markdown 稿件的绝对路径。front matter 支持 title、tags、visibility、schedule_at、is_original、products、location、collection、create_collection、images，正文即笔记内容，正文里的 ![](相对路径) 图片按稿件所在目录解析"`
+	Draft  bool   `json:"draft,omitempty" jsonschema:"只存草稿不发布（可选），之后用 publish_draft 发布"`
+	DryRun bool   `json:"dry_run,omitempty" jsonschema:"只解析并校验稿件，返回解析结果，不发布"`
+}
+
+// DeleteNoteArgs 删除笔记的参数
+type DeleteNoteArgs struct {
+	FeedID  string `json:"feed_id" jsonschema:"要删除的笔记ID，必须是当前账号自己的笔记"`
+	Confirm bool   `json:"confirm" jsonschema:"确认删除，必须为 true。删除不可恢复，点赞、评论和收藏会一起消失"`
+}
+
+// EditNoteArgs 编辑笔记的参数
+type EditNoteArgs struct {
+	FeedID     string   `json:"feed_id" jsonschema:"要修改的笔记ID，必须是当前账号自己的笔记"`
+	Title      string   `json:"title,omitempty" jsonschema:"新标题（可选），不填不改"`
+	Content    string   `json:"content,omitempty" jsonschema:"新正文（可选），不填不改，不包含话题。用 @{昵称} @ 用户"`
+	Tags       []string `json:"tags,omitempty" jsonschema:"新话题列表（可选），不填不改，传空数组清空话题"`
+	Visibility string   `json:"visibility,omitempty" jsonschema:"新可见范围（可选）: 公开可见、仅自己可见、仅互关好友可见，不填不改"`
+}
+
+// SchedulePublishArgs 本地定时发布的参数
+type SchedulePublishArgs struct {
+	Title      string         `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
+	Content    string         `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容。用 @{昵称} @ 用户"`
+	Images     []string       `json:"images" jsonschema:"图片路径列表（至少需要1张图片），HTTP/HTTPS链接或本地绝对路径。安排时就下载并复制到服务的数据目录，之后改动或删除原图不影响发布"`
+	PublishAt  string         `json:"publish_at" jsonschema:"发布时间，ISO8601格式如 2025-06-18T20:00:00+08:00，晚于当前时间即可，没有上限"`
+	Tags       []string       `json:"tags,omitempty" jsonschema:"话题标签列表（可选）"`
+	IsOriginal bool           `json:"is_original,omitempty" jsonschema:"是否声明原创（可选）"`
+	Visibility string         `json:"visibility,omitempty" jsonschema:"可见范围（可选）: 公开可见(默认)、仅自己可见、仅互关好友可见"`
+	Products   []string       `json:"products,omitempty" jsonschema:"商品关键词列表（可选），发布时搜索并绑定"`
+	Location   string         `json:"location,omitempty" jsonschema:"地点（可选），发布时搜索并选中最匹配的地点"`
+	Preprocess *ImagePrepArgs `json:"preprocess,omitempty" jsonschema:"上传前的图片预处理（可选），安排时就处理好，参数同 publish_content"`
+
+	Collection       string `json:"collection,omitempty" jsonschema:"合集名称（可选），发布时加入该合集"`
+	CreateCollection bool   `json:"create_collection,omitempty" jsonschema:"发布时合集不存在则新建（可选），需同时指定 collection"`
+}
+
+// ScheduleIDArgs 指定定时任务的参数
+type ScheduleIDArgs struct {
+	ScheduleID string `json:"schedule_id" jsonschema:"定时任务ID，从 schedule_publish 或 list_scheduled_publishes 的返回获取"`
+}
+
+// RescheduleArgs 修改发布时间的参数
+type RescheduleArgs struct {
+	ScheduleID string `json:"schedule_id" jsonschema:"定时任务ID，从 schedule_publish 或 list_scheduled_publishes 的返回获取"`
+	PublishAt  string `json:"publish_at" jsonschema:"新的发布时间，ISO8601格式，晚于当前时间即可"`
+}
+
+// LintPostArgs 发布前检查的参数
+type LintPostArgs struct {
+	Title   string   `json:"title" jsonschema:"标题"`
+	Content string   `json:"content" jsonschema:"正文，与发布时一致，可含 @{昵称}"`
+	Tags    []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选）"`
 }
 
 // LikeFeedArgs 点赞参数
@@ -238,14 +431,19 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 		withPanicRecovery("publish_content", func(ctx context.Context, req *mcp.CallToolRequest, args PublishContentArgs) (*mcp.CallToolResult, any, error) {
 			// 转换参数格式到现有的 handler
 			argsMap := map[string]interface{}{
-				"title":       args.Title,
-				"content":     args.Content,
-				"images":      convertStringsToInterfaces(args.Images),
-				"tags":        convertStringsToInterfaces(args.Tags),
-				"schedule_at": args.ScheduleAt,
-				"is_original": args.IsOriginal,
-				"visibility":  args.Visibility,
-				"products":    convertStringsToInterfaces(args.Products),
+				"title":             args.Title,
+				"content":           args.Content,
+				"images":            convertStringsToInterfaces(args.Images),
+				"tags":              convertStringsToInterfaces(args.Tags),
+				"schedule_at":       args.ScheduleAt,
+				"is_original":       args.IsOriginal,
+				"visibility":        args.Visibility,
+				"products":          convertStringsToInterfaces(args.Products),
+				"location":          args.Location,
+				"draft":             args.Draft,
+				"preprocess":        args.Preprocess,
+				"collection":        args.Collection,
+				"create_collection": args.CreateCollection,
 			}
 			result := appServer.handlePublishContent(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
@@ -256,14 +454,14 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "list_feeds",
-			Description: "获取首页 Feeds 列表",
+			Description: "获取首页 Feeds 列表。封面地址可用 image_format、image_width 指定格式和尺寸",
 			Annotations: &mcp.ToolAnnotations{
 				Title:        "List Feeds",
 				ReadOnlyHint: true,
 			},
 		},
-		withPanicRecovery("list_feeds", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
-			result := appServer.handleListFeeds(ctx)
+		withPanicRecovery("list_feeds", func(ctx context.Context, req *mcp.CallToolRequest, args ListFeedsArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListFeeds(ctx, args)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -272,7 +470,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "search_feeds",
-			Description: "搜索小红书内容（需要已登录）",
+			Description: "搜索小红书内容（需要已登录）。封面地址可用 image_format、image_width 指定格式和尺寸",
 			Annotations: &mcp.ToolAnnotations{
 				Title:        "Search Feeds",
 				ReadOnlyHint: true,
@@ -288,7 +486,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "get_feed_detail",
-			Description: "获取小红书笔记详情，返回笔记内容、图片、作者信息、互动数据（点赞/收藏/分享数）及评论列表。视频笔记额外返回 video 字段，含各编码档位的视频直链与字幕地址（均带签名、有时效）。默认返回前10条一级评论，如需更多评论请设置load_all_comments=true",
+			Description: "获取小红书笔记详情，返回笔记内容、图片、作者信息、互动数据（点赞/收藏/分享数）及评论列表。视频笔记额外返回 video 字段，含各编码档位的视频直链与字幕地址（均带签名、有时效），以及首帧、缩略图地址（可用 image_format、image_width 指定格式和尺寸）。默认返回前10条一级评论，如需更多评论请设置load_all_comments=true",
 			Annotations: &mcp.ToolAnnotations{
 				Title:        "Get Feed Detail",
 				ReadOnlyHint: true,
@@ -299,6 +497,9 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 				"feed_id":           args.FeedID,
 				"xsec_token":        args.XsecToken,
 				"load_all_comments": args.LoadAllComments,
+				"image_format":      args.ImageFormat,
+				"image_width":       args.ImageWidth,
+				"cache":             args.Cache,
 			}
 
 			// 只有当 load_all_comments=true 时，才处理其他参数
@@ -333,7 +534,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "user_profile",
-			Description: "获取指定的小红书用户主页，返回用户基本信息，关注、粉丝、获赞量，以及指定 tab 下的内容。tab 可选 note(笔记,默认)、fav(收藏)、liked(点赞)，后两者可能被对方设为不公开",
+			Description: "获取指定的小红书用户主页，返回用户基本信息，关注、粉丝、获赞量，以及指定 tab 下的内容。tab 可选 note(笔记,默认)、fav(收藏)、liked(点赞)，后两者可能被对方设为不公开。封面地址可用 image_format、image_width 指定格式和尺寸",
 			Annotations: &mcp.ToolAnnotations{
 				Title:        "User Profile",
 				ReadOnlyHint: true,
@@ -341,9 +542,12 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 		},
 		withPanicRecovery("user_profile", func(ctx context.Context, req *mcp.CallToolRequest, args UserProfileArgs) (*mcp.CallToolResult, any, error) {
 			argsMap := map[string]interface{}{
-				"user_id":    args.UserID,
-				"xsec_token": args.XsecToken,
-				"tab":        args.Tab,
+				"user_id":      args.UserID,
+				"xsec_token":   args.XsecToken,
+				"tab":          args.Tab,
+				"cache":        args.Cache,
+				"image_format": args.ImageFormat,
+				"image_width":  args.ImageWidth,
 			}
 			result := appServer.handleUserProfile(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
@@ -405,7 +609,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "publish_with_video",
-			Description: "发布小红书视频内容（仅支持本地单个视频文件）",
+			Description: "发布小红书视频内容（单个视频，支持本地文件或 http(s) URL）",
 			Annotations: &mcp.ToolAnnotations{
 				Title:           "Publish Video",
 				DestructiveHint: boolPtr(true),
@@ -413,13 +617,20 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 		},
 		withPanicRecovery("publish_with_video", func(ctx context.Context, req *mcp.CallToolRequest, args PublishVideoArgs) (*mcp.CallToolResult, any, error) {
 			argsMap := map[string]interface{}{
-				"title":       args.Title,
-				"content":     args.Content,
-				"video":       args.Video,
-				"tags":        convertStringsToInterfaces(args.Tags),
-				"schedule_at": args.ScheduleAt,
-				"visibility":  args.Visibility,
-				"products":    convertStringsToInterfaces(args.Products),
+				"title":             args.Title,
+				"content":           args.Content,
+				"video":             args.Video,
+				"tags":              convertStringsToInterfaces(args.Tags),
+				"schedule_at":       args.ScheduleAt,
+				"visibility":        args.Visibility,
+				"products":          convertStringsToInterfaces(args.Products),
+				"location":          args.Location,
+				"cover":             args.Cover,
+				"collection":        args.Collection,
+				"create_collection": args.CreateCollection,
+			}
+			if args.CoverTime != nil {
+				argsMap["cover_time"] = *args.CoverTime
 			}
 			result := appServer.handlePublishVideo(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
@@ -472,14 +683,14 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "get_my_profile",
-			Description: "获取当前登录用户的主页，返回用户基本信息，关注、粉丝、获赞量，以及指定 tab 下的内容。tab 可选 note(自己发的笔记,默认)、fav(自己收藏的)、liked(自己点赞的)",
+			Description: "获取当前登录用户的主页，返回用户基本信息，关注、粉丝、获赞量，以及指定 tab 下的内容。tab 可选 note(自己发的笔记,默认)、fav(自己收藏的)、liked(自己点赞的)。封面地址可用 image_format、image_width 指定格式和尺寸",
 			Annotations: &mcp.ToolAnnotations{
 				Title:        "Get My Profile",
 				ReadOnlyHint: true,
 			},
 		},
 		withPanicRecovery("get_my_profile", func(ctx context.Context, req *mcp.CallToolRequest, args MyProfileArgs) (*mcp.CallToolResult, any, error) {
-			result := appServer.handleGetMyProfile(ctx, args.Tab)
+			result := appServer.handleGetMyProfile(ctx, args)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -548,7 +759,422 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 		}),
 	)
 
-	logrus.Infof("Registered %d MCP tools", 18)
+	// 工具 19: 获取单条评论的全部回复
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_comment_replies",
+			Description: "获取笔记下某条一级评论的全部回复（楼中楼）。只展开这一楼，一直展开到没有「展开更多回复」为止，不受 get_feed_detail 的 reply_limit 限制。complete=false 表示展开中途停止，回复可能不全。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Comment Replies",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("get_comment_replies", func(ctx context.Context, req *mcp.CallToolRequest, args CommentRepliesArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleGetCommentReplies(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 20: 下载笔记图片与实况视频
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "download_note_media",
+			Description: "把笔记的全部图片按原顺序下载到本地目录（01.webp、02.jpg…），实况图额外保存视频部分（02_live.mp4），并在目录里写出 manifest.json。返回本地路径列表和清单内容，单个文件失败会记在清单的 error 字段里。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:          "Download Note Media",
+				IdempotentHint: true,
+			},
+		},
+		withPanicRecovery("download_note_media", func(ctx context.Context, req *mcp.CallToolRequest, args DownloadNoteMediaArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleDownloadNoteMedia(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 21: 下载视频
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "download_video",
+			Description: "下载视频笔记的视频文件到本地。可按策略挑档位：best(分辨率最高)、smallest(体积最小)、codec(指定编码)。签名主地址过期时自动换备用地址，中断后再次调用会续传，下完按页面给出的大小校验。返回本地路径、所选档位及全部可选档位。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:          "Download Video",
+				IdempotentHint: true,
+			},
+		},
+		withPanicRecovery("download_video", func(ctx context.Context, req *mcp.CallToolRequest, args DownloadVideoArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleDownloadVideo(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 22: 获取视频字幕
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_video_subtitles",
+			Description: "获取视频笔记的字幕，返回纯文本、SRT 或 WebVTT。纯文本可去掉时间戳，适合直接阅读或总结视频内容。笔记没有字幕或没有指定语种时会报错并列出可选语种。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Video Subtitles",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("get_video_subtitles", func(ctx context.Context, req *mcp.CallToolRequest, args VideoSubtitlesArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleGetVideoSubtitles(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 23: 导出笔记为 Markdown
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "export_note",
+			Description: "把笔记导出为带 front matter 的 Markdown 文件（作者、发布时间、IP属地、互动数、话题标签），图片下载到同级的 <feed_id>.assets 目录并以相对路径引用，可直接放进 Obsidian 库。可选把评论区以嵌套引用追加到文末。返回 Markdown 文件路径。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:          "Export Note",
+				IdempotentHint: true,
+			},
+		},
+		withPanicRecovery("export_note", func(ctx context.Context, req *mcp.CallToolRequest, args ExportNoteArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleExportNote(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 24: 导出评论为表格
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "export_comments",
+			Description: "加载笔记的评论（含二级回复），摊平成表格写入本地 CSV 或 XLSX 文件。每行一条评论，列为 id、parent_id、depth、user_id、nickname、content、like_count、ip_location、created_at、show_tags。只返回文件路径和行数，评论内容不进响应。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:          "Export Comments",
+				IdempotentHint: true,
+			},
+		},
+		withPanicRecovery("export_comments", func(ctx context.Context, req *mcp.CallToolRequest, args ExportCommentsArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleExportComments(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 25: 关注笔记
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "watch_note",
+			Description: "把笔记加入关注列表，后台定时采集点赞、收藏、评论、分享数（默认每小时一次，XHS_WATCH_INTERVAL 可调）。加入时立即采一次作为基线。已在列表中的笔记只更新 xsec_token 和备注。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:          "Watch Note",
+				IdempotentHint: true,
+			},
+		},
+		withPanicRecovery("watch_note", func(ctx context.Context, req *mcp.CallToolRequest, args WatchNoteArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleWatchNote(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 26: 取消关注笔记
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "unwatch_note",
+			Description: "把笔记移出关注列表，停止采样。已采集的序列保留，仍可用 get_note_metrics_history 查询。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Unwatch Note",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("unwatch_note", func(ctx context.Context, req *mcp.CallToolRequest, args UnwatchNoteArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleUnwatchNote(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 27: 关注列表
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_watched_notes",
+			Description: "列出关注列表中的笔记，含最近一次采样时间和采样错误。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Watched Notes",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("list_watched_notes", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListWatchedNotes(ctx)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 28: 互动数序列
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_note_metrics_history",
+			Description: "返回关注笔记的互动数采样序列，以及首尾总增量和相邻两次采样之间的增量（含每小时点赞增速）。超过一万的计数页面只给近似值，这类点的 exact 为 false。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Note Metrics History",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("get_note_metrics_history", func(ctx context.Context, req *mcp.CallToolRequest, args NoteMetricsHistoryArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleNoteMetricsHistory(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 29: 保存搜索
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "save_search",
+			Description: "保存一个关键词搜索（可带筛选项），后台定时重新搜索（默认每30分钟，XHS_MONITOR_INTERVAL 可调），把之前没出现过的笔记写入收件箱，配置了 webhook 时同时推送。保存时立即搜一次，当前结果作为基线不产生提醒。同样的关键词和筛选只保存一份，重复保存只更新 webhook。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:          "Save Search",
+				IdempotentHint: true,
+			},
+		},
+		withPanicRecovery("save_search", func(ctx context.Context, req *mcp.CallToolRequest, args SaveSearchArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleSaveSearch(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 30: 删除保存的搜索
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "delete_saved_search",
+			Description: "删除保存的搜索，停止监控。收件箱里已有的提醒保留。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Delete Saved Search",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("delete_saved_search", func(ctx context.Context, req *mcp.CallToolRequest, args DeleteSavedSearchArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleDeleteSavedSearch(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 31: 保存的搜索列表
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_saved_searches",
+			Description: "列出保存的搜索，含最近一次运行时间和失败原因。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Saved Searches",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("list_saved_searches", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListSavedSearches(ctx)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 32: 新笔记提醒
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_search_alerts",
+			Description: "查询保存的搜索发现的新笔记，最新的在前。每条含 feed_id 和 xsec_token，可直接用于 get_feed_detail。",
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Get Search Alerts",
+			},
+		},
+		withPanicRecovery("get_search_alerts", func(ctx context.Context, req *mcp.CallToolRequest, args SearchAlertsArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleSearchAlerts(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 33: 草稿列表
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_drafts",
+			Description: "列出用 publish_content(draft=true) 保存的图文草稿，新的在前。草稿只保存在本服务本地，创作中心草稿箱里看不到。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Drafts",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("list_drafts", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListDrafts(ctx)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 34: 发布草稿
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "publish_draft",
+			Description: "按草稿内容重新填表并发布，可指定定时发布。发布成功后删除草稿。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Publish Draft",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("publish_draft", func(ctx context.Context, req *mcp.CallToolRequest, args PublishDraftArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handlePublishDraft(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 35: 删除草稿
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "delete_draft",
+			Description: "删除草稿及其图片。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Delete Draft",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("delete_draft", func(ctx context.Context, req *mcp.CallToolRequest, args DraftIDArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleDeleteDraft(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 36: 从 Markdown 稿件发布
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "publish_from_file",
+			Description: "读取带 YAML front matter 的 Markdown 稿件发布图文：front matter 放标题、标签、可见范围、定时、原创、商品和图片，正文作为笔记内容，正文里的图片引用会被提取为图片并从内容中去掉。先用 dry_run 检查解析结果。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Publish From File",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("publish_from_file", func(ctx context.Context, req *mcp.CallToolRequest, args PublishFromFileArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handlePublishFromFile(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 37: 合集列表
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_collections",
+			Description: "列出当前账号在创作中心已有的合集（名称和笔记数），用于发布时的 collection 参数。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Collections",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("list_collections", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListCollections(ctx)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 38: 删除笔记
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "delete_note",
+			Description: "删除当前账号自己的笔记（在创作中心笔记管理页操作），删除后会确认笔记已从列表消失。不可恢复，必须传 confirm=true。只能删除笔记管理页列表里能找到的笔记。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Delete Note",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("delete_note", func(ctx context.Context, req *mcp.CallToolRequest, args DeleteNoteArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleDeleteNote(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 39: 编辑笔记
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "edit_note",
+			Description: "修改当前账号已发布笔记的标题、正文、话题或可见范围后重新发布，点赞评论等互动保留。只改传入的字段，返回实际改动的字段（修改前后的值）；与原笔记一致时不重新发布。改正文或话题时会重新输入整段正文，原正文里的 @ 需要用 @{昵称} 重新写上才会保留链接。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Edit Note",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("edit_note", func(ctx context.Context, req *mcp.CallToolRequest, args EditNoteArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleEditNote(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 40: 本地定时发布
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "schedule_publish",
+			Description: "安排图文在指定时间发布，不受平台定时发布 1 小时至 14 天的限制，适合提前数月排好的内容日历。图片在安排时复制到服务的数据目录，由服务到点发布（需保持服务运行），失败会退避重试几次。返回 schedule_id，用 list_scheduled_publishes 查看状态和发布后的笔记ID。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Schedule Publish",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("schedule_publish", func(ctx context.Context, req *mcp.CallToolRequest, args SchedulePublishArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleSchedulePublish(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 41: 定时任务列表
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_scheduled_publishes",
+			Description: "列出本地定时发布任务，按发布时间先后排列，含状态（pending 待发布/running 发布中/done 已发布/failed 失败/canceled 已取消）、尝试次数、最近的错误和发布后的笔记ID。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Scheduled Publishes",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("list_scheduled_publishes", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListScheduledPublishes(ctx)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 42: 取消定时任务
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "cancel_scheduled_publish",
+			Description: "取消未发出的本地定时发布任务（待发布或失败），同时删除复制的图片。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Cancel Scheduled Publish",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("cancel_scheduled_publish", func(ctx context.Context, req *mcp.CallToolRequest, args ScheduleIDArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleCancelScheduledPublish(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 43: 修改发布时间
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "reschedule_publish",
+			Description: "修改本地定时发布任务的发布时间。失败的任务（重试用完或发布途中服务退出）确认未发出后也用它重新安排，尝试次数清零。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Reschedule Publish",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("reschedule_publish", func(ctx context.Context, req *mcp.CallToolRequest, args RescheduleArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleReschedulePublish(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 44: 发布前检查
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "lint_post",
+			Description: "不打开浏览器检查图文内容：标题和正文长度、话题数量和重复、违禁词和敏感词、外部链接、电话号码、表情密度。每个问题带级别（error/warning/info），发布时也会自动检查，有 error 时直接拒绝发布。词表和级别可在数据目录的 lint.yaml 里配置。",
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Lint Post",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("lint_post", func(ctx context.Context, req *mcp.CallToolRequest, args LintPostArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleLintPost(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	logrus.Infof("Registered %d MCP tools", 44)
 }
 
 // convertToMCPResult 将自定义的 MCPToolResult 转换为官方 SDK 的格式
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/humanize"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/cache"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/monitor"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhscdn"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// 搜索监控间隔，XHS_MONITOR_INTERVAL 可调。默认半小时，保证一小时内能发现新笔记
+const (
+	defaultMonitorInterval = 30 * time.Minute
+	minMonitorInterval     = 10 * time.Minute
+)
+
+// SaveSearchRequest 保存搜索请求
+type SaveSearchRequest struct {
+	Keyword string                   `json:"keyword" binding:"required"`
+	Filters xiaohongshu.FilterOption `json:"filters,omitempty"`
+	Webhook string                   `json:"webhook,omitempty"`
+}
+
+// SaveSearchResponse 保存搜索响应
+type SaveSearchResponse struct {
+	Created  bool           `json:"created"` // false 表示已存在，只更新了 webhook
+	Search   monitor.Search `json:"search"`
+	Baseline int            `json:"baseline"` // 新建时当前结果数，这些笔记不会产生提醒
+}
+
+// SearchAlertsRequest 查询新笔记提醒
+type SearchAlertsRequest struct {
+	SearchID   string `json:"search_id,omitempty"`
+	UnreadOnly bool   `json:"unread_only,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	MarkRead   bool   `json:"mark_read,omitempty"` // 把本次返回的提醒标为已读
+}
+
+// SearchAlertsResponse 新笔记提醒
+type SearchAlertsResponse struct {
+	Alerts []monitor.Alert `json:"alerts"`
+	Count  int             `json:"count"`
+}
+
+// SaveSearch 保存搜索。新建时立即搜一次，把当前结果记为基线，同时验证关键词和筛选可用
+func (s *XiaohongshuService) SaveSearch(ctx context.Context, req *SaveSearchRequest) (*SaveSearchResponse, error) {
+	if err := xiaohongshu.ValidateFilters(req.Filters); err != nil {
+		return nil, err
+	}
+	if err := monitor.ValidateWebhook(req.Webhook); err != nil {
+		return nil, err
+	}
+
+	search, created, err := s.monitor.Save(monitor.Search{
+		Keyword: req.Keyword,
+		Filters: req.Filters,
+		Webhook: req.Webhook,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &SaveSearchResponse{Created: created, Search: search}
+	if !created {
+		return resp, nil
+	}
+
+	matches, err := s.runSavedSearch(ctx, search)
+	if err != nil {
+		// 基线没建成就撤销保存，调用方修正后重试即可
+		if _, delErr := s.monitor.Delete(search.ID); delErr != nil {
+			logrus.Warnf("回滚保存的搜索 %s 失败: %v", search.ID, delErr)
+		}
+		return nil, err
+	}
+	if _, err := s.monitor.Record(search.ID, matches, nil, time.Now()); err != nil {
+		return nil, err
+	}
+	resp.Baseline = len(matches)
+
+	searches, err := s.monitor.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range searches {
+		if item.ID == search.ID {
+			resp.Search = item
+		}
+	}
+	return resp, nil
+}
+
+// DeleteSavedSearch 删除保存的搜索，已有提醒保留
+func (s *XiaohongshuService) DeleteSavedSearch(id string) error {
+	deleted, err := s.monitor.Delete(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("保存的搜索 %s 不存在", id)
+	}
+	return nil
+}
+
+// ListSavedSearches 保存的搜索
+func (s *XiaohongshuService) ListSavedSearches() ([]monitor.Search, error) {
+	return s.monitor.List()
+}
+
+// SearchAlerts 查询收件箱，最新的在前
+func (s *XiaohongshuService) SearchAlerts(req *SearchAlertsRequest) (*SearchAlertsResponse, error) {
+	alerts, err := s.monitor.Alerts(monitor.AlertQuery{
+		SearchID:   req.SearchID,
+		UnreadOnly: req.UnreadOnly,
+		Limit:      req.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MarkRead && len(alerts) > 0 {
+		ids := make([]string, len(alerts))
+		for i, a := range alerts {
+			ids[i] = a.ID
+		}
+		if _, err := s.monitor.MarkRead(ids); err != nil {
+			return nil, err
+		}
+	}
+
+	return &SearchAlertsResponse{Alerts: alerts, Count: len(alerts)}, nil
+}
+
+// runSavedSearches 把保存的搜索各跑一次，新命中写入收件箱并推送 webhook，由后台任务定时调用
+func (s *XiaohongshuService) runSavedSearches(ctx context.Context) {
+	searches, err := s.monitor.List()
+	if err != nil {
+		logrus.Errorf("读取保存的搜索失败: %v", err)
+		return
+	}
+
+	for i, search := range searches {
+		if ctx.Err() != nil {
+			return
+		}
+		if i > 0 {
+			humanize.Delay(ctx, humanize.Reading)
+		}
+
+		matches, err := s.runSavedSearch(ctx, search)
+		if err != nil {
+			logrus.Warnf("保存的搜索 %s(%s) 执行失败: %v", search.ID, search.Keyword, err)
+		}
+		alerts, recErr := s.monitor.Record(search.ID, matches, err, time.Now())
+		if recErr != nil {
+			logrus.Errorf("记录搜索 %s 结果失败: %v", search.ID, recErr)
+			continue
+		}
+		if len(alerts) == 0 {
+			continue
+		}
+
+		logrus.Infof("保存的搜索 %s(%s) 发现 %d 篇新笔记", search.ID, search.Keyword, len(alerts))
+		if search.Webhook == "" {
+			continue
+		}
+		payload := monitor.WebhookPayload{SearchID: search.ID, Keyword: search.Keyword, Alerts: alerts}
+		if err := monitor.PostWebhook(ctx, search.Webhook, payload); err != nil {
+			// 提醒已在收件箱里，推送失败只记下原因
+			logrus.Warnf("保存的搜索 %s: %v", search.ID, err)
+			if err := s.monitor.SetError(search.ID, err); err != nil {
+				logrus.Errorf("记录搜索 %s 失败原因失败: %v", search.ID, err)
+			}
+		}
+	}
+}
+
+// runSavedSearch 搜一次，不走缓存
+func (s *XiaohongshuService) runSavedSearch(ctx context.Context, search monitor.Search) ([]monitor.Match, error) {
+	result, err := s.SearchFeeds(ctx, search.Keyword, cache.ModeBypass, xhscdn.Options{}, search.Filters)
+	if err != nil {
+		return nil, err
+	}
+	return matchesOf(result.Feeds), nil
+}
+
+// matchesOf 搜索结果转成监控关心的字段
+func matchesOf(feeds []xiaohongshu.Feed) []monitor.Match {
+	matches := make([]monitor.Match, 0, len(feeds))
+	for _, f := range feeds {
+		author := f.NoteCard.User.Nickname
+		if author == "" {
+			author = f.NoteCard.User.NickName
+		}
+		matches = append(matches, monitor.Match{
+			FeedID:    f.ID,
+			XsecToken: f.XsecToken,
+			Title:     f.NoteCard.DisplayTitle,
+			Author:    author,
+		})
+	}
+	return matches
+}
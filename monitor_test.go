@@ -0,0 +1,22 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// TestMatchesOf 作者昵称两种拼写都要认。
+func TestMatchesOf(t *testing.T) {
+	matches := matchesOf([]xiaohongshu.Feed{
+		{ID: "a", XsecToken: "t", NoteCard: xiaohongshu.NoteCard{DisplayTitle: "标题", User: xiaohongshu.User{Nickname: "小红"}}},
+		{ID: "b", NoteCard: xiaohongshu.NoteCard{User: xiaohongshu.User{NickName: "小蓝"}}},
+	})
+
+	assert.Len(t, matches, 2)
+	assert.Equal(t, "标题", matches[0].Title)
+	assert.Equal(t, "t", matches[0].XsecToken)
+	assert.Equal(t, "小红", matches[0].Author)
+	assert.Equal(t, "小蓝", matches[1].Author)
+}
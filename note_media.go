@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// 归档条目类型
+const (
+	mediaTypeImage     = "image"
+	mediaTypeLiveVideo = "live_video" // 实况图的视频部分
+)
+
+// manifestFileName 归档目录里的清单文件名
+const manifestFileName = "manifest.json"
+
+// NoteMediaItem 清单里的单个文件
+type NoteMediaItem struct {
+	Index  int    `json:"index"` // 在笔记里的序号，从 1 开始；实况视频与其图片同号
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+	Path   string `json:"path,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	Error  string `json:"error,omitempty"` // 下载失败时的原因，此时 Path 为空
+}
+
+// NoteMediaManifest 笔记媒体清单，同时写进归档目录的 manifest.json
+type NoteMediaManifest struct {
+	FeedID       string          `json:"feed_id"`
+	Title        string          `json:"title"`
+	Author       string          `json:"author"`
+	AuthorID     string          `json:"author_id"`
+	DownloadedAt time.Time       `json:"downloaded_at"`
+	Items        []NoteMediaItem `json:"items"`
+}
+
+// DownloadNoteMediaRequest 笔记媒体下载请求
+type DownloadNoteMediaRequest struct {
+	FeedID    string `json:"feed_id" binding:"required"`
+	XsecToken string `json:"xsec_token" binding:"required"`
+	Dir       string `json:"dir,omitempty"` // 保存目录，为空则使用默认归档目录下的 <feed_id>
+}
+
+// DownloadNoteMediaResponse 笔记媒体下载响应
+type DownloadNoteMediaResponse struct {
+	Dir          string            `json:"dir"`
+	Files        []string          `json:"files"`
+	Failed       int               `json:"failed"`
+	ManifestPath string            `json:"manifest_path"`
+	Manifest     NoteMediaManifest `json:"manifest"`
+}
+
+// DownloadNoteMedia 按原顺序下载笔记的全部图片，实况图额外保存视频部分，并写出清单
+func (s *XiaohongshuService) DownloadNoteMedia(ctx context.Context, req *DownloadNoteMediaRequest) (*DownloadNoteMediaResponse, error) {
+	detail, err := s.fetchFeedDetail(ctx, req.FeedID, req.XsecToken)
+	if err != nil {
+		return nil, err
+	}
+
+	items := planNoteMedia(detail.Note.ImageList)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("笔记 %s 没有图片可下载", req.FeedID)
+	}
+
+	dir := req.Dir
+	if dir == "" {
+		dir = filepath.Join(configs.GetDownloadsPath(), req.FeedID)
+	}
+
+	d, err := downloader.NewMediaDownloader(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(items))
+	failed := 0
+	for i := range items {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		item := &items[i]
+		item.Path, err = saveNoteMediaItem(ctx, d, *item, detail.Note.ImageList[item.Index-1])
+		if err != nil {
+			logrus.Warnf("下载笔记媒体失败: feed=%s index=%d type=%s: %v", req.FeedID, item.Index, item.Type, err)
+			item.Error = err.Error()
+			failed++
+			continue
+		}
+		files = append(files, item.Path)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("笔记 %s 的 %d 个文件全部下载失败", req.FeedID, len(items))
+	}
+
+	manifest := NoteMediaManifest{
+		FeedID:       req.FeedID,
+		Title:        detail.Note.Title,
+		Author:       detail.Note.User.Nickname,
+		AuthorID:     detail.Note.User.UserID,
+		DownloadedAt: time.Now(),
+		Items:        items,
+	}
+
+	manifestPath := filepath.Join(dir, manifestFileName)
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("写入清单失败: %w", err)
+	}
+
+	return &DownloadNoteMediaResponse{
+		Dir:          dir,
+		Files:        files,
+		Failed:       failed,
+		ManifestPath: manifestPath,
+		Manifest:     manifest,
+	}, nil
+}
+
+// planNoteMedia 按笔记顺序列出要下载的文件：每张图一项，实况图紧跟一项视频。
+func planNoteMedia(images []xiaohongshu.DetailImageInfo) []NoteMediaItem {
+	items := make([]NoteMediaItem, 0, len(images))
+
+	for i, img := range images {
+		index := i + 1
+
+		imageURL := img.URLDefault
+		if imageURL == "" {
+			imageURL = img.URLPre
+		}
+		items = append(items, NoteMediaItem{
+			Index:  index,
+			Type:   mediaTypeImage,
+			URL:    imageURL,
+			Width:  img.Width,
+			Height: img.Height,
+		})
+
+		if stream := livePhotoStream(img); stream != nil {
+			items = append(items, NoteMediaItem{
+				Index:  index,
+				Type:   mediaTypeLiveVideo,
+				URL:    stream.URLs[0],
+				Width:  img.Width,
+				Height: img.Height,
+			})
+		}
+	}
+
+	return items
+}
+
+// livePhotoStream 实况图的视频流，地址主在前、备在后，带大小用于校验；不是实况图时返回 nil。
+// 优先 h264：实况视频很短，兼容性比体积要紧。
+func livePhotoStream(img xiaohongshu.DetailImageInfo) *downloader.VideoCandidate {
+	if !img.LivePhoto {
+		return nil
+	}
+
+	chosen, err := downloader.SelectStream(videoCandidates(img.Stream), downloader.PolicyCodec, "h264")
+	if err != nil || len(chosen.URLs) == 0 {
+		return nil
+	}
+	return chosen
+}
+
+// videoCandidates 把按编码分桶的 stream 摊平成下载候选。
+// 按编码名排序，避免 map 遍历顺序让同分的档位每次选得不一样。
+func videoCandidates(streams map[string][]xiaohongshu.VideoStream) []downloader.VideoCandidate {
+	codecs := make([]string, 0, len(streams))
+	for codec := range streams {
+		codecs = append(codecs, codec)
+	}
+	sort.Strings(codecs)
+
+	var candidates []downloader.VideoCandidate
+	for _, codec := range codecs {
+		for _, st := range streams[codec] {
+			var urls []string
+			if st.MasterURL != "" {
+				urls = append(urls, st.MasterURL)
+			}
+			urls = append(urls, st.BackupURLs...)
+
+			bitrate := st.VideoBitrate
+			if bitrate == 0 {
+				bitrate = st.AvgBitrate
+			}
+
+			candidates = append(candidates, downloader.VideoCandidate{
+				Codec:   codec,
+				Width:   st.Width,
+				Height:  st.Height,
+				Size:    st.Size,
+				Bitrate: bitrate,
+				URLs:    urls,
+			})
+		}
+	}
+	return candidates
+}
+
+// saveNoteMediaItem 下载单个文件，文件名按序号补零，保证目录里的排序就是笔记顺序
+func saveNoteMediaItem(ctx context.Context, d *downloader.MediaDownloader, item NoteMediaItem, img xiaohongshu.DetailImageInfo) (string, error) {
+	name := fmt.Sprintf("%02d", item.Index)
+
+	switch item.Type {
+	case mediaTypeLiveVideo:
+		stream := livePhotoStream(img)
+		if stream == nil {
+			return "", fmt.Errorf("实况视频地址为空")
+		}
+		return d.SaveVideo(ctx, *stream, name+"_live")
+	default:
+		if item.URL == "" {
+			return "", fmt.Errorf("图片地址为空")
+		}
+		return d.SaveImage(item.URL, name)
+	}
+}
+
+// fetchFeedDetail 打开详情页取笔记数据，不加载更多评论
+func (s *XiaohongshuService) fetchFeedDetail(ctx context.Context, feedID, xsecToken string) (*xiaohongshu.FeedDetailResponse, error) {
+	b := newBrowser()
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	action := xiaohongshu.NewFeedDetailAction(page)
+	return action.GetFeedDetailWithConfig(ctx, feedID, xsecToken, false, xiaohongshu.DefaultCommentLoadConfig())
+}
+
+// DownloadVideoRequest 视频下载请求
+type DownloadVideoRequest struct {
+	FeedID    string `json:"feed_id" binding:"required"`
+	XsecToken string `json:"xsec_token" binding:"required"`
+	Policy    string `json:"policy,omitempty"` // best(默认) | smallest | codec
+	Codec     string `json:"codec,omitempty"`  // policy=codec 时的首选编码，如 h264、h265、av1
+	Dir       string `json:"dir,omitempty"`    // 保存目录，为空则使用默认归档目录下的 <feed_id>
+}
+
+// DownloadVideoResponse 视频下载响应
+type DownloadVideoResponse struct {
+	FeedID string `json:"feed_id"`
+	downloader.VideoDownloadResult
+	Stream     downloader.VideoCandidate   `json:"stream"`
+	Candidates []downloader.VideoCandidate `json:"candidates"` // 全部可选档位，便于调用方换策略重下
+}
+
+// DownloadVideo 按策略挑一档视频流下载到本地，支持续传，并按页面给出的大小校验
+func (s *XiaohongshuService) DownloadVideo(ctx context.Context, req *DownloadVideoRequest) (*DownloadVideoResponse, error) {
+	policy, err := downloader.ParseStreamPolicy(req.Policy)
+	if err != nil {
+		return nil, err
+	}
+	if policy == downloader.PolicyCodec && req.Codec == "" {
+		return nil, fmt.Errorf("policy=codec 时必须指定 codec")
+	}
+
+	detail, err := s.fetchFeedDetail(ctx, req.FeedID, req.XsecToken)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Note.Video == nil {
+		return nil, fmt.Errorf("笔记 %s 不是视频笔记", req.FeedID)
+	}
+
+	candidates := videoCandidates(detail.Note.Video.Media.Stream)
+	chosen, err := downloader.SelectStream(candidates, policy, req.Codec)
+	if err != nil {
+		return nil, fmt.Errorf("笔记 %s 没有可下载的视频流: %w", req.FeedID, err)
+	}
+	if policy == downloader.PolicyCodec && downloader.NormalizeCodec(chosen.Codec) != downloader.NormalizeCodec(req.Codec) {
+		logrus.Infof("笔记 %s 没有 %s 编码，回落到 %s", req.FeedID, req.Codec, chosen.Codec)
+	}
+
+	dir := req.Dir
+	if dir == "" {
+		dir = filepath.Join(configs.GetDownloadsPath(), req.FeedID)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	// 文件名带上档位，换策略重下不会续传到别的档位的 .part 上
+	fileName := fmt.Sprintf("%s_%s_%dx%d.mp4", req.FeedID, chosen.Codec, chosen.Width, chosen.Height)
+	result, err := downloader.NewVideoDownloader().Download(ctx, *chosen, filepath.Join(dir, fileName))
+	if err != nil {
+		return nil, fmt.Errorf("下载视频失败: %w", err)
+	}
+
+	logrus.Infof("视频下载完成: feed=%s codec=%s %dx%d size=%d backup=%v resumed=%v",
+		req.FeedID, chosen.Codec, chosen.Width, chosen.Height, result.Size, result.Backup, result.Resumed)
+
+	return &DownloadVideoResponse{
+		FeedID:              req.FeedID,
+		VideoDownloadResult: *result,
+		Stream:              *chosen,
+		Candidates:          candidates,
+	}, nil
+}
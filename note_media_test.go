@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// TestPlanNoteMedia 保持笔记原顺序，实况图的视频紧跟在对应图片后面、序号相同。
+func TestPlanNoteMedia(t *testing.T) {
+	images := []xiaohongshu.DetailImageInfo{
+		{URLDefault: "https://img/1", Width: 1080, Height: 1440},
+		{
+			URLDefault: "https://img/2",
+			LivePhoto:  true,
+			Stream: map[string][]xiaohongshu.VideoStream{
+				"h265": {{MasterURL: "https://v/2-h265"}},
+				"h264": {{MasterURL: "https://v/2-h264", BackupURLs: []string{"https://bak/2"}}},
+			},
+		},
+		{URLPre: "https://img/3-pre"},
+	}
+
+	items := planNoteMedia(images)
+	require.Len(t, items, 4)
+
+	assert.Equal(t, NoteMediaItem{Index: 1, Type: mediaTypeImage, URL: "https://img/1", Width: 1080, Height: 1440}, items[0])
+	assert.Equal(t, 2, items[1].Index)
+	assert.Equal(t, mediaTypeImage, items[1].Type)
+	assert.Equal(t, 2, items[2].Index)
+	assert.Equal(t, mediaTypeLiveVideo, items[2].Type)
+	assert.Equal(t, "https://v/2-h264", items[2].URL, "实况视频优先取 h264")
+	assert.Equal(t, "https://img/3-pre", items[3].URL, "缺 urlDefault 时回落到 urlPre")
+}
+
+// TestLivePhotoStream 主地址在前、备用在后，带上大小；没标记实况的图片即使带 stream 也不当实况处理。
+func TestLivePhotoStream(t *testing.T) {
+	stream := map[string][]xiaohongshu.VideoStream{
+		"h264": {{MasterURL: "m", BackupURLs: []string{"b1", "b2"}, Size: 2048}},
+	}
+
+	got := livePhotoStream(xiaohongshu.DetailImageInfo{LivePhoto: true, Stream: stream})
+	require.NotNil(t, got)
+	assert.Equal(t, []string{"m", "b1", "b2"}, got.URLs)
+	assert.Equal(t, int64(2048), got.Size)
+	assert.Nil(t, livePhotoStream(xiaohongshu.DetailImageInfo{Stream: stream}))
+	assert.Nil(t, livePhotoStream(xiaohongshu.DetailImageInfo{LivePhoto: true}))
+}
+
+// TestVideoCandidates 编码名取桶名，码率缺省时用平均码率，主备地址合在一起。
+func TestVideoCandidates(t *testing.T) {
+	got := videoCandidates(map[string][]xiaohongshu.VideoStream{
+		"h265": {{MasterURL: "m265", Width: 1080, Height: 1920, Size: 10, AvgBitrate: 900}},
+		"h264": {{MasterURL: "m264", BackupURLs: []string{"b264"}, VideoBitrate: 1200}},
+		"av1":  {},
+	})
+
+	require.Len(t, got, 2)
+	assert.Equal(t, "h264", got[0].Codec, "按编码名排序")
+	assert.Equal(t, []string{"m264", "b264"}, got[0].URLs)
+	assert.Equal(t, 1200, got[0].Bitrate)
+	assert.Equal(t, "h265", got[1].Codec)
+	assert.Equal(t, 900, got[1].Bitrate)
+	assert.Equal(t, int64(10), got[1].Size)
+}
@@ -0,0 +1,143 @@
+// Package cache 把抓取结果按类型存成本地 JSON 文件，过期时间由读取方决定。
+//
+// 每条缓存一个文件（<dir>/<kind>/<key 的哈希>.json），不同条目互不影响，
+// 不需要常驻内存，也不用担心单个大文件反复整体重写。过期文件由 Prune 清理。
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/jsonfile"
+)
+
+// Mode 读缓存的策略
+type Mode string
+
+const (
+	ModePrefer Mode = "prefer" // 有未过期的缓存就用，否则抓取并写缓存
+	ModeBypass Mode = "bypass" // 不读缓存，抓取后刷新缓存（默认）
+	ModeOnly   Mode = "only"   // 只读缓存，不访问站点
+)
+
+// ParseMode 解析策略，空串按 bypass 处理：默认总是返回最新数据，读缓存要调用方明确选择
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return ModeBypass, nil
+	case ModePrefer, ModeBypass, ModeOnly:
+		return m, nil
+	default:
+		return "", fmt.Errorf("不支持的缓存策略 %q，可选: prefer|bypass|only", s)
+	}
+}
+
+// ErrMiss 没有缓存或已过期
+var ErrMiss = errors.New("cache miss")
+
+// entry 缓存文件的结构，Key 原样保存便于排查
+type entry struct {
+	Key      string          `json:"key"`
+	StoredAt time.Time       `json:"stored_at"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// pruneInterval 同一类型两次清理之间的最短间隔，避免每次写入都扫目录
+const pruneInterval = 10 * time.Minute
+
+// Store 文件缓存
+type Store struct {
+	dir string
+	now func() time.Time
+
+	mu         sync.Mutex
+	lastPruned map[string]time.Time
+}
+
+// NewStore 创建缓存，目录在第一次写入时创建
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, now: time.Now, lastPruned: make(map[string]time.Time)}
+}
+
+// Get 读取未超过 ttl 的缓存解码到 v，返回写入时间。没有或已过期时返回 ErrMiss。
+func (s *Store) Get(kind, key string, ttl time.Duration, v any) (time.Time, error) {
+	var e entry
+	found, err := jsonfile.Load(s.path(kind, key), &e)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// 哈希碰撞时 Key 对不上，同样按未命中处理
+	if !found || e.Key != key || s.now().Sub(e.StoredAt) > ttl {
+		return time.Time{}, ErrMiss
+	}
+
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to decode cached data")
+	}
+	return e.StoredAt, nil
+}
+
+// Put 写入缓存，覆盖同 key 的旧值
+func (s *Store) Put(kind, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode cache data")
+	}
+
+	return jsonfile.Save(s.path(kind, key), entry{Key: key, StoredAt: s.now(), Data: data})
+}
+
+// Prune 删除 kind 下写入超过 maxAge 的缓存文件，返回删除的个数。按文件修改时间判断，不用逐个解析
+func (s *Store) Prune(kind string, maxAge time.Duration) (int, error) {
+	dir := filepath.Join(s.dir, kind)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read cache dir %s", dir)
+	}
+
+	removed := 0
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || s.now().Sub(info.ModTime()) <= maxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err == nil {
+			removed++
+		}
+	}
+
+	s.mu.Lock()
+	s.lastPruned[kind] = s.now()
+	s.mu.Unlock()
+	return removed, nil
+}
+
+// PruneIfDue 距上次清理 kind 超过 pruneInterval 时清理一次，供写入后调用
+func (s *Store) PruneIfDue(kind string, maxAge time.Duration) (int, error) {
+	s.mu.Lock()
+	due := s.now().Sub(s.lastPruned[kind]) >= pruneInterval
+	s.mu.Unlock()
+	if !due {
+		return 0, nil
+	}
+	return s.Prune(kind, maxAge)
+}
+
+func (s *Store) path(kind, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, kind, hex.EncodeToString(sum[:16])+".json")
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	s := NewStore(t.TempDir())
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	var got []string
+	if _, err := s.Get("feed", "k1", time.Minute, &got); !errors.Is(err, ErrMiss) {
+		t.Fatalf("空缓存应返回 ErrMiss, got %v", err)
+	}
+
+	if err := s.Put("feed", "k1", []string{"a", "b"}); err != nil {
+		t.Fatalf("Put 失败: %v", err)
+	}
+
+	now = now.Add(30 * time.Second)
+	storedAt, err := s.Get("feed", "k1", time.Minute, &got)
+	if err != nil {
+		t.Fatalf("未过期应命中: %v", err)
+	}
+	if len(got) != 2 || !storedAt.Equal(now.Add(-30*time.Second)) {
+		t.Errorf("got %v stored_at %v", got, storedAt)
+	}
+
+	if _, err := s.Get("profile", "k1", time.Minute, &got); !errors.Is(err, ErrMiss) {
+		t.Errorf("不同类型互不命中, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := s.Get("feed", "k1", time.Minute, &got); !errors.Is(err, ErrMiss) {
+		t.Errorf("过期应返回 ErrMiss, got %v", err)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for in, want := range map[string]Mode{"": ModeBypass, "Prefer": ModePrefer, "Bypass": ModeBypass, "only": ModeOnly} {
+		if got, err := ParseMode(in); err != nil || got != want {
+			t.Errorf("ParseMode(%q) = %q %v, expected %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseMode("always"); err == nil {
+		t.Errorf("未知策略应报错")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s := NewStore(t.TempDir())
+	if n, err := s.Prune("feed", time.Minute); err != nil || n != 0 {
+		t.Fatalf("目录不存在时应直接返回, got %d %v", n, err)
+	}
+
+	for _, k := range []string{"old", "new"} {
+		if err := s.Put("feed", k, k); err != nil {
+			t.Fatalf("Put 失败: %v", err)
+		}
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(s.path("feed", "old"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := s.Prune("feed", 30*time.Minute)
+	if err != nil || n != 1 {
+		t.Fatalf("应删除 1 个过期文件, got %d %v", n, err)
+	}
+	var got string
+	if _, err := s.Get("feed", "new", time.Hour, &got); err != nil {
+		t.Errorf("未过期的应保留: %v", err)
+	}
+
+	if err := os.Chtimes(s.path("feed", "new"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := s.PruneIfDue("feed", 30*time.Minute); n != 0 {
+		t.Errorf("刚清理过，间隔内不应再清理, got %d", n)
+	}
+}
@@ -0,0 +1,45 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxFetchSize Fetch 读取的上限，字幕这类小文件远小于它
+const maxFetchSize = 10 << 20
+
+var fetchClient = &http.Client{Timeout: 30 * time.Second}
+
+// Fetch 下载小文件到内存，带浏览器请求头。超过 10MB 报错，大文件请用 VideoDownloader。
+// ctx 取消时立即中止下载。
+func Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := newRequest(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := fetchClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch %s", rawURL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch failed with status %d for URL: %s", resp.StatusCode, rawURL)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response")
+	}
+	if len(data) > maxFetchSize {
+		return nil, fmt.Errorf("response too large: over %d bytes", maxFetchSize)
+	}
+	return data, nil
+}
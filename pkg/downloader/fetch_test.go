@@ -0,0 +1,41 @@
+package downloader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sub.srt":
+			w.Write([]byte("1\n00:00:00,000 --> 00:00:01,000\n你好\n"))
+		case "/big":
+			w.Write([]byte(strings.Repeat("x", maxFetchSize+1)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	data, err := Fetch(context.Background(), server.URL+"/sub.srt")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "你好")
+
+	_, err = Fetch(context.Background(), server.URL+"/missing")
+	assert.Error(t, err)
+
+	_, err = Fetch(context.Background(), server.URL+"/big")
+	assert.Error(t, err, "超过上限")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Fetch(ctx, server.URL+"/sub.srt")
+	assert.ErrorIs(t, err, context.Canceled, "请求取消后不再下载")
+}
@@ -44,19 +44,9 @@ func (d *ImageDownloader) DownloadImage(imageURL string) (string, error) {
 		return "", errors.New("invalid image URL format")
 	}
 
-	// 创建请求并设置请求头
-	req, err := http.NewRequest("GET", imageURL, nil)
+	req, err := newRequest(imageURL)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to create request")
-	}
-
-	// 设置 User-Agent，模拟浏览器请求
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-
-	// 设置 Referer，使用图片 URL 的域名
-	parsedURL, _ := url.Parse(imageURL)
-	if parsedURL != nil {
-		req.Header.Set("Referer", fmt.Sprintf("%s://%s/", parsedURL.Scheme, parsedURL.Host))
+		return "", err
 	}
 
 	// 下载图片数据
@@ -103,6 +93,25 @@ func (d *ImageDownloader) DownloadImage(imageURL string) (string, error) {
 	return filePath, nil
 }
 
+// newRequest 创建带浏览器请求头的 GET 请求
+func newRequest(rawURL string) (*http.Request, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	// 设置 User-Agent，模拟浏览器请求
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+
+	// 设置 Referer，使用 URL 的域名
+	parsedURL, _ := url.Parse(rawURL)
+	if parsedURL != nil {
+		req.Header.Set("Referer", fmt.Sprintf("%s://%s/", parsedURL.Scheme, parsedURL.Host))
+	}
+
+	return req, nil
+}
+
 // DownloadImages 批量下载图片
 func (d *ImageDownloader) DownloadImages(imageURLs []string) ([]string, error) {
 	var localPaths []string
@@ -0,0 +1,98 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/h2non/filetype"
+	"github.com/pkg/errors"
+)
+
+// MediaDownloader 把笔记媒体按调用方给的文件名存进目标目录，用于归档。
+//
+// 与 ImageDownloader 的区别：文件名由调用方决定，便于按原顺序编号；
+// 同名文件直接覆盖，重复归档同一篇笔记得到的是同一组文件。
+type MediaDownloader struct {
+	dir        string
+	httpClient *http.Client
+	video      *VideoDownloader
+}
+
+// NewMediaDownloader 创建媒体下载器，目录不存在时自动创建
+func NewMediaDownloader(dir string) (*MediaDownloader, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create dir %s", dir)
+	}
+
+	return &MediaDownloader{
+		dir:        dir,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		// 视频不能用图片那 30 秒的整体超时，但归档时也不能因为一个卡住的连接一直挂着
+		video: &VideoDownloader{httpClient: &http.Client{Timeout: 30 * time.Minute}},
+	}, nil
+}
+
+// Dir 返回保存目录
+func (d *MediaDownloader) Dir() string {
+	return d.dir
+}
+
+// SaveImage 下载图片，存为 <dir>/<name>.<实际格式扩展名>，返回本地路径
+func (d *MediaDownloader) SaveImage(imageURL, name string) (string, error) {
+	if !IsImageURL(imageURL) {
+		return "", errors.New("invalid image URL format")
+	}
+
+	req, err := newRequest(imageURL)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to download image from %s", imageURL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download failed with status %d for URL: %s", resp.StatusCode, imageURL)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read image data")
+	}
+
+	// 扩展名以内容为准：小红书的图片 URL 不带扩展名，实际多为 webp
+	if !filetype.IsImage(data) {
+		return "", errors.New("downloaded file is not a valid image")
+	}
+	kind, err := filetype.Match(data)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to detect file type")
+	}
+
+	filePath := filepath.Join(d.dir, name+"."+kind.Extension)
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", errors.Wrap(err, "failed to save image")
+	}
+
+	return filePath, nil
+}
+
+// SaveVideo 依次尝试流的各个地址下载视频，存为 <dir>/<name>.mp4，返回本地路径。
+// 走 VideoDownloader，边下边写、可续传，不把整个视频读进内存；流带大小时按大小校验。
+func (d *MediaDownloader) SaveVideo(ctx context.Context, stream VideoCandidate, name string) (string, error) {
+	filePath := filepath.Join(d.dir, name+".mp4")
+
+	result, err := d.video.Download(ctx, stream, filePath)
+	if err != nil {
+		return "", err
+	}
+	return result.Path, nil
+}
@@ -0,0 +1,93 @@
+package downloader
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMediaDownloader_SaveImage 文件名由调用方决定，扩展名按内容探测。
+func TestMediaDownloader_SaveImage(t *testing.T) {
+	const pngBase64 = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mP8/x8AAwMCAO7+2X8AAAAASUVORK5CYII="
+	pngData, err := base64.StdEncoding.DecodeString(pngBase64)
+	if err != nil {
+		t.Fatalf("解析测试图片失败: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(pngData)
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "note")
+	d, err := NewMediaDownloader(dir)
+	if err != nil {
+		t.Fatalf("创建下载器失败: %v", err)
+	}
+
+	// URL 不带扩展名，和小红书 CDN 一样
+	got, err := d.SaveImage(server.URL+"/abc", "01")
+	if err != nil {
+		t.Fatalf("下载失败: %v", err)
+	}
+
+	if want := filepath.Join(dir, "01.png"); got != want {
+		t.Errorf("path = %q, expected %q", got, want)
+	}
+}
+
+// TestMediaDownloader_SaveVideo 主地址失败时换下一个，且不留下 .part 残片。
+func TestMediaDownloader_SaveVideo(t *testing.T) {
+	payload := []byte("fake mp4 payload")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/expired" {
+			http.Error(w, "expired", http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	d, err := NewMediaDownloader(dir)
+	if err != nil {
+		t.Fatalf("创建下载器失败: %v", err)
+	}
+
+	got, err := d.SaveVideo(context.Background(), VideoCandidate{URLs: []string{server.URL + "/expired", server.URL + "/backup"}}, "01_live")
+	if err != nil {
+		t.Fatalf("下载失败: %v", err)
+	}
+
+	data, err := os.ReadFile(got)
+	if err != nil {
+		t.Fatalf("读取文件失败: %v", err)
+	}
+	if string(data) != string(payload) {
+		t.Errorf("content = %q, expected %q", data, payload)
+	}
+
+	if _, err := os.Stat(got + ".part"); !os.IsNotExist(err) {
+		t.Errorf(".part 文件应已改名")
+	}
+
+	if _, err := d.SaveVideo(context.Background(), VideoCandidate{URLs: []string{server.URL + "/expired"}}, "02_live"); err == nil {
+		t.Errorf("所有地址都失败时应返回错误")
+	}
+
+	stream := VideoCandidate{URLs: []string{server.URL + "/backup"}, Size: int64(len(payload)) + 1}
+	if _, err := d.SaveVideo(context.Background(), stream, "03_live"); err == nil {
+		t.Errorf("大小与流信息不符时应返回错误")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := d.SaveVideo(ctx, VideoCandidate{URLs: []string{server.URL + "/backup"}}, "04_live"); err == nil {
+		t.Errorf("ctx 取消后应返回错误")
+	}
+}
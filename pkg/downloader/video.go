@@ -0,0 +1,285 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// StreamPolicy 多档视频流的挑选策略
+type StreamPolicy string
+
+const (
+	PolicyBest     StreamPolicy = "best"     // 分辨率最高，同分辨率取码率高的
+	PolicySmallest StreamPolicy = "smallest" // 体积最小
+	PolicyCodec    StreamPolicy = "codec"    // 指定编码里分辨率最高的；没有该编码时回落到 best
+)
+
+// ParseStreamPolicy 解析策略，空串按 best 处理
+func ParseStreamPolicy(s string) (StreamPolicy, error) {
+	switch p := StreamPolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return PolicyBest, nil
+	case PolicyBest, PolicySmallest, PolicyCodec:
+		return p, nil
+	default:
+		return "", fmt.Errorf("不支持的挑选策略 %q，可选: best|smallest|codec", s)
+	}
+}
+
+// VideoCandidate 一档可下载的视频流
+type VideoCandidate struct {
+	Codec   string   `json:"codec"` // h264/h265/av1…
+	Width   int      `json:"width"`
+	Height  int      `json:"height"`
+	Size    int64    `json:"size"`    // 字节，0 表示未知，此时不做大小校验
+	Bitrate int      `json:"bitrate"` // bps
+	URLs    []string `json:"-"`       // 主地址在前，备用地址在后
+}
+
+// codecAliases 编码的常见别名，统一成小红书 stream 的桶名
+var codecAliases = map[string]string{
+	"avc":  "h264",
+	"hevc": "h265",
+	"vvc":  "h266",
+}
+
+// NormalizeCodec 把编码名统一成小写桶名
+func NormalizeCodec(codec string) string {
+	c := strings.ToLower(strings.TrimSpace(codec))
+	if alias, ok := codecAliases[c]; ok {
+		return alias
+	}
+	return c
+}
+
+// SelectStream 按策略从候选里挑一档。没有可用地址的候选不参与挑选。
+func SelectStream(candidates []VideoCandidate, policy StreamPolicy, codec string) (*VideoCandidate, error) {
+	usable := make([]VideoCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if len(c.URLs) > 0 {
+			usable = append(usable, c)
+		}
+	}
+	if len(usable) == 0 {
+		return nil, errors.New("no downloadable video stream")
+	}
+
+	if policy == PolicyCodec {
+		want := NormalizeCodec(codec)
+		var matched []VideoCandidate
+		for _, c := range usable {
+			if NormalizeCodec(c.Codec) == want {
+				matched = append(matched, c)
+			}
+		}
+		if len(matched) > 0 {
+			usable = matched
+		}
+		policy = PolicyBest
+	}
+
+	sort.SliceStable(usable, func(i, j int) bool {
+		a, b := usable[i], usable[j]
+		if policy == PolicySmallest {
+			if a.Size != b.Size {
+				// 未知大小排在最后
+				if a.Size == 0 || b.Size == 0 {
+					return b.Size == 0
+				}
+				return a.Size < b.Size
+			}
+			return a.Width*a.Height > b.Width*b.Height
+		}
+
+		if pa, pb := a.Width*a.Height, b.Width*b.Height; pa != pb {
+			return pa > pb
+		}
+		return a.Bitrate > b.Bitrate
+	})
+
+	chosen := usable[0]
+	return &chosen, nil
+}
+
+// VideoDownloadResult 视频下载结果
+type VideoDownloadResult struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	URL     string `json:"-"`       // 最终成功的地址，带签名，不对外返回
+	Backup  bool   `json:"backup"`  // 是否用上了备用地址
+	Resumed bool   `json:"resumed"` // 是否接着上次的 .part 续传
+}
+
+// VideoDownloader 视频下载器：断点续传、主地址失效回落备用地址、按大小校验
+type VideoDownloader struct {
+	httpClient *http.Client
+	maxSize    int64 // 文件大小上限，0 表示不限制
+}
+
+// NewVideoDownloader 创建视频下载器。
+// 不设整体超时：大视频下几十分钟是正常的，由调用方通过 ctx 控制。
+func NewVideoDownloader() *VideoDownloader {
+	return &VideoDownloader{httpClient: &http.Client{}}
+}
+
+// WithMaxSize 限制下载文件的大小，超过即中止并删除 .part
+func (d *VideoDownloader) WithMaxSize(n int64) *VideoDownloader {
+	d.maxSize = n
+	return d
+}
+
+// Download 把候选流下载到 filePath。
+//
+// 下载过程写 <filePath>.part，中断后再调一次会从 .part 的长度接着下；
+// 换地址时同样续传，因为主备地址指向的是同一个文件。
+func (d *VideoDownloader) Download(ctx context.Context, c VideoCandidate, filePath string) (*VideoDownloadResult, error) {
+	if len(c.URLs) == 0 {
+		return nil, errors.New("no video URL")
+	}
+
+	partPath := filePath + ".part"
+	startOffset := fileSize(partPath)
+
+	var lastErr error
+	for i, u := range c.URLs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		written, err := d.fetchRange(ctx, u, partPath, c.Size)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if c.Size > 0 && written != c.Size {
+			if written > c.Size {
+				// 比预期还大，说明文件不是同一个，续传也救不回来
+				os.Remove(partPath)
+			}
+			lastErr = fmt.Errorf("size mismatch: got %d bytes, expected %d", written, c.Size)
+			continue
+		}
+
+		if err := os.Rename(partPath, filePath); err != nil {
+			return nil, errors.Wrap(err, "failed to rename downloaded file")
+		}
+
+		return &VideoDownloadResult{
+			Path:    filePath,
+			Size:    written,
+			URL:     u,
+			Backup:  i > 0,
+			Resumed: startOffset > 0,
+		}, nil
+	}
+
+	return nil, errors.Wrapf(lastErr, "all %d video URLs failed", len(c.URLs))
+}
+
+// fetchRange 从 .part 当前长度开始续传，返回下载完成后 .part 的总长度。
+// 服务端不支持 Range（回 200）时从头重下。
+func (d *VideoDownloader) fetchRange(ctx context.Context, rawURL, partPath string, expected int64) (int64, error) {
+	offset := fileSize(partPath)
+	if expected > 0 && offset == expected {
+		return offset, nil
+	}
+
+	req, err := newRequest(rawURL)
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to download %s", rawURL)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || start != offset {
+			return 0, fmt.Errorf("unexpected Content-Range %q for offset %d", resp.Header.Get("Content-Range"), offset)
+		}
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+		flags |= os.O_TRUNC
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// .part 已经不短于远端文件，交给上层按大小判断
+		return offset, nil
+	default:
+		return 0, fmt.Errorf("download failed with status %d for URL: %s", resp.StatusCode, rawURL)
+	}
+
+	start := int64(0)
+	if flags&os.O_APPEND != 0 {
+		start = offset
+	}
+	if d.maxSize > 0 && resp.ContentLength > 0 && start+resp.ContentLength > d.maxSize {
+		return 0, fmt.Errorf("file too large: %d bytes, limit %d", start+resp.ContentLength, d.maxSize)
+	}
+
+	f, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to open file")
+	}
+
+	body := io.Reader(resp.Body)
+	if d.maxSize > 0 {
+		// 多读 1 字节用来判断是否超限
+		body = io.LimitReader(resp.Body, d.maxSize-start+1)
+	}
+
+	// 中途断开时保留已写入部分，下次续传
+	_, copyErr := io.Copy(f, body)
+	closeErr := f.Close()
+	if copyErr != nil {
+		return 0, errors.Wrapf(copyErr, "failed to write %s", rawURL)
+	}
+	if closeErr != nil {
+		return 0, errors.Wrap(closeErr, "failed to close file")
+	}
+
+	written := fileSize(partPath)
+	if d.maxSize > 0 && written > d.maxSize {
+		os.Remove(partPath)
+		return 0, fmt.Errorf("file too large: over %d bytes", d.maxSize)
+	}
+	return written, nil
+}
+
+// contentRangeStart 解析 "bytes 100-199/200" 的起始偏移
+func contentRangeStart(header string) (int64, bool) {
+	rest, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	startStr, _, ok := strings.Cut(rest, "-")
+	if !ok {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	return start, err == nil
+}
+
+// fileSize 文件不存在时返回 0
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
@@ -0,0 +1,227 @@
+package downloader
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/h2non/filetype"
+	"github.com/pkg/errors"
+)
+
+// RemoteVideo 远程视频下载到本地的结果
+type RemoteVideo struct {
+	URL         string `json:"url"`
+	Path        string `json:"path"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type,omitempty"` // 服务端声明的类型，HEAD 不可用时为空
+	Resumed     bool   `json:"resumed"`                // 是否接着上次中断的 .part 续传
+}
+
+// staleFetchAge 目录里超过这个时间的下载残片和没删掉的视频会在下次下载时清理
+const staleFetchAge = 24 * time.Hour
+
+// fetchLocks 每个下载路径一把锁。同一地址并发发布时排队下载，不会同时写一个 .part
+var fetchLocks = struct {
+	sync.Mutex
+	m map[string]chan struct{}
+}{m: make(map[string]chan struct{})}
+
+// lockFetch 占用 path 的下载，等待期间 ctx 取消则放弃
+func lockFetch(ctx context.Context, path string) (func(), error) {
+	fetchLocks.Lock()
+	ch, ok := fetchLocks.m[path]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		fetchLocks.m[path] = ch
+	}
+	fetchLocks.Unlock()
+
+	select {
+	case ch <- struct{}{}:
+		return func() { <-ch }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// VideoFetcher 把 http(s) 地址的视频下载到本地，供发布上传使用。
+//
+// 下载中的 .part 只由 URL 决定，同一地址下载中断后重试会接着续传；同一地址同时只有一个下载。
+// 下载完成后改名时加随机后缀，每次调用拿到自己的文件，用完各自删除互不影响。
+type VideoFetcher struct {
+	dir        string
+	maxSize    int64
+	headClient *http.Client
+}
+
+// NewVideoFetcher 创建远程视频下载器，maxSize 为 0 表示不限制大小
+func NewVideoFetcher(dir string, maxSize int64) *VideoFetcher {
+	return &VideoFetcher{
+		dir:        dir,
+		maxSize:    maxSize,
+		headClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// IsRemoteURL 判断是否为 http(s) 地址
+func IsRemoteURL(path string) bool {
+	return IsImageURL(path)
+}
+
+// Fetch 下载视频：先用 HEAD 预检类型和大小，再断点续传下载，最后按文件头确认确实是视频
+func (f *VideoFetcher) Fetch(ctx context.Context, rawURL string) (*RemoteVideo, error) {
+	if !IsRemoteURL(rawURL) {
+		return nil, errors.New("invalid video URL format")
+	}
+	if err := os.MkdirAll(f.dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "failed to create video dir")
+	}
+
+	f.removeStale()
+
+	size, contentType, err := f.head(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	base := f.basePath(rawURL)
+	downloadPath := base + ".download"
+
+	unlock, err := lockFetch(ctx, downloadPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	result, err := NewVideoDownloader().WithMaxSize(f.maxSize).Download(ctx, VideoCandidate{
+		Size: size,
+		URLs: []string{rawURL},
+	}, downloadPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ext, err := sniffVideo(downloadPath)
+	if err != nil {
+		os.Remove(downloadPath)
+		return nil, err
+	}
+
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	finalPath := fmt.Sprintf("%s_%x.%s", base, suffix, ext)
+	if err := os.Rename(downloadPath, finalPath); err != nil {
+		return nil, errors.Wrap(err, "failed to rename downloaded video")
+	}
+
+	return &RemoteVideo{
+		URL:         rawURL,
+		Path:        finalPath,
+		Size:        result.Size,
+		ContentType: contentType,
+		Resumed:     result.Resumed,
+	}, nil
+}
+
+// basePath 不含扩展名的本地路径，只由 URL 决定
+func (f *VideoFetcher) basePath(rawURL string) string {
+	hash := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(f.dir, fmt.Sprintf("video_%x", hash[:8]))
+}
+
+// removeStale 删除超过 staleFetchAge 的下载残片和视频：放弃重试的 .part、服务崩溃时没来得及删的视频
+func (f *VideoFetcher) removeStale() {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), "video_") {
+			continue
+		}
+		if info, err := e.Info(); err == nil && time.Since(info.ModTime()) > staleFetchAge {
+			os.Remove(filepath.Join(f.dir, e.Name()))
+		}
+	}
+}
+
+// head 预检远端大小和类型。
+// 预签名 URL 常常只对 GET 签名，HEAD 会被拒，这时不算失败，交给下载后的文件头校验。
+func (f *VideoFetcher) head(ctx context.Context, rawURL string) (int64, string, error) {
+	req, err := newRequest(rawURL)
+	if err != nil {
+		return 0, "", err
+	}
+	req = req.WithContext(ctx)
+	req.Method = http.MethodHead
+
+	resp, err := f.headClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, "", ctx.Err()
+		}
+		return 0, "", nil
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, "", nil
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !isVideoContentType(contentType) {
+		return 0, "", fmt.Errorf("URL is not a video (content-type: %s)", contentType)
+	}
+
+	size := max(resp.ContentLength, 0)
+	if f.maxSize > 0 && size > f.maxSize {
+		return 0, "", fmt.Errorf("video too large: %d bytes, limit %d", size, f.maxSize)
+	}
+	return size, contentType, nil
+}
+
+// isVideoContentType 对象存储常把视频标成通用二进制类型，这些也放行
+func isVideoContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/octet-stream", "binary/octet-stream", "application/mp4":
+		return true
+	}
+	return strings.HasPrefix(mediaType, "video/")
+}
+
+// sniffVideo 按文件头判断是否为视频，返回扩展名
+func sniffVideo(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open downloaded video")
+	}
+	defer file.Close()
+
+	head := make([]byte, 262)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", errors.Wrap(err, "failed to read downloaded video")
+	}
+
+	kind, err := filetype.Match(head[:n])
+	if err != nil || !filetype.IsVideo(head[:n]) {
+		return "", errors.New("downloaded file is not a valid video")
+	}
+	return kind.Extension, nil
+}
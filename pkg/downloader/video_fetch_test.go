@@ -0,0 +1,179 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mp4Payload 带 ftyp 头的最小"视频"，足够让文件头识别为 mp4
+func mp4Payload(n int) []byte {
+	head := []byte{0, 0, 0, 0x18, 'f', 't', 'y', 'p', 'i', 's', 'o', 'm', 0, 0, 2, 0, 'i', 's', 'o', 'm', 'm', 'p', '4', '1'}
+	return append(head, bytes.Repeat([]byte{0}, n)...)
+}
+
+func fetchServer(t *testing.T, payload []byte, contentType string, allowHead bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead && !allowHead {
+			http.Error(w, "signature mismatch", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(payload))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVideoFetcher_Fetch(t *testing.T) {
+	payload := mp4Payload(1000)
+	server := fetchServer(t, payload, "video/mp4", true)
+
+	dir := t.TempDir()
+	video, err := NewVideoFetcher(dir, 0).Fetch(context.Background(), server.URL+"/a.mp4")
+	if err != nil {
+		t.Fatalf("下载失败: %v", err)
+	}
+
+	if filepath.Ext(video.Path) != ".mp4" {
+		t.Errorf("扩展名应按文件头识别为 mp4, got %s", video.Path)
+	}
+	if video.Size != int64(len(payload)) || video.ContentType != "video/mp4" {
+		t.Errorf("got size=%d type=%q", video.Size, video.ContentType)
+	}
+	if _, err := os.Stat(NewVideoFetcher(dir, 0).basePath(server.URL+"/a.mp4") + ".download.part"); !os.IsNotExist(err) {
+		t.Errorf("下载完成后不应残留 .part")
+	}
+}
+
+func TestVideoFetcher_ResumeWithoutHead(t *testing.T) {
+	payload := mp4Payload(1000)
+	server := fetchServer(t, payload, "binary/octet-stream", false)
+
+	dir := t.TempDir()
+	fetcher := NewVideoFetcher(dir, 0)
+	rawURL := server.URL + "/signed?sig=abc"
+
+	// 伪造一半的 .part 模拟中断
+	part := fetcher.basePath(rawURL) + ".download.part"
+	if err := os.WriteFile(part, payload[:400], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	video, err := fetcher.Fetch(context.Background(), rawURL)
+	if err != nil {
+		t.Fatalf("续传失败: %v", err)
+	}
+	if !video.Resumed {
+		t.Errorf("应标记为续传")
+	}
+	data, _ := os.ReadFile(video.Path)
+	if !bytes.Equal(data, payload) {
+		t.Errorf("续传后的内容与原文件不一致")
+	}
+}
+
+// TestVideoFetcher_Concurrent 同一地址并发下载各自拿到完整的文件，删掉一个不影响另一个。
+func TestVideoFetcher_Concurrent(t *testing.T) {
+	payload := mp4Payload(200000)
+	server := fetchServer(t, payload, "video/mp4", true)
+	fetcher := NewVideoFetcher(t.TempDir(), 0)
+
+	var wg sync.WaitGroup
+	videos := make([]*RemoteVideo, 4)
+	errs := make([]error, len(videos))
+	for i := range videos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			videos[i], errs[i] = fetcher.Fetch(context.Background(), server.URL+"/same.mp4")
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for i, v := range videos {
+		if errs[i] != nil {
+			t.Fatalf("第 %d 个下载失败: %v", i, errs[i])
+		}
+		if seen[v.Path] {
+			t.Errorf("并发下载拿到了同一个文件 %s", v.Path)
+		}
+		seen[v.Path] = true
+		data, _ := os.ReadFile(v.Path)
+		if !bytes.Equal(data, payload) {
+			t.Errorf("第 %d 个文件内容不完整", i)
+		}
+	}
+}
+
+// TestVideoFetcher_RemoveStale 过期的残片在下次下载时删除，新的保留。
+func TestVideoFetcher_RemoveStale(t *testing.T) {
+	payload := mp4Payload(100)
+	server := fetchServer(t, payload, "video/mp4", true)
+	dir := t.TempDir()
+
+	stale := filepath.Join(dir, "video_0000.download.part")
+	fresh := filepath.Join(dir, "video_1111.download.part")
+	for _, p := range []string{stale, fresh} {
+		if err := os.WriteFile(p, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * staleFetchAge)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewVideoFetcher(dir, 0).Fetch(context.Background(), server.URL+"/v.mp4"); err != nil {
+		t.Fatalf("下载失败: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("过期的 .part 应已删除")
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Errorf("未过期的 .part 应保留: %v", err)
+	}
+}
+
+func TestVideoFetcher_Rejects(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     []byte
+		contentType string
+		allowHead   bool
+		maxSize     int64
+	}{
+		{name: "HEAD 声明是网页", payload: mp4Payload(10), contentType: "text/html; charset=utf-8", allowHead: true},
+		{name: "HEAD 声明超限", payload: mp4Payload(1000), contentType: "video/mp4", allowHead: true, maxSize: 100},
+		{name: "无 HEAD 时下载超限", payload: mp4Payload(1000), contentType: "video/mp4", maxSize: 100},
+		{name: "文件头不是视频", payload: []byte("<html>login required</html>"), contentType: "application/octet-stream", allowHead: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := fetchServer(t, tt.payload, tt.contentType, tt.allowHead)
+			dir := t.TempDir()
+
+			if _, err := NewVideoFetcher(dir, tt.maxSize).Fetch(context.Background(), server.URL+"/v"); err == nil {
+				t.Fatalf("应返回错误")
+			}
+			entries, _ := os.ReadDir(dir)
+			if len(entries) != 0 {
+				t.Errorf("失败后不应留下文件, got %d", len(entries))
+			}
+		})
+	}
+}
+
+func TestVideoFetcher_InvalidURL(t *testing.T) {
+	if _, err := NewVideoFetcher(t.TempDir(), 0).Fetch(context.Background(), "/local/video.mp4"); err == nil {
+		t.Errorf("本地路径应报错")
+	}
+}
@@ -0,0 +1,176 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSelectStream(t *testing.T) {
+	candidates := []VideoCandidate{
+		{Codec: "h264", Width: 1280, Height: 720, Size: 3000, Bitrate: 1000, URLs: []string{"h264-720"}},
+		{Codec: "h264", Width: 1920, Height: 1080, Size: 6000, Bitrate: 2000, URLs: []string{"h264-1080"}},
+		{Codec: "h265", Width: 1920, Height: 1080, Size: 4000, Bitrate: 2500, URLs: []string{"h265-1080"}},
+		{Codec: "av1", Width: 1280, Height: 720, Size: 2000, Bitrate: 800, URLs: []string{"av1-720"}},
+		{Codec: "av1", Width: 3840, Height: 2160, Size: 1, Bitrate: 9000}, // 没有地址，不参与
+	}
+
+	tests := []struct {
+		name   string
+		policy StreamPolicy
+		codec  string
+		want   string
+	}{
+		{"best 取分辨率最高，同分辨率比码率", PolicyBest, "", "h265-1080"},
+		{"smallest 取体积最小", PolicySmallest, "", "av1-720"},
+		{"codec 在指定编码里取最好", PolicyCodec, "h264", "h264-1080"},
+		{"codec 认别名", PolicyCodec, "AVC", "h264-1080"},
+		{"codec 不存在时回落 best", PolicyCodec, "h266", "h265-1080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectStream(candidates, tt.policy, tt.codec)
+			if err != nil {
+				t.Fatalf("SelectStream 失败: %v", err)
+			}
+			if got.URLs[0] != tt.want {
+				t.Errorf("选中 %s, expected %s", got.URLs[0], tt.want)
+			}
+		})
+	}
+
+	if _, err := SelectStream([]VideoCandidate{{Codec: "h264"}}, PolicyBest, ""); err == nil {
+		t.Errorf("没有可用地址时应返回错误")
+	}
+}
+
+func TestSelectStream_SmallestUnknownSizeLast(t *testing.T) {
+	got, err := SelectStream([]VideoCandidate{
+		{Size: 0, URLs: []string{"unknown"}},
+		{Size: 500, URLs: []string{"known"}},
+	}, PolicySmallest, "")
+	if err != nil {
+		t.Fatalf("SelectStream 失败: %v", err)
+	}
+	if got.URLs[0] != "known" {
+		t.Errorf("未知大小应排在最后，选中 %s", got.URLs[0])
+	}
+}
+
+func TestParseStreamPolicy(t *testing.T) {
+	if p, err := ParseStreamPolicy(""); err != nil || p != PolicyBest {
+		t.Errorf("空串应为 best, got %q %v", p, err)
+	}
+	if p, err := ParseStreamPolicy(" Smallest "); err != nil || p != PolicySmallest {
+		t.Errorf("应忽略大小写和空白, got %q %v", p, err)
+	}
+	if _, err := ParseStreamPolicy("fastest"); err == nil {
+		t.Errorf("未知策略应报错")
+	}
+}
+
+// videoServer 支持 Range 的文件服务；/expired 模拟签名过期的主地址
+func videoServer(t *testing.T, payload []byte) (*httptest.Server, *[]string) {
+	t.Helper()
+	var ranges []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/expired" {
+			http.Error(w, "expired", http.StatusForbidden)
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "v.mp4", time.Time{}, bytes.NewReader(payload))
+	}))
+	t.Cleanup(server.Close)
+	return server, &ranges
+}
+
+func TestVideoDownloader_FallbackToBackup(t *testing.T) {
+	payload := []byte(strings.Repeat("0123456789", 100))
+	server, _ := videoServer(t, payload)
+
+	path := filepath.Join(t.TempDir(), "v.mp4")
+	result, err := NewVideoDownloader().Download(context.Background(), VideoCandidate{
+		Size: int64(len(payload)),
+		URLs: []string{server.URL + "/expired", server.URL + "/backup"},
+	}, path)
+	if err != nil {
+		t.Fatalf("下载失败: %v", err)
+	}
+
+	if !result.Backup {
+		t.Errorf("应标记为使用了备用地址")
+	}
+	if result.Size != int64(len(payload)) {
+		t.Errorf("size = %d, expected %d", result.Size, len(payload))
+	}
+}
+
+func TestVideoDownloader_Resume(t *testing.T) {
+	payload := []byte(strings.Repeat("abcdefghij", 100))
+	server, ranges := videoServer(t, payload)
+
+	path := filepath.Join(t.TempDir(), "v.mp4")
+	// 模拟上次下到一半中断
+	if err := os.WriteFile(path+".part", payload[:300], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewVideoDownloader().Download(context.Background(), VideoCandidate{
+		Size: int64(len(payload)),
+		URLs: []string{server.URL + "/v"},
+	}, path)
+	if err != nil {
+		t.Fatalf("下载失败: %v", err)
+	}
+
+	if !result.Resumed {
+		t.Errorf("应标记为续传")
+	}
+	if len(*ranges) != 1 || (*ranges)[0] != "bytes=300-" {
+		t.Errorf("应从 300 字节处续传, got ranges %v", *ranges)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, payload) {
+		t.Errorf("续传后的内容与原文件不一致")
+	}
+}
+
+func TestVideoDownloader_SizeMismatch(t *testing.T) {
+	payload := []byte("short")
+	server, _ := videoServer(t, payload)
+
+	path := filepath.Join(t.TempDir(), "v.mp4")
+	_, err := NewVideoDownloader().Download(context.Background(), VideoCandidate{
+		Size: 100,
+		URLs: []string{server.URL + "/v"},
+	}, path)
+	if err == nil {
+		t.Fatalf("大小不符时应报错")
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("校验失败时不应产出最终文件")
+	}
+}
+
+func TestContentRangeStart(t *testing.T) {
+	if start, ok := contentRangeStart("bytes 300-999/1000"); !ok || start != 300 {
+		t.Errorf("got %d %v", start, ok)
+	}
+	if _, ok := contentRangeStart("items 1-2/3"); ok {
+		t.Errorf("非 bytes 单位应解析失败")
+	}
+}
@@ -0,0 +1,157 @@
+// Package drafts 在本地保存图文草稿：表单内容存 drafts.json，图片复制到 <id>/ 目录。
+//
+// 创作中心的草稿箱存在浏览器本地，而这里每次操作都起一个全新的浏览器，
+// 关掉后草稿就没了，所以草稿只存在本地，不进创作中心的草稿箱，发布时重新填表。
+package drafts
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/jsonfile"
+)
+
+// Draft 一份图文草稿
+type Draft struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Content    string   `json:"content"`
+	Images     []string `json:"images"` // 草稿目录下的本地路径，不受临时目录清理影响
+	Tags       []string `json:"tags,omitempty"`
+	IsOriginal bool     `json:"is_original,omitempty"`
+	Visibility string   `json:"visibility,omitempty"`
+	Products   []string `json:"products,omitempty"`
+	Location   string   `json:"location,omitempty"`
+	Collection string   `json:"collection,omitempty"`
+	// CreateCollection 合集不存在时在发布草稿时新建
+	CreateCollection bool      `json:"create_collection,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type draftsFile struct {
+	Drafts []Draft `json:"drafts"`
+}
+
+// Store 草稿存储，并发安全
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewStore 创建存储，目录在第一次写入时创建
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// NewID 生成草稿 ID
+func NewID() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// AssetDir 草稿图片目录
+func (s *Store) AssetDir(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id))
+}
+
+// Save 保存草稿，ID 已存在时覆盖
+func (s *Store) Save(draft Draft) error {
+	if draft.ID == "" {
+		return fmt.Errorf("草稿 ID 不能为空")
+	}
+	if draft.CreatedAt.IsZero() {
+		draft.CreatedAt = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i := range list.Drafts {
+		if list.Drafts[i].ID == draft.ID {
+			list.Drafts[i] = draft
+			return s.save(list)
+		}
+	}
+	list.Drafts = append(list.Drafts, draft)
+	return s.save(list)
+}
+
+// Get 取一份草稿，不存在时返回 false
+func (s *Store) Get(id string) (Draft, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return Draft{}, false, err
+	}
+	for _, d := range list.Drafts {
+		if d.ID == id {
+			return d, true, nil
+		}
+	}
+	return Draft{}, false, nil
+}
+
+// List 返回全部草稿，新的在前
+func (s *Store) List() ([]Draft, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(list.Drafts, func(i, j int) bool {
+		return list.Drafts[i].CreatedAt.After(list.Drafts[j].CreatedAt)
+	})
+	return list.Drafts, nil
+}
+
+// Delete 删除草稿及其图片目录，返回是否存在
+func (s *Store) Delete(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	for i, d := range list.Drafts {
+		if d.ID != id {
+			continue
+		}
+		list.Drafts = append(list.Drafts[:i], list.Drafts[i+1:]...)
+		if err := s.save(list); err != nil {
+			return false, err
+		}
+		if err := os.RemoveAll(s.AssetDir(id)); err != nil {
+			return true, fmt.Errorf("删除草稿图片失败: %w", err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (s *Store) load() (*draftsFile, error) {
+	var list draftsFile
+	if _, err := jsonfile.Load(filepath.Join(s.dir, "drafts.json"), &list); err != nil {
+		return nil, fmt.Errorf("读取草稿失败: %w", err)
+	}
+	return &list, nil
+}
+
+func (s *Store) save(list *draftsFile) error {
+	return jsonfile.Save(filepath.Join(s.dir, "drafts.json"), list)
+}
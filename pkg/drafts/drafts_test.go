@@ -0,0 +1,46 @@
+package drafts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	s := NewStore(t.TempDir())
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, s.Save(Draft{ID: "a", Title: "旧", CreatedAt: t0}))
+	require.NoError(t, s.Save(Draft{ID: "b", Title: "新", CreatedAt: t0.Add(time.Hour)}))
+	require.NoError(t, s.Save(Draft{ID: "a", Title: "改过", CreatedAt: t0}))
+	assert.Error(t, s.Save(Draft{Title: "缺 ID"}))
+
+	list, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "b", list[0].ID, "新的在前")
+	assert.Equal(t, "改过", list[1].Title, "同 ID 覆盖")
+
+	require.NoError(t, os.MkdirAll(s.AssetDir("a"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(s.AssetDir("a"), "01.jpg"), []byte("x"), 0644))
+
+	deleted, err := s.Delete("a")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	_, err = os.Stat(s.AssetDir("a"))
+	assert.True(t, os.IsNotExist(err), "图片目录一起删")
+
+	_, ok, err := s.Get("a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	deleted, err = s.Delete("a")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	assert.NotEqual(t, NewID(), NewID())
+}
@@ -0,0 +1,154 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// SheetFormat 表格格式
+type SheetFormat string
+
+const (
+	SheetCSV  SheetFormat = "csv"
+	SheetXLSX SheetFormat = "xlsx"
+)
+
+// ParseSheetFormat 解析表格格式，空串按 csv 处理
+func ParseSheetFormat(s string) (SheetFormat, error) {
+	switch f := SheetFormat(strings.ToLower(strings.TrimSpace(s))); f {
+	case "":
+		return SheetCSV, nil
+	case SheetCSV, SheetXLSX:
+		return f, nil
+	default:
+		return "", fmt.Errorf("不支持的表格格式 %q，可选: csv|xlsx", s)
+	}
+}
+
+// commentColumns 表头，顺序与 CommentRow.values 一致
+var commentColumns = []string{
+	"id", "parent_id", "depth", "user_id", "nickname", "content",
+	"like_count", "ip_location", "created_at", "show_tags",
+}
+
+// CommentRow 摊平后的一行评论
+type CommentRow struct {
+	ID         string
+	ParentID   string // 一级评论为空
+	Depth      int    // 一级评论为 1，回复为 2
+	UserID     string
+	Nickname   string
+	Content    string
+	LikeCount  string
+	IPLocation string
+	CreatedAt  int64 // 毫秒时间戳
+	ShowTags   []string
+}
+
+func (r CommentRow) values() []string {
+	created := ""
+	if r.CreatedAt > 0 {
+		created = time.UnixMilli(r.CreatedAt).Format("2006-01-02 15:04:05")
+	}
+	return []string{
+		r.ID, r.ParentID, fmt.Sprint(r.Depth), r.UserID, r.Nickname, r.Content,
+		r.LikeCount, r.IPLocation, created, strings.Join(r.ShowTags, ","),
+	}
+}
+
+// FlattenComments 按页面顺序摊平评论树，每条回复紧跟在所属一级评论后面
+func FlattenComments(list []xiaohongshu.Comment) []CommentRow {
+	var rows []CommentRow
+	var walk func(c xiaohongshu.Comment, parentID string, depth int)
+	walk = func(c xiaohongshu.Comment, parentID string, depth int) {
+		rows = append(rows, CommentRow{
+			ID:         c.ID,
+			ParentID:   parentID,
+			Depth:      depth,
+			UserID:     c.UserInfo.UserID,
+			Nickname:   c.UserInfo.Nickname,
+			Content:    c.Content,
+			LikeCount:  c.LikeCount,
+			IPLocation: c.IPLocation,
+			CreatedAt:  c.CreateTime,
+			ShowTags:   c.ShowTags,
+		})
+		for _, sub := range c.SubComments {
+			walk(sub, c.ID, depth+1)
+		}
+	}
+
+	for _, c := range list {
+		walk(c, "", 1)
+	}
+	return rows
+}
+
+// CommentWriter 逐行写出评论，不在内存里攒整张表
+type CommentWriter interface {
+	Write(row CommentRow) error
+	// Close 写出收尾内容并刷新缓冲，不关闭底层 io.Writer
+	Close() error
+}
+
+// NewCommentWriter 按格式创建写入器，表头在创建时写出
+func NewCommentWriter(w io.Writer, format SheetFormat) (CommentWriter, error) {
+	switch format {
+	case SheetCSV:
+		return newCSVWriter(w)
+	case SheetXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("不支持的表格格式 %q", format)
+	}
+}
+
+type csvCommentWriter struct {
+	buf *bufio.Writer
+	csv *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvCommentWriter, error) {
+	buf := bufio.NewWriter(w)
+	// UTF-8 BOM：没有它 Excel 会按本地编码打开，中文全是乱码
+	if _, err := buf.WriteString("\ufeff"); err != nil {
+		return nil, err
+	}
+
+	cw := &csvCommentWriter{buf: buf, csv: csv.NewWriter(buf)}
+	if err := cw.csv.Write(commentColumns); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (w *csvCommentWriter) Write(row CommentRow) error {
+	values := row.values()
+	for i, v := range values {
+		values[i] = escapeFormula(v)
+	}
+	return w.csv.Write(values)
+}
+
+func (w *csvCommentWriter) Close() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
+
+// escapeFormula 以 = + - @ 开头的单元格在 Excel 里会被当成公式执行，
+// 评论内容是任何人都能写的，前面补一个 ' 让它按文本显示。
+func escapeFormula(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
@@ -0,0 +1,107 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+var sampleComments = []xiaohongshu.Comment{
+	{
+		ID: "c1", Content: "=HYPERLINK(\"x\")", LikeCount: "12", IPLocation: "上海",
+		UserInfo: xiaohongshu.User{UserID: "u1", Nickname: "路人"},
+		ShowTags: []string{"is_author"},
+		SubComments: []xiaohongshu.Comment{
+			{ID: "c1-1", Content: "回复 <b>&", UserInfo: xiaohongshu.User{UserID: "u2"}},
+		},
+	},
+	{ID: "c2", Content: "第二条"},
+}
+
+func TestFlattenComments(t *testing.T) {
+	rows := FlattenComments(sampleComments)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, []string{"c1", "c1-1", "c2"}, []string{rows[0].ID, rows[1].ID, rows[2].ID}, "回复紧跟在所属评论后面")
+	assert.Equal(t, "", rows[0].ParentID)
+	assert.Equal(t, 1, rows[0].Depth)
+	assert.Equal(t, "c1", rows[1].ParentID)
+	assert.Equal(t, 2, rows[1].Depth)
+}
+
+func writeComments(t *testing.T, format SheetFormat) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewCommentWriter(&buf, format)
+	require.NoError(t, err)
+	for _, row := range FlattenComments(sampleComments) {
+		require.NoError(t, w.Write(row))
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestCommentWriter_CSV(t *testing.T) {
+	data := writeComments(t, SheetCSV)
+	require.True(t, bytes.HasPrefix(data, []byte("\ufeff")), "需要 BOM")
+
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff")))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+
+	assert.Equal(t, commentColumns, records[0])
+	assert.Equal(t, "'=HYPERLINK(\"x\")", records[1][5], "公式开头要转义")
+	assert.Equal(t, "is_author", records[1][9])
+	assert.Equal(t, "c1", records[2][1])
+}
+
+func TestCommentWriter_XLSX(t *testing.T) {
+	data := writeComments(t, SheetXLSX)
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			b, _ := io.ReadAll(rc)
+			rc.Close()
+			sheet = string(b)
+		}
+	}
+	require.NotEmpty(t, sheet)
+
+	assert.Equal(t, 4, strings.Count(sheet, "<row "))
+	assert.Contains(t, sheet, `<c r="J2" t="inlineStr"><is><t xml:space="preserve">is_author</t></is></c>`)
+	assert.Contains(t, sheet, "回复 &lt;b&gt;&amp;", "内容要做 XML 转义")
+	assert.True(t, strings.HasSuffix(sheet, "</sheetData></worksheet>"))
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+}
+
+func TestParseSheetFormat(t *testing.T) {
+	f, err := ParseSheetFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, SheetCSV, f)
+
+	f, err = ParseSheetFormat("XLSX")
+	assert.NoError(t, err)
+	assert.Equal(t, SheetXLSX, f)
+
+	_, err = ParseSheetFormat("xls")
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+// Package export 把笔记详情导出成便于归档的文件格式。
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+	"gopkg.in/yaml.v3"
+)
+
+// 资源类型，与笔记媒体归档的清单一致
+const (
+	AssetImage     = "image"
+	AssetLiveVideo = "live_video"
+)
+
+// Asset 已下载到本地的笔记媒体
+type Asset struct {
+	Index int    // 在笔记里的序号，从 1 开始
+	Type  string // AssetImage | AssetLiveVideo
+	Link  string // Markdown 里引用的路径，相对 .md 文件；下载失败时可填远程地址
+}
+
+// MarkdownOptions 导出选项
+type MarkdownOptions struct {
+	Assets     []Asset
+	Comments   []xiaohongshu.Comment // 为空不输出评论区
+	ExportedAt time.Time
+}
+
+// frontMatter 字段顺序即输出顺序
+type frontMatter struct {
+	Title          string   `yaml:"title"`
+	NoteID         string   `yaml:"note_id"`
+	Source         string   `yaml:"source"`
+	Type           string   `yaml:"type"`
+	Author         string   `yaml:"author"`
+	AuthorID       string   `yaml:"author_id"`
+	Published      string   `yaml:"published,omitempty"`
+	IPLocation     string   `yaml:"ip_location,omitempty"`
+	LikedCount     string   `yaml:"liked_count"`
+	CollectedCount string   `yaml:"collected_count"`
+	CommentCount   string   `yaml:"comment_count"`
+	SharedCount    string   `yaml:"shared_count"`
+	Tags           []string `yaml:"tags,omitempty"`
+	Exported       string   `yaml:"exported"`
+}
+
+// 正文里的话题写作 #露营[话题]#，转成 Obsidian 能识别的 #露营
+var topicRegex = regexp.MustCompile(`#([^#\[\]]+)\[话题\]#`)
+
+// Markdown 把笔记渲染成带 front matter 的 Markdown
+func Markdown(note xiaohongshu.FeedDetail, opts MarkdownOptions) ([]byte, error) {
+	fm := frontMatter{
+		Title:          note.Title,
+		NoteID:         note.NoteID,
+		Source:         "https://www.xiaohongshu.com/explore/" + note.NoteID,
+		Type:           note.Type,
+		Author:         note.User.Nickname,
+		AuthorID:       note.User.UserID,
+		Published:      formatTime(note.Time, time.RFC3339),
+		IPLocation:     note.IPLocation,
+		LikedCount:     note.InteractInfo.LikedCount,
+		CollectedCount: note.InteractInfo.CollectedCount,
+		CommentCount:   note.InteractInfo.CommentCount,
+		SharedCount:    note.InteractInfo.SharedCount,
+		Tags:           tagNames(note.TagList),
+		Exported:       opts.ExportedAt.Format(time.RFC3339),
+	}
+
+	header, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, fmt.Errorf("生成 front matter 失败: %w", err)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("---\n")
+	b.Write(header)
+	b.WriteString("---\n\n")
+
+	title := note.Title
+	if title == "" {
+		title = note.NoteID
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+
+	if desc := strings.TrimSpace(topicRegex.ReplaceAllString(note.Desc, "#$1")); desc != "" {
+		b.WriteString(desc)
+		b.WriteString("\n\n")
+	}
+
+	for _, a := range opts.Assets {
+		switch a.Type {
+		case AssetLiveVideo:
+			fmt.Fprintf(&b, "[实况 %02d](%s)\n\n", a.Index, a.Link)
+		default:
+			fmt.Fprintf(&b, "![%02d](%s)\n\n", a.Index, a.Link)
+		}
+	}
+
+	if len(opts.Comments) > 0 {
+		b.WriteString("## 评论\n\n")
+		for _, c := range opts.Comments {
+			writeComment(&b, c, 1)
+			b.WriteString("\n")
+		}
+	}
+
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
+
+// writeComment 以嵌套引用输出评论，回复比上级多一层 >
+func writeComment(b *bytes.Buffer, c xiaohongshu.Comment, depth int) {
+	prefix := strings.Repeat("> ", depth)
+
+	meta := []string{"**" + c.UserInfo.Nickname + "**"}
+	if c.IPLocation != "" {
+		meta = append(meta, c.IPLocation)
+	}
+	if t := formatTime(c.CreateTime, "2006-01-02 15:04"); t != "" {
+		meta = append(meta, t)
+	}
+	if c.LikeCount != "" && c.LikeCount != "0" {
+		meta = append(meta, "赞 "+c.LikeCount)
+	}
+	b.WriteString(prefix + strings.Join(meta, " · ") + "\n")
+
+	for _, line := range strings.Split(strings.TrimSpace(c.Content), "\n") {
+		b.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+	}
+
+	for _, sub := range c.SubComments {
+		// 空引用行隔开上下级，否则 Markdown 会把回复并进上级的段落
+		b.WriteString(strings.TrimRight(prefix, " ") + "\n")
+		writeComment(b, sub, depth+1)
+	}
+}
+
+// formatTime 页面给的是毫秒时间戳，0 表示没有
+func formatTime(ms int64, layout string) string {
+	if ms <= 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).Format(layout)
+}
+
+func tagNames(tags []xiaohongshu.Tag) []string {
+	var names []string
+	for _, t := range tags {
+		if t.Name != "" {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarkdown(t *testing.T) {
+	note := xiaohongshu.FeedDetail{
+		NoteID:     "64f1",
+		Title:      "周末露营: 装备清单",
+		Desc:       "第一次露营\n#露营[话题]# #户外[话题]#",
+		Type:       "normal",
+		Time:       1702195200000,
+		IPLocation: "浙江",
+		User:       xiaohongshu.User{UserID: "u1", Nickname: "小明"},
+		InteractInfo: xiaohongshu.InteractInfo{
+			LikedCount: "1.2万", CollectedCount: "80", CommentCount: "10+", SharedCount: "3",
+		},
+		TagList: []xiaohongshu.Tag{{Name: "露营"}, {Name: "户外"}},
+	}
+
+	out, err := Markdown(note, MarkdownOptions{
+		Assets: []Asset{
+			{Index: 1, Type: AssetImage, Link: "64f1.assets/01.webp"},
+			{Index: 1, Type: AssetLiveVideo, Link: "64f1.assets/01_live.mp4"},
+		},
+		Comments: []xiaohongshu.Comment{{
+			Content:    "求链接\n谢谢",
+			IPLocation: "上海",
+			LikeCount:  "5",
+			UserInfo:   xiaohongshu.User{Nickname: "路人"},
+			SubComments: []xiaohongshu.Comment{{
+				Content:   "主页有",
+				LikeCount: "0",
+				UserInfo:  xiaohongshu.User{Nickname: "小明"},
+			}},
+		}},
+		ExportedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	md := string(out)
+
+	// front matter 必须是合法 YAML，标题里的冒号不能破坏结构
+	parts := strings.SplitN(md, "---\n", 3)
+	require.Len(t, parts, 3)
+	var fm map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(parts[1]), &fm))
+	assert.Equal(t, "周末露营: 装备清单", fm["title"])
+	assert.Equal(t, "1.2万", fm["liked_count"])
+	assert.Equal(t, []any{"露营", "户外"}, fm["tags"])
+	assert.Equal(t, "https://www.xiaohongshu.com/explore/64f1", fm["source"])
+
+	assert.Contains(t, md, "#露营 #户外", "话题转成 Obsidian 标签")
+	assert.Contains(t, md, "![01](64f1.assets/01.webp)")
+	assert.Contains(t, md, "[实况 01](64f1.assets/01_live.mp4)")
+	assert.Contains(t, md, "> **路人** · 上海 · ")
+	assert.Contains(t, md, "· 赞 5\n> 求链接\n> 谢谢\n>\n> > **小明**\n> > 主页有")
+	assert.NotContains(t, md, "赞 0", "零赞不输出")
+}
+
+func TestMarkdown_WithoutComments(t *testing.T) {
+	out, err := Markdown(xiaohongshu.FeedDetail{NoteID: "n1"}, MarkdownOptions{})
+	require.NoError(t, err)
+
+	md := string(out)
+	assert.Contains(t, md, "# n1", "没有标题时用笔记 ID")
+	assert.NotContains(t, md, "## 评论")
+	assert.NotContains(t, md, "published:", "没有发布时间时不输出该字段")
+}
@@ -0,0 +1,203 @@
+// Package imageprep 在上传前预处理图片：统一转成 JPEG（顺带去掉 EXIF/GPS 等元数据）、
+// 按 EXIF 方向摆正、缩小超限的大图、按需补边或裁剪到统一比例。纯 Go 实现，不依赖 cgo。
+//
+// 小红书一篇笔记的所有图片按第一张的比例展示，比例不一致的会被平台裁掉，
+// 所以整组图片统一到同一个比例：调用方指定了就用指定的，否则用第一张的比例（限制在 3:4 到 4:3 之间）。
+package imageprep
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+
+	// 注册解码器
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+
+	"github.com/h2non/filetype"
+	"github.com/h2non/filetype/matchers"
+)
+
+// Aspect 目标比例（宽:高）
+type Aspect string
+
+const (
+	AspectAuto      Aspect = ""    // 按第一张图的比例
+	AspectPortrait  Aspect = "3:4" // 竖图，平台推荐
+	AspectSquare    Aspect = "1:1"
+	AspectLandscape Aspect = "4:3"
+)
+
+// Fit 比例不一致时的处理方式
+type Fit string
+
+const (
+	FitPad  Fit = "pad"  // 补白边，不丢内容（默认）
+	FitCrop Fit = "crop" // 居中裁剪
+)
+
+const (
+	defaultMaxSide = 4096
+	minMaxSide     = 512
+	maxMaxSide     = 8192
+	defaultQuality = 90
+
+	// maxPixels 单张图片的像素上限，解码成 RGBA 约占 4 字节/像素
+	maxPixels = 100_000_000
+
+	// 平台允许的比例范围：最竖 3:4，最横 4:3
+	minRatio = 3.0 / 4.0
+	maxRatio = 4.0 / 3.0
+)
+
+// Options 预处理参数，零值即默认：按第一张比例补边、长边不超过 4096、JPEG 质量 90
+type Options struct {
+	Aspect  Aspect `json:"aspect,omitempty" yaml:"aspect"`
+	Fit     Fit    `json:"fit,omitempty" yaml:"fit"`
+	MaxSide int    `json:"max_side,omitempty" yaml:"max_side"` // 长边像素上限
+	Quality int    `json:"quality,omitempty" yaml:"quality"`   // JPEG 质量 1-100
+}
+
+// Result 一张图的处理结果
+type Result struct {
+	Source string `json:"source"`
+	Path   string `json:"path"` // 处理后的文件；未处理时同 Source
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	Note   string `json:"note,omitempty"` // 未处理或有调整时的说明
+}
+
+// Validate 校验并补全默认值
+func (o *Options) Validate() error {
+	switch o.Aspect {
+	case AspectAuto, AspectPortrait, AspectSquare, AspectLandscape:
+	default:
+		return fmt.Errorf("不支持的比例 %q，可选: 3:4、1:1、4:3，不填按第一张图", o.Aspect)
+	}
+	switch o.Fit {
+	case "":
+		o.Fit = FitPad
+	case FitPad, FitCrop:
+	default:
+		return fmt.Errorf("不支持的 fit %q，可选: pad、crop", o.Fit)
+	}
+	if o.MaxSide == 0 {
+		o.MaxSide = defaultMaxSide
+	}
+	if o.MaxSide < minMaxSide || o.MaxSide > maxMaxSide {
+		return fmt.Errorf("max_side 需在 %d 到 %d 之间", minMaxSide, maxMaxSide)
+	}
+	if o.Quality == 0 {
+		o.Quality = defaultQuality
+	}
+	if o.Quality < 1 || o.Quality > 100 {
+		return fmt.Errorf("quality 需在 1 到 100 之间")
+	}
+	return nil
+}
+
+// ratio 比例的数值（宽/高），自动时返回 0
+func (a Aspect) ratio() float64 {
+	switch a {
+	case AspectPortrait:
+		return 3.0 / 4.0
+	case AspectSquare:
+		return 1
+	case AspectLandscape:
+		return 4.0 / 3.0
+	}
+	return 0
+}
+
+// Prepare 按顺序处理一组本地图片，结果写到 dir 下，返回与输入一一对应的结果
+func Prepare(paths []string, dir string, opts Options) ([]Result, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	target := opts.Aspect.ratio()
+	results := make([]Result, 0, len(paths))
+
+	for i, src := range paths {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return nil, fmt.Errorf("读取第%d张图片失败: %w", i+1, err)
+		}
+
+		// HEIC/HEIF 没有纯 Go 解码器。原样上传会带着 EXIF/GPS 出去，与开启预处理的本意相反，直接报错
+		if filetype.IsType(data, matchers.TypeHeif) {
+			return nil, fmt.Errorf("第%d张图片是 HEIC，无法在本地转换和去除元数据，请先转成 JPEG，或关闭预处理原样上传", i+1)
+		}
+
+		// 先只读尺寸，超大的图不解码，避免一张图吃掉几个 GB 内存
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("解码第%d张图片失败: %w", i+1, err)
+		}
+		if pixels := int64(cfg.Width) * int64(cfg.Height); pixels > maxPixels {
+			return nil, fmt.Errorf("第%d张图片 %dx%d 超过 %d 万像素上限", i+1, cfg.Width, cfg.Height, maxPixels/10000)
+		}
+
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("解码第%d张图片失败: %w", i+1, err)
+		}
+
+		orientation := jpegOrientation(data)
+		w, h := img.Bounds().Dx(), img.Bounds().Dy()
+		if orientation >= 5 {
+			w, h = h, w
+		}
+		if target == 0 {
+			// 第一张决定整组的比例
+			target = clamp(float64(w)/float64(h), minRatio, maxRatio)
+		}
+
+		// 解码后马上按最终尺寸缩小，摆正、铺白底、补边都在小图上做，内存里只有一份原尺寸的图
+		img = downscale(img, fitScale(w, h, target, opts.Fit, opts.MaxSide))
+		out, note := fitRatio(flatten(orient(img, orientation)), target, opts.Fit)
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, out, &jpeg.Options{Quality: opts.Quality}); err != nil {
+			return nil, fmt.Errorf("编码第%d张图片失败: %w", i+1, err)
+		}
+
+		dst := filepath.Join(dir, outputName(i, src, opts))
+		if err := os.WriteFile(dst, buf.Bytes(), 0644); err != nil {
+			return nil, fmt.Errorf("保存第%d张图片失败: %w", i+1, err)
+		}
+
+		b := out.Bounds()
+		results = append(results, Result{Source: src, Path: dst, Width: b.Dx(), Height: b.Dy(), Note: note})
+	}
+
+	return results, nil
+}
+
+// outputName 同一源文件同一参数得到同一个名字，重复处理直接覆盖
+func outputName(i int, src string, opts Options) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%+v", src, opts)))
+	return fmt.Sprintf("%02d_%s.jpg", i+1, hex.EncodeToString(sum[:6]))
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
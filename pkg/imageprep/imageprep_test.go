@@ -0,0 +1,172 @@
+package imageprep
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePNG(t *testing.T, dir, name string, w, h int) string {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, png.Encode(f, img))
+	return path
+}
+
+func decodeJPEG(t *testing.T, path string) image.Image {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	img, err := jpeg.Decode(f)
+	require.NoError(t, err)
+	return img
+}
+
+func TestPrepareFirstImageRatio(t *testing.T) {
+	dir := t.TempDir()
+	first := writePNG(t, dir, "a.png", 400, 300)  // 4:3
+	second := writePNG(t, dir, "b.png", 300, 400) // 3:4，要补成 4:3
+
+	results, err := Prepare([]string{first, second}, filepath.Join(dir, "out"), Options{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, [2]int{400, 300}, [2]int{results[0].Width, results[0].Height})
+	assert.Empty(t, results[0].Note)
+	assert.Equal(t, [2]int{533, 400}, [2]int{results[1].Width, results[1].Height})
+	assert.Contains(t, results[1].Note, "补边")
+
+	img := decodeJPEG(t, results[1].Path)
+	r, g, b, _ := img.At(2, 200).RGBA()
+	assert.True(t, r > 0xF000 && g > 0xF000 && b > 0xF000, "补的边和透明区域都是白色")
+}
+
+func TestPrepareAspectAndCrop(t *testing.T) {
+	dir := t.TempDir()
+	tall := writePNG(t, dir, "tall.png", 100, 400) // 1:4，超出平台范围
+
+	results, err := Prepare([]string{tall}, dir, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{300, 400}, [2]int{results[0].Width, results[0].Height}, "自动比例限制到 3:4")
+
+	results, err = Prepare([]string{tall}, dir, Options{Aspect: AspectSquare, Fit: FitCrop})
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{100, 100}, [2]int{results[0].Width, results[0].Height})
+	assert.Contains(t, results[0].Note, "裁剪")
+}
+
+func TestPrepareDownscale(t *testing.T) {
+	dir := t.TempDir()
+	big := writePNG(t, dir, "big.png", 1000, 750)
+
+	results, err := Prepare([]string{big}, dir, Options{MaxSide: 512})
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{512, 384}, [2]int{results[0].Width, results[0].Height})
+}
+
+func TestPrepareDownscaleBeforePad(t *testing.T) {
+	dir := t.TempDir()
+	big := writePNG(t, dir, "big.png", 1000, 750)
+
+	results, err := Prepare([]string{big}, dir, Options{Aspect: AspectPortrait, MaxSide: 512})
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{384, 512}, [2]int{results[0].Width, results[0].Height}, "补边后的长边也不超过上限")
+}
+
+func TestPrepareHEICRejected(t *testing.T) {
+	dir := t.TempDir()
+	heic := filepath.Join(dir, "a.heic")
+	header := append([]byte{0, 0, 0, 0x18}, []byte("ftypheic\x00\x00\x00\x00mif1heic")...)
+	require.NoError(t, os.WriteFile(heic, header, 0644))
+
+	_, err := Prepare([]string{heic}, dir, Options{})
+	require.Error(t, err, "HEIC 原样上传会带着元数据，开启预处理时应报错")
+	assert.Contains(t, err.Error(), "HEIC")
+}
+
+func TestPreparePixelLimit(t *testing.T) {
+	dir := t.TempDir()
+	// 只有文件头的 PNG，声明 20000x20000，DecodeConfig 就能发现超限，不用真的解码
+	chunk := []byte("IHDR")
+	chunk = binary.BigEndian.AppendUint32(chunk, 20000)
+	chunk = binary.BigEndian.AppendUint32(chunk, 20000)
+	chunk = append(chunk, 8, 6, 0, 0, 0)
+	var hdr bytes.Buffer
+	hdr.WriteString("\x89PNG\r\n\x1a\n")
+	binary.Write(&hdr, binary.BigEndian, uint32(13))
+	hdr.Write(chunk)
+	binary.Write(&hdr, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+	path := filepath.Join(dir, "huge.png")
+	require.NoError(t, os.WriteFile(path, hdr.Bytes(), 0644))
+
+	_, err := Prepare([]string{path}, dir, Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "像素上限")
+}
+
+func TestOptionsValidate(t *testing.T) {
+	o := Options{}
+	require.NoError(t, o.Validate())
+	assert.Equal(t, FitPad, o.Fit)
+	assert.Equal(t, defaultMaxSide, o.MaxSide)
+	assert.Equal(t, defaultQuality, o.Quality)
+
+	assert.Error(t, (&Options{Aspect: "16:9"}).Validate())
+	assert.Error(t, (&Options{Fit: "stretch"}).Validate())
+	assert.Error(t, (&Options{MaxSide: 100}).Validate())
+	assert.Error(t, (&Options{Quality: 101}).Validate())
+}
+
+// TestOrientation 方向 6 的 2x1 图应摆成 1x2，左边的像素转到上面。
+func TestOrientation(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	img.Set(1, 0, color.RGBA{B: 255, A: 255})
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	data := withOrientation(buf.Bytes(), 6)
+	assert.Equal(t, 6, jpegOrientation(data))
+	assert.Equal(t, 1, jpegOrientation(buf.Bytes()))
+
+	out := orient(img, 6)
+	assert.Equal(t, image.Rect(0, 0, 1, 2), out.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, out.At(0, 0))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, out.At(0, 1))
+}
+
+// withOrientation 在 SOI 之后插入只含方向标签的 EXIF 段（大端）
+func withOrientation(jpg []byte, o uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)      // 1 个条目
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112) // 方向
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)      // SHORT
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, o)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0) // 值补齐 + 下一个 IFD 偏移
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	seg := []byte{0xFF, 0xE1}
+	seg = binary.BigEndian.AppendUint16(seg, uint16(len(payload)+2))
+	seg = append(seg, payload...)
+
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, seg...)
+	return append(out, jpg[2:]...)
+}
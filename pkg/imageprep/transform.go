@@ -0,0 +1,123 @@
+package imageprep
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+// ratioTolerance 比例差在这个范围内视为一致，不为一两个像素补边
+const ratioTolerance = 0.01
+
+// flatten 画到白底 RGBA 上：透明区域变白，JPEG 没有透明通道
+func flatten(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
+
+// fittedSize 补边或裁剪到目标比例（宽/高）后的尺寸，比例已一致时原样返回
+func fittedSize(w, h int, target float64, fit Fit) (int, int) {
+	r := float64(w) / float64(h)
+	if math.Abs(r-target)/target <= ratioTolerance {
+		return w, h
+	}
+	if fit == FitCrop {
+		if r > target {
+			return int(math.Round(float64(h) * target)), h
+		}
+		return w, int(math.Round(float64(w) / target))
+	}
+	if r > target {
+		return w, int(math.Round(float64(w) / target))
+	}
+	return int(math.Round(float64(h) * target)), h
+}
+
+// fitRatio 补边或裁剪到目标比例（宽/高），返回调整说明
+func fitRatio(img *image.RGBA, target float64, fit Fit) (*image.RGBA, string) {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	fw, fh := fittedSize(w, h, target, fit)
+	if fw == w && fh == h {
+		return img, ""
+	}
+
+	if fit == FitCrop {
+		x0, y0 := (w-fw)/2, (h-fh)/2
+		dst := image.NewRGBA(image.Rect(0, 0, fw, fh))
+		draw.Draw(dst, dst.Bounds(), img, image.Pt(x0, y0), draw.Src)
+		return dst, fmt.Sprintf("裁剪 %dx%d → %dx%d", w, h, fw, fh)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, fw, fh))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect((fw-w)/2, (fh-h)/2, (fw-w)/2+w, (fh-h)/2+h), img, image.Point{}, draw.Src)
+	return dst, fmt.Sprintf("补边 %dx%d → %dx%d", w, h, fw, fh)
+}
+
+// fitScale 摆正后 w×h 的图要缩小多少，才能在补边或裁剪后长边不超过 maxSide，不需要缩小时返回 1
+func fitScale(w, h int, target float64, fit Fit, maxSide int) float64 {
+	fw, fh := fittedSize(w, h, target, fit)
+	long := max(fw, fh)
+	if long <= maxSide {
+		return 1
+	}
+	return float64(maxSide) / float64(long)
+}
+
+// downscale 按 scale 等比缩小，保留透明通道，铺白底留给 flatten
+func downscale(img image.Image, scale float64) image.Image {
+	if scale >= 1 {
+		return img
+	}
+	b := img.Bounds()
+	nw := max(1, int(math.Round(float64(b.Dx())*scale)))
+	nh := max(1, int(math.Round(float64(b.Dy())*scale)))
+	dst := image.NewRGBA(image.Rect(0, 0, nw, nh))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// orient 按 EXIF 方向（1-8）把图片摆正。重新编码会丢掉 EXIF，不摆正的话手机竖拍的照片会躺倒
+func orient(img image.Image, o int) image.Image {
+	if o < 2 || o > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if o >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		for dx := 0; dx < dw; dx++ {
+			var sx, sy int
+			switch o {
+			case 2: // 水平翻转
+				sx, sy = w-1-dx, dy
+			case 3: // 旋转 180°
+				sx, sy = w-1-dx, h-1-dy
+			case 4: // 垂直翻转
+				sx, sy = dx, h-1-dy
+			case 5: // 沿主对角线翻转
+				sx, sy = dy, dx
+			case 6: // 顺时针 90°
+				sx, sy = dy, h-1-dx
+			case 7: // 沿副对角线翻转
+				sx, sy = w-1-dy, h-1-dx
+			case 8: // 逆时针 90°
+				sx, sy = w-1-dy, dx
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+	return dst
+}
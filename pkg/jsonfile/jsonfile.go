@@ -0,0 +1,56 @@
+// Package jsonfile 以 JSON 文件做本地持久化：读时容忍文件不存在，写时先写临时文件再改名，
+// 进程中途退出不会留下写了一半的文件。
+package jsonfile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// Load 读取 path 解码到 v。文件不存在时返回 false、不报错，v 保持原样。
+func Load(path string, v any) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read %s", path)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, errors.Wrapf(err, "failed to decode %s", path)
+	}
+	return true, nil
+}
+
+// Save 把 v 编码后写入 path，目录不存在时自动创建
+func Save(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "failed to create dir for %s", path)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temp file")
+	}
+	defer os.Remove(tmp.Name())
+
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil {
+		return errors.Wrapf(writeErr, "failed to write %s", path)
+	}
+	if closeErr != nil {
+		return errors.Wrapf(closeErr, "failed to write %s", path)
+	}
+
+	return errors.Wrapf(os.Rename(tmp.Name(), path), "failed to save %s", path)
+}
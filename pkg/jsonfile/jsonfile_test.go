@@ -0,0 +1,46 @@
+package jsonfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "data.json")
+
+	var got map[string]int
+	found, err := Load(path, &got)
+	if err != nil || found {
+		t.Fatalf("文件不存在时应返回 false 且不报错, got %v %v", found, err)
+	}
+
+	if err := Save(path, map[string]int{"a": 1}); err != nil {
+		t.Fatalf("Save 失败: %v", err)
+	}
+
+	found, err = Load(path, &got)
+	if err != nil || !found {
+		t.Fatalf("Load 失败: %v %v", found, err)
+	}
+	if got["a"] != 1 {
+		t.Errorf("got %v", got)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("不应残留临时文件, got %d entries", len(entries))
+	}
+}
+
+func TestLoad_Corrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var v map[string]any
+	if _, err := Load(path, &v); err == nil {
+		t.Errorf("损坏的文件应报错")
+	}
+}
@@ -0,0 +1,335 @@
+// Package lint 在打开浏览器之前检查图文内容：标题和正文长度、话题、违禁词、外链、手机号、表情密度。
+//
+// 平台的很多拒绝要等图片传完、表单填完才出现，这里先把能在本地发现的问题挑出来。
+// 每条规则有默认级别，可在配置文件里改成 error、warning、info 或 off。
+package lint
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity 问题级别，error 会拦下发布
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	SeverityOff     Severity = "off"
+)
+
+// 规则名，也是配置文件里 severity 的键
+const (
+	RuleTitleLength   = "title_length"
+	RuleContentLength = "content_length"
+	RuleTagCount      = "tag_count"
+	RuleDuplicateTag  = "duplicate_tag"
+	RuleBannedWord    = "banned_word"
+	RuleSensitiveWord = "sensitive_word"
+	RuleExternalLink  = "external_link"
+	RulePhoneNumber   = "phone_number"
+	RuleEmojiDensity  = "emoji_density"
+)
+
+// defaultSeverity 各规则的默认级别：平台一定会拒绝或判定引流的是 error，其余只提醒
+var defaultSeverity = map[string]Severity{
+	RuleTitleLength:   SeverityError,
+	RuleContentLength: SeverityError,
+	RuleTagCount:      SeverityWarning,
+	RuleDuplicateTag:  SeverityWarning,
+	RuleBannedWord:    SeverityError,
+	RuleSensitiveWord: SeverityWarning,
+	RuleExternalLink:  SeverityWarning,
+	RulePhoneNumber:   SeverityError,
+	RuleEmojiDensity:  SeverityWarning,
+}
+
+// TitleMaxLength 标题上限，按 xhsutil.CalcTitleLength 计算
+const TitleMaxLength = 20
+
+// DefaultContentMaxLength 正文上限的默认值，从发布页读到过实际上限后以页面为准
+const DefaultContentMaxLength = 1000
+
+// Post 待检查的内容。Content 是发出后的文本，@{昵称} 要先展开成 @昵称
+type Post struct {
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// Config 检查配置，对应配置文件
+type Config struct {
+	BannedWords    []string            `yaml:"banned_words"`    // 命中即拦截
+	SensitiveWords []string            `yaml:"sensitive_words"` // 命中只提醒；配置里写了就替换内置列表，写 [] 清空
+	MaxTags        int                 `yaml:"max_tags"`
+	MaxEmojiRatio  float64             `yaml:"max_emoji_ratio"` // 表情占非空白字符的比例上限
+	Severity       map[string]Severity `yaml:"severity"`        // 覆盖规则默认级别
+
+	ContentMaxLength int `yaml:"-"` // 由调用方填入当前已知的正文上限
+}
+
+// DefaultConfig 内置配置：敏感词是常见的引流和广告法绝对化用语。
+// 中文没法分词，只收「加微信」这类几乎只在引流时出现的说法，「第一」「最好」在正常文字里太常见，不放进来
+func DefaultConfig() Config {
+	return Config{
+		SensitiveWords: []string{"加微信", "vx", "加v", "私信我", "国家级", "100%有效"},
+		MaxTags:        10,
+		MaxEmojiRatio:  0.15,
+
+		ContentMaxLength: DefaultContentMaxLength,
+	}
+}
+
+// LoadConfig 在内置配置上叠加 YAML 配置文件，文件不存在时用内置配置
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("读取检查配置失败: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("解析检查配置 %s 失败: %w", path, err)
+	}
+	for rule, sev := range cfg.Severity {
+		if _, ok := defaultSeverity[rule]; !ok {
+			return cfg, fmt.Errorf("检查配置里有未知规则 %q", rule)
+		}
+		switch sev {
+		case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		default:
+			return cfg, fmt.Errorf("规则 %s 的级别 %q 无效，可选 error、warning、info、off", rule, sev)
+		}
+	}
+	return cfg, nil
+}
+
+// Issue 一个问题
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Report 检查结果
+type Report struct {
+	Issues   []Issue `json:"issues"`
+	Errors   int     `json:"errors"`
+	Warnings int     `json:"warnings"`
+}
+
+// Err 有 error 级问题时返回汇总错误，否则返回 nil
+func (r *Report) Err() error {
+	if r.Errors == 0 {
+		return nil
+	}
+	var msgs []string
+	for _, i := range r.Issues {
+		if i.Severity == SeverityError {
+			msgs = append(msgs, i.Message)
+		}
+	}
+	return fmt.Errorf("发布前检查未通过: %s", strings.Join(msgs, "；"))
+}
+
+// rule 一条规则，返回问题描述，级别由配置决定
+type rule struct {
+	name  string
+	check func(p Post, cfg Config) []string
+}
+
+var rules = []rule{
+	{RuleTitleLength, checkTitleLength},
+	{RuleContentLength, checkContentLength},
+	{RuleTagCount, checkTagCount},
+	{RuleDuplicateTag, checkDuplicateTags},
+	{RuleBannedWord, func(p Post, cfg Config) []string { return checkWords(p, cfg.BannedWords, "违禁词") }},
+	{RuleSensitiveWord, func(p Post, cfg Config) []string { return checkWords(p, cfg.SensitiveWords, "敏感词") }},
+	{RuleExternalLink, checkExternalLinks},
+	{RulePhoneNumber, checkPhoneNumbers},
+	{RuleEmojiDensity, checkEmojiDensity},
+}
+
+// Check 按配置跑全部规则
+func Check(p Post, cfg Config) *Report {
+	report := &Report{Issues: []Issue{}}
+	for _, r := range rules {
+		sev := defaultSeverity[r.name]
+		if s, ok := cfg.Severity[r.name]; ok {
+			sev = s
+		}
+		if sev == SeverityOff {
+			continue
+		}
+		for _, msg := range r.check(p, cfg) {
+			report.Issues = append(report.Issues, Issue{Rule: r.name, Severity: sev, Message: msg})
+			switch sev {
+			case SeverityError:
+				report.Errors++
+			case SeverityWarning:
+				report.Warnings++
+			}
+		}
+	}
+	return report
+}
+
+func checkTitleLength(p Post, _ Config) []string {
+	if strings.TrimSpace(p.Title) == "" {
+		return []string{"标题不能为空"}
+	}
+	if n := xhsutil.CalcTitleLength(p.Title); n > TitleMaxLength {
+		return []string{fmt.Sprintf("标题长度 %d，超过上限 %d", n, TitleMaxLength)}
+	}
+	return nil
+}
+
+// checkContentLength 话题在编辑器里也占正文长度，按「 #话题」估算
+func checkContentLength(p Post, cfg Config) []string {
+	limit := cfg.ContentMaxLength
+	if limit <= 0 {
+		limit = DefaultContentMaxLength
+	}
+	n := utf8.RuneCountInString(strings.TrimSpace(p.Content))
+	for _, t := range normalizeTags(p.Tags) {
+		n += utf8.RuneCountInString(t) + 2
+	}
+	if n > limit {
+		return []string{fmt.Sprintf("正文加话题约 %d 字，超过上限 %d", n, limit)}
+	}
+	return nil
+}
+
+func checkTagCount(p Post, cfg Config) []string {
+	if cfg.MaxTags > 0 && len(p.Tags) > cfg.MaxTags {
+		return []string{fmt.Sprintf("话题 %d 个，超过 %d 个，多出的可能不会生效", len(p.Tags), cfg.MaxTags)}
+	}
+	return nil
+}
+
+func checkDuplicateTags(p Post, _ Config) []string {
+	seen := make(map[string]bool)
+	var msgs []string
+	for _, t := range normalizeTags(p.Tags) {
+		key := strings.ToLower(t)
+		if seen[key] {
+			msgs = append(msgs, fmt.Sprintf("话题 #%s 重复", t))
+		}
+		seen[key] = true
+	}
+	return msgs
+}
+
+// checkWords 标题、正文、话题里找词，不区分大小写，每个词只报一次
+func checkWords(p Post, words []string, kind string) []string {
+	text := strings.ToLower(p.Title + "\n" + p.Content + "\n" + strings.Join(p.Tags, " "))
+	var msgs []string
+	for _, w := range words {
+		w = strings.TrimSpace(w)
+		if w != "" && containsWord(text, strings.ToLower(w)) {
+			msgs = append(msgs, fmt.Sprintf("包含%s「%s」", kind, w))
+		}
+	}
+	return msgs
+}
+
+// containsWord text 里有 w。w 以字母或数字开头/结尾时，那一侧不能紧挨着字母或数字，
+// 避免 vx 命中 vxe、加v 命中 加vip 这类普通文字
+func containsWord(text, w string) bool {
+	for from := 0; ; {
+		i := strings.Index(text[from:], w)
+		if i < 0 {
+			return false
+		}
+		start, end := from+i, from+i+len(w)
+		if !(isASCIIAlnum(w[0]) && start > 0 && isASCIIAlnum(text[start-1])) &&
+			!(isASCIIAlnum(w[len(w)-1]) && end < len(text) && isASCIIAlnum(text[end])) {
+			return true
+		}
+		from = start + 1
+	}
+}
+
+func isASCIIAlnum(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)[^\s，。]+|\b[a-z0-9-]+\.(com|cn|net|org|io|cc|me|top|xyz)\b(/[^\s，。]*)?`)
+
+func checkExternalLinks(p Post, _ Config) []string {
+	var msgs []string
+	for _, m := range linkPattern.FindAllString(p.Title+"\n"+p.Content, -1) {
+		msgs = append(msgs, fmt.Sprintf("包含外部链接 %s，正文里的链接不可点击且可能被判定为引流", m))
+	}
+	return msgs
+}
+
+// phonePattern 手机号（可带 +86 和分隔符）和带区号的座机，前后不能紧挨数字
+var phonePattern = regexp.MustCompile(`(?:^|\D)((?:\+?86[-\s]?)?1[3-9]\d[-\s]?\d{4}[-\s]?\d{4}|0\d{2,3}-\d{7,8})(?:\D|$)`)
+
+func checkPhoneNumbers(p Post, _ Config) []string {
+	var msgs []string
+	for _, m := range phonePattern.FindAllStringSubmatch(p.Title+"\n"+p.Content, -1) {
+		msgs = append(msgs, fmt.Sprintf("包含电话号码 %s，会被判定为引流", m[1]))
+	}
+	return msgs
+}
+
+// xhsEmojiPattern 小红书自带表情的文本形式，如 [笑哭R]
+var xhsEmojiPattern = regexp.MustCompile(`\[[^\[\]\s]{1,6}R\]`)
+
+// minEmojiCount 表情少于这个数不检查密度，短文案几个表情很正常
+const minEmojiCount = 5
+
+func checkEmojiDensity(p Post, cfg Config) []string {
+	if cfg.MaxEmojiRatio <= 0 {
+		return nil
+	}
+	text := p.Title + p.Content
+	emojis := len(xhsEmojiPattern.FindAllString(text, -1))
+	text = xhsEmojiPattern.ReplaceAllString(text, "")
+
+	total := emojis
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r), r == 0xFE0F, r == 0x200D:
+			// 空白、变体选择符和连接符不计
+		case isEmoji(r):
+			emojis++
+			total++
+		default:
+			total++
+		}
+	}
+	if emojis < minEmojiCount || total == 0 {
+		return nil
+	}
+	if ratio := float64(emojis) / float64(total); ratio > cfg.MaxEmojiRatio {
+		return []string{fmt.Sprintf("表情 %d 个，占 %.0f%%，超过 %.0f%%，可能被判定为低质内容", emojis, ratio*100, cfg.MaxEmojiRatio*100)}
+	}
+	return nil
+}
+
+func isEmoji(r rune) bool {
+	return r >= 0x1F000 && r <= 0x1FAFF || r >= 0x2600 && r <= 0x27BF || r >= 0x2B00 && r <= 0x2BFF
+}
+
+func normalizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(t), "#")); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
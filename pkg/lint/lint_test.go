@@ -0,0 +1,108 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rulesOf(r *Report) []string {
+	var names []string
+	for _, i := range r.Issues {
+		names = append(names, i.Rule)
+	}
+	return names
+}
+
+func TestCheckClean(t *testing.T) {
+	r := Check(Post{Title: "周末去爬山", Content: "天气很好，山顶的风很舒服 ☀️", Tags: []string{"爬山", "周末"}}, DefaultConfig())
+	assert.Empty(t, r.Issues)
+	assert.NoError(t, r.Err())
+}
+
+func TestCheckRules(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BannedWords = []string{"代购"}
+	cfg.ContentMaxLength = 50
+
+	r := Check(Post{
+		Title:   "这是一个非常非常非常非常长的标题超过二十个字",
+		Content: "正品代购，加微信详聊，电话 138-1234-5678，官网 www.example.com " + strings.Repeat("好", 30),
+		Tags:    []string{"代购", "#代购", "好物"},
+	}, cfg)
+
+	assert.ElementsMatch(t, []string{
+		RuleTitleLength, RuleContentLength, RuleDuplicateTag, RuleBannedWord,
+		RuleSensitiveWord, RuleExternalLink, RulePhoneNumber,
+	}, rulesOf(r))
+	assert.Equal(t, 4, r.Errors)
+	require.Error(t, r.Err())
+	assert.Contains(t, r.Err().Error(), "违禁词「代购」")
+}
+
+func TestSensitiveWords(t *testing.T) {
+	cfg := DefaultConfig()
+	for text, want := range map[string]bool{
+		"第一次去这家店，最好吃的是烤鱼": false,
+		"顶级食材，淘宝也能买到":     false,
+		"vxe-table 组件的用法": false,
+		"加vip 才能看":        false,
+		"想要的加微信":          true,
+		"有问题 vx 联系":       true,
+		"VX：abc123":       true,
+		"私信我领资料":          true,
+	} {
+		got := len(checkWords(Post{Content: text}, cfg.SensitiveWords, "敏感词")) > 0
+		assert.Equal(t, want, got, text)
+	}
+}
+
+func TestPhoneNumber(t *testing.T) {
+	cfg := DefaultConfig()
+	for text, want := range map[string]bool{
+		"联系 13812345678":      true,
+		"+86 138 1234 5678":   true,
+		"座机 010-12345678":     true,
+		"订单号 2138123456789":   false,
+		"价格 199 元，已售 12000 件": false,
+	} {
+		got := len(checkPhoneNumbers(Post{Content: text}, cfg)) > 0
+		assert.Equal(t, want, got, text)
+	}
+}
+
+func TestEmojiDensity(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.Empty(t, checkEmojiDensity(Post{Content: "😀😀 好开心"}, cfg), "表情太少不检查")
+	assert.NotEmpty(t, checkEmojiDensity(Post{Content: "😀😀😀[笑哭R][笑哭R] 好"}, cfg))
+	assert.Empty(t, checkEmojiDensity(Post{Content: "😀😀😀😀😀" + strings.Repeat("今天的分享", 10)}, cfg))
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := LoadConfig(filepath.Join(dir, "missing.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig().SensitiveWords, cfg.SensitiveWords)
+
+	path := filepath.Join(dir, "lint.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("banned_words: [代购]\nsensitive_words: []\nseverity:\n  phone_number: warning\n  external_link: off\n"), 0644))
+	cfg, err = LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"代购"}, cfg.BannedWords)
+	assert.Empty(t, cfg.SensitiveWords, "写了就替换内置列表")
+	assert.Equal(t, 10, cfg.MaxTags, "没写的保留默认")
+
+	r := Check(Post{Title: "标题", Content: "电话 13812345678 www.example.com"}, cfg)
+	assert.Equal(t, []string{RulePhoneNumber}, rulesOf(r))
+	assert.Equal(t, SeverityWarning, r.Issues[0].Severity)
+	assert.NoError(t, r.Err())
+
+	require.NoError(t, os.WriteFile(path, []byte("severity:\n  phone: off\n"), 0644))
+	_, err = LoadConfig(path)
+	assert.Error(t, err, "未知规则")
+}
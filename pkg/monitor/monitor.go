@@ -0,0 +1,354 @@
+// Package monitor 维护保存的搜索（关键词 + 筛选项）和命中的新笔记提醒，数据存成本地 JSON 文件。
+//
+// searches.json 存搜索本身，seen/<id>.json 存每个搜索已见过的笔记，inbox.json 存提醒。
+// 第一次运行只记下当前结果作为基线，之后每次只把没见过的笔记当作新命中。
+package monitor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/jsonfile"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+const (
+	// maxSeen 单个搜索最多记住的笔记数，超出丢最早的。搜索一页只有几十条，足够覆盖翻出来的旧笔记
+	maxSeen = 2000
+	// maxAlerts 收件箱最多保留的提醒数，超出丢最早的
+	maxAlerts = 2000
+)
+
+// Search 保存的搜索
+type Search struct {
+	ID        string                   `json:"id"` // 由关键词和筛选项算出，同样的搜索只存一份
+	Keyword   string                   `json:"keyword"`
+	Filters   xiaohongshu.FilterOption `json:"filters"`
+	Webhook   string                   `json:"webhook,omitempty"` // 有新命中时 POST 过去，不填只进收件箱
+	CreatedAt time.Time                `json:"created_at"`
+	LastRunAt *time.Time               `json:"last_run_at,omitempty"`
+	LastError string                   `json:"last_error,omitempty"` // 最近一次搜索或推送失败的原因，成功后清空
+}
+
+// Match 一次搜索结果里的一条笔记
+type Match struct {
+	FeedID    string `json:"feed_id"`
+	XsecToken string `json:"xsec_token"`
+	Title     string `json:"title"`
+	Author    string `json:"author"`
+}
+
+// Alert 新命中的提醒
+type Alert struct {
+	ID       string    `json:"id"`
+	SearchID string    `json:"search_id"`
+	Keyword  string    `json:"keyword"`
+	Match              // 命中的笔记
+	FoundAt  time.Time `json:"found_at"`
+	Read     bool      `json:"read"`
+}
+
+// AlertQuery 查询收件箱的条件，零值返回全部
+type AlertQuery struct {
+	SearchID   string
+	UnreadOnly bool
+	Limit      int // 只取最新的 Limit 条，0 为不限
+}
+
+type searchesFile struct {
+	Searches []Search `json:"searches"`
+}
+
+type inboxFile struct {
+	Alerts []Alert `json:"alerts"`
+}
+
+// Store 保存的搜索与收件箱的存储，并发安全
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewStore 创建存储，目录在第一次写入时创建
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// SearchID 关键词和筛选项决定的搜索 ID
+func SearchID(keyword string, filters xiaohongshu.FilterOption) string {
+	data, _ := json.Marshal(filters)
+	sum := sha256.Sum256([]byte(strings.TrimSpace(keyword) + "|" + string(data)))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Save 保存搜索，已存在时只更新 webhook。返回保存后的搜索和是否为新建
+func (s *Store) Save(search Search) (Search, bool, error) {
+	search.Keyword = strings.TrimSpace(search.Keyword)
+	if search.Keyword == "" {
+		return Search{}, false, fmt.Errorf("关键词不能为空")
+	}
+	search.ID = SearchID(search.Keyword, search.Filters)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.loadSearches()
+	if err != nil {
+		return Search{}, false, err
+	}
+
+	for i := range list.Searches {
+		if list.Searches[i].ID == search.ID {
+			list.Searches[i].Webhook = search.Webhook
+			return list.Searches[i], false, s.saveSearches(list)
+		}
+	}
+
+	if search.CreatedAt.IsZero() {
+		search.CreatedAt = time.Now()
+	}
+	search.LastRunAt = nil
+	search.LastError = ""
+	list.Searches = append(list.Searches, search)
+	return search, true, s.saveSearches(list)
+}
+
+// Delete 删除搜索和它见过的笔记，收件箱里已有的提醒保留。返回是否存在
+func (s *Store) Delete(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.loadSearches()
+	if err != nil {
+		return false, err
+	}
+
+	for i, search := range list.Searches {
+		if search.ID != id {
+			continue
+		}
+		list.Searches = append(list.Searches[:i], list.Searches[i+1:]...)
+		if err := s.saveSearches(list); err != nil {
+			return false, err
+		}
+		if err := os.Remove(s.seenPath(id)); err != nil && !os.IsNotExist(err) {
+			return true, fmt.Errorf("删除已见记录失败: %w", err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// List 返回全部保存的搜索，按创建时间排序
+func (s *Store) List() ([]Search, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.loadSearches()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(list.Searches, func(i, j int) bool {
+		return list.Searches[i].CreatedAt.Before(list.Searches[j].CreatedAt)
+	})
+	return list.Searches, nil
+}
+
+// Record 记录一次搜索结果，返回新命中并写入收件箱。
+//
+// 搜索第一次运行时只把结果记为基线，不产生提醒——否则一保存就是几十条「新」笔记。
+// runErr 非空时只记下失败原因。
+func (s *Store) Record(id string, matches []Match, runErr error, at time.Time) ([]Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.loadSearches()
+	if err != nil {
+		return nil, err
+	}
+	idx := -1
+	for i := range list.Searches {
+		if list.Searches[i].ID == id {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		// 运行期间被删掉了
+		return nil, nil
+	}
+	search := &list.Searches[idx]
+
+	if runErr != nil {
+		search.LastError = runErr.Error()
+		return nil, s.saveSearches(list)
+	}
+
+	var seen []string
+	if _, err := jsonfile.Load(s.seenPath(id), &seen); err != nil {
+		return nil, err
+	}
+	fresh := Diff(seen, matches)
+
+	var alerts []Alert
+	if search.LastRunAt != nil {
+		for _, m := range fresh {
+			alerts = append(alerts, Alert{
+				ID:       id + ":" + m.FeedID,
+				SearchID: id,
+				Keyword:  search.Keyword,
+				Match:    m,
+				FoundAt:  at,
+			})
+		}
+	}
+
+	for _, m := range fresh {
+		seen = append(seen, m.FeedID)
+	}
+	if len(seen) > maxSeen {
+		seen = seen[len(seen)-maxSeen:]
+	}
+	if err := jsonfile.Save(s.seenPath(id), seen); err != nil {
+		return nil, err
+	}
+
+	if len(alerts) > 0 {
+		var inbox inboxFile
+		if _, err := jsonfile.Load(s.inboxPath(), &inbox); err != nil {
+			return nil, err
+		}
+		inbox.Alerts = append(inbox.Alerts, alerts...)
+		if len(inbox.Alerts) > maxAlerts {
+			inbox.Alerts = inbox.Alerts[len(inbox.Alerts)-maxAlerts:]
+		}
+		if err := jsonfile.Save(s.inboxPath(), inbox); err != nil {
+			return nil, err
+		}
+	}
+
+	search.LastRunAt = &at
+	search.LastError = ""
+	return alerts, s.saveSearches(list)
+}
+
+// SetError 记下搜索之外的失败（如 webhook 推送失败），不影响已见记录
+func (s *Store) SetError(id string, cause error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.loadSearches()
+	if err != nil {
+		return err
+	}
+	for i := range list.Searches {
+		if list.Searches[i].ID == id {
+			list.Searches[i].LastError = cause.Error()
+			return s.saveSearches(list)
+		}
+	}
+	return nil
+}
+
+// Alerts 查询收件箱，最新的在前
+func (s *Store) Alerts(q AlertQuery) ([]Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var inbox inboxFile
+	if _, err := jsonfile.Load(s.inboxPath(), &inbox); err != nil {
+		return nil, err
+	}
+
+	var result []Alert
+	for i := len(inbox.Alerts) - 1; i >= 0; i-- {
+		a := inbox.Alerts[i]
+		if q.SearchID != "" && a.SearchID != q.SearchID {
+			continue
+		}
+		if q.UnreadOnly && a.Read {
+			continue
+		}
+		result = append(result, a)
+		if q.Limit > 0 && len(result) == q.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// MarkRead 把提醒标为已读，返回实际改动的条数
+func (s *Store) MarkRead(ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var inbox inboxFile
+	if _, err := jsonfile.Load(s.inboxPath(), &inbox); err != nil {
+		return 0, err
+	}
+
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	changed := 0
+	for i := range inbox.Alerts {
+		if want[inbox.Alerts[i].ID] && !inbox.Alerts[i].Read {
+			inbox.Alerts[i].Read = true
+			changed++
+		}
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+	return changed, jsonfile.Save(s.inboxPath(), inbox)
+}
+
+// Diff 返回 matches 中不在 seen 里的笔记，保持原顺序并去重
+func Diff(seen []string, matches []Match) []Match {
+	known := make(map[string]bool, len(seen)+len(matches))
+	for _, id := range seen {
+		known[id] = true
+	}
+
+	var fresh []Match
+	for _, m := range matches {
+		if m.FeedID == "" || known[m.FeedID] {
+			continue
+		}
+		known[m.FeedID] = true
+		fresh = append(fresh, m)
+	}
+	return fresh
+}
+
+func (s *Store) loadSearches() (*searchesFile, error) {
+	var list searchesFile
+	if _, err := jsonfile.Load(filepath.Join(s.dir, "searches.json"), &list); err != nil {
+		return nil, fmt.Errorf("读取保存的搜索失败: %w", err)
+	}
+	return &list, nil
+}
+
+func (s *Store) saveSearches(list *searchesFile) error {
+	return jsonfile.Save(filepath.Join(s.dir, "searches.json"), list)
+}
+
+func (s *Store) seenPath(id string) string {
+	return filepath.Join(s.dir, "seen", filepath.Base(id)+".json")
+}
+
+func (s *Store) inboxPath() string {
+	return filepath.Join(s.dir, "inbox.json")
+}
@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+func TestStoreRecord(t *testing.T) {
+	s := NewStore(t.TempDir())
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	search, created, err := s.Save(Search{Keyword: " 防晒 ", Filters: xiaohongshu.FilterOption{SortBy: "最新"}})
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "防晒", search.Keyword)
+
+	_, created, err = s.Save(Search{Keyword: "防晒", Filters: xiaohongshu.FilterOption{SortBy: "最新"}, Webhook: "https://example.com/hook"})
+	require.NoError(t, err)
+	assert.False(t, created, "同样的关键词和筛选只更新 webhook")
+
+	// 第一次只记基线
+	alerts, err := s.Record(search.ID, []Match{{FeedID: "a"}, {FeedID: "b"}}, nil, t0)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	alerts, err = s.Record(search.ID, []Match{{FeedID: "c", Title: "新品"}, {FeedID: "a"}, {FeedID: "c"}}, nil, t0.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "c", alerts[0].FeedID)
+	assert.Equal(t, "防晒", alerts[0].Keyword)
+
+	_, err = s.Record(search.ID, nil, errors.New("未登录"), t0.Add(2*time.Hour))
+	require.NoError(t, err)
+	searches, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, searches, 1)
+	assert.Equal(t, "未登录", searches[0].LastError)
+	assert.Equal(t, "https://example.com/hook", searches[0].Webhook)
+	assert.Equal(t, t0.Add(time.Hour), *searches[0].LastRunAt)
+
+	unread, err := s.Alerts(AlertQuery{UnreadOnly: true})
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+
+	n, err := s.MarkRead([]string{unread[0].ID, "nope"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	unread, _ = s.Alerts(AlertQuery{UnreadOnly: true})
+	assert.Empty(t, unread)
+
+	deleted, err := s.Delete(search.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	all, _ := s.Alerts(AlertQuery{})
+	assert.Len(t, all, 1, "删除搜索不删提醒")
+
+	// 重新保存后重新建立基线
+	_, _, err = s.Save(Search{Keyword: "防晒", Filters: xiaohongshu.FilterOption{SortBy: "最新"}})
+	require.NoError(t, err)
+	alerts, _ = s.Record(search.ID, []Match{{FeedID: "d"}}, nil, t0.Add(3*time.Hour))
+	assert.Empty(t, alerts)
+}
+
+func TestSearchIDDependsOnFilters(t *testing.T) {
+	assert.Equal(t, SearchID("防晒", xiaohongshu.FilterOption{}), SearchID(" 防晒", xiaohongshu.FilterOption{}))
+	assert.NotEqual(t, SearchID("防晒", xiaohongshu.FilterOption{}), SearchID("防晒", xiaohongshu.FilterOption{PublishTime: "一天内"}))
+}
+
+func TestPostWebhook(t *testing.T) {
+	var got WebhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		if got.Keyword == "fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	payload := WebhookPayload{SearchID: "x", Keyword: "防晒", Alerts: []Alert{{ID: "x:a", Match: Match{FeedID: "a"}}}}
+	require.NoError(t, PostWebhook(context.Background(), srv.URL, payload))
+	assert.Equal(t, "a", got.Alerts[0].FeedID)
+
+	payload.Keyword = "fail"
+	assert.Error(t, PostWebhook(context.Background(), srv.URL, payload))
+
+	assert.NoError(t, ValidateWebhook(""))
+	assert.Error(t, ValidateWebhook("ftp://example.com"))
+	assert.Error(t, ValidateWebhook("example.com/hook"))
+}
@@ -0,0 +1,59 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// WebhookPayload 推送给 webhook 的内容
+type WebhookPayload struct {
+	SearchID string  `json:"search_id"`
+	Keyword  string  `json:"keyword"`
+	Alerts   []Alert `json:"alerts"`
+}
+
+// ValidateWebhook 检查 webhook 地址，空串表示不推送
+func ValidateWebhook(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook 需为 http(s) 地址: %q", raw)
+	}
+	return nil
+}
+
+// PostWebhook 以 JSON POST 推送新命中，非 2xx 视为失败
+func PostWebhook(ctx context.Context, rawURL string, payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("推送 webhook 失败: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("推送 webhook 失败: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// Package postfile 解析带 YAML front matter 的 Markdown 稿件：front matter 放发布参数，正文即笔记内容。
+//
+//	---
+//	title: 周末去哪儿
+//	tags: [旅行, 周末]
+//	visibility: 仅自己可见
+//	schedule_at: 2024-01-20T10:30:00+08:00
+//	is_original: true
+//	products: [防晒霜]
+//	images: [cover.jpg]
+//	preprocess: {aspect: "3:4"}
+//	---
+//	正文……
+//
+//	![](photos/2.jpg)
+//
+// 图片来自 front matter 的 images 和正文里的 ![](...) 引用，按出现顺序合并去重；
+// 相对路径按稿件所在目录解析。正文里的图片引用会从内容中去掉，小红书不渲染 Markdown。
+package postfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/imageprep"
+	"github.com/xpzouying/xiaohongshu-mcp/publishing"
+	"gopkg.in/yaml.v3"
+)
+
+// Post 从稿件解析出的发布参数
+type Post struct {
+	Title      string   `json:"title" yaml:"title"`
+	Content    string   `json:"content" yaml:"-"`
+	Images     []string `json:"images" yaml:"images"`
+	Tags       []string `json:"tags,omitempty" yaml:"tags"`
+	Visibility string   `json:"visibility,omitempty" yaml:"visibility"`
+	ScheduleAt string   `json:"schedule_at,omitempty" yaml:"schedule_at"` // ISO8601，为空则立即发布
+	IsOriginal bool     `json:"is_original,omitempty" yaml:"is_original"`
+	Products   []string `json:"products,omitempty" yaml:"products"`
+	Location   string   `json:"location,omitempty" yaml:"location"`
+
+	Collection       string `json:"collection,omitempty" yaml:"collection"`
+	CreateCollection bool   `json:"create_collection,omitempty" yaml:"create_collection"`
+
+	Preprocess *imageprep.Options `json:"preprocess,omitempty" yaml:"preprocess"` // 上传前的图片预处理，不写则原样上传
+}
+
+var (
+	// imageRef 匹配 ![alt](path)、![alt](<path with space>)、![alt](path "title")
+	imageRef   = regexp.MustCompile(`!\[[^\]]*\]\(\s*(?:<([^>]+)>|([^)\s]+))(?:\s+"[^"]*")?\s*\)`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// Load 读取并解析稿件，相对图片路径按稿件所在目录解析
+func Load(path string) (*Post, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取稿件失败: %w", err)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, filepath.Dir(abs))
+}
+
+// Parse 解析稿件内容，baseDir 用于解析相对图片路径
+func Parse(data []byte, baseDir string) (*Post, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimPrefix(text, "\ufeff")
+
+	if !strings.HasPrefix(text, "---\n") {
+		return nil, fmt.Errorf("稿件缺少 front matter，需以 --- 开头")
+	}
+	// 前面补一个换行，空 front matter（紧跟着就是 ---）也能找到结束行
+	rest := "\n" + text[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return nil, fmt.Errorf("front matter 没有结束的 ---")
+	}
+	header, body := rest[:end], rest[end+len("\n---"):]
+	if i := strings.IndexByte(body, '\n'); i >= 0 {
+		body = body[i+1:]
+	} else {
+		body = ""
+	}
+
+	var post Post
+	dec := yaml.NewDecoder(bytes.NewReader([]byte(header)))
+	// 写错字段名（如 tag:）直接报错，不要悄悄发出一篇没有标签的笔记
+	dec.KnownFields(true)
+	if err := dec.Decode(&post); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("解析 front matter 失败: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var images []string
+	add := func(ref string) {
+		p := resolve(baseDir, strings.TrimSpace(ref))
+		if p != "" && !seen[p] {
+			seen[p] = true
+			images = append(images, p)
+		}
+	}
+	for _, ref := range post.Images {
+		add(ref)
+	}
+	for _, m := range imageRef.FindAllStringSubmatch(body, -1) {
+		add(m[1] + m[2])
+	}
+	post.Images = images
+
+	content := imageRef.ReplaceAllString(body, "")
+	content = blankLines.ReplaceAllString(content, "\n\n")
+	post.Content = strings.TrimSpace(content)
+
+	return &post, nil
+}
+
+// Validate 在打开浏览器之前检查稿件。标题长度、定时时间和合集参数直接用发布接口的检查，
+// 这里只补上稿件特有的：缺字段、本地图片不存在
+func (p *Post) Validate(now time.Time) error {
+	if strings.TrimSpace(p.Title) == "" {
+		return fmt.Errorf("缺少标题（front matter 的 title）")
+	}
+	if err := publishing.CheckTitle(p.Title); err != nil {
+		return err
+	}
+	if p.Content == "" {
+		return fmt.Errorf("正文为空")
+	}
+	if len(p.Images) == 0 {
+		return fmt.Errorf("至少需要一张图片（front matter 的 images 或正文里的 ![](...)）")
+	}
+	for _, img := range p.Images {
+		if isURL(img) {
+			continue
+		}
+		if _, err := os.Stat(img); err != nil {
+			return fmt.Errorf("图片不存在: %s", img)
+		}
+	}
+
+	if _, err := publishing.ParseScheduleAt(p.ScheduleAt, now); err != nil {
+		return err
+	}
+	return publishing.CheckCollection(p.Collection, p.CreateCollection)
+}
+
+// resolve 网络图片原样保留，相对路径拼上 baseDir
+func resolve(baseDir, ref string) string {
+	if ref == "" || isURL(ref) || filepath.IsAbs(ref) {
+		return ref
+	}
+	return filepath.Join(baseDir, filepath.FromSlash(ref))
+}
+
+func isURL(s string) bool {
+	lower := strings.ToLower(s)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
@@ -0,0 +1,85 @@
+package postfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/imageprep"
+)
+
+func TestParse(t *testing.T) {
+	src := "---\r\n" +
+		"title: 周末去哪儿\r\n" +
+		"tags: [旅行, 周末]\r\n" +
+		"visibility: 仅自己可见\r\n" +
+		"schedule_at: 2024-01-20T10:30:00+08:00\r\n" +
+		"is_original: true\r\n" +
+		"products: [防晒霜]\r\n" +
+		"images: [cover.jpg, https://example.com/a.jpg]\r\n" +
+		"preprocess: {aspect: \"3:4\", max_side: 2048}\r\n" +
+		"---\r\n" +
+		"第一段\r\n\r\n" +
+		"![](photos/2.jpg)\r\n\r\n\r\n" +
+		"第二段 ![封面](<cover.jpg> \"重复\")\r\n"
+
+	post, err := Parse([]byte(src), "/posts")
+	require.NoError(t, err)
+
+	assert.Equal(t, "周末去哪儿", post.Title)
+	assert.Equal(t, []string{"旅行", "周末"}, post.Tags)
+	assert.Equal(t, "仅自己可见", post.Visibility)
+	assert.Equal(t, "2024-01-20T10:30:00+08:00", post.ScheduleAt, "未加引号的时间也按原文保留")
+	assert.True(t, post.IsOriginal)
+	assert.Equal(t, []string{"防晒霜"}, post.Products)
+	require.NotNil(t, post.Preprocess)
+	assert.Equal(t, imageprep.Options{Aspect: imageprep.AspectPortrait, MaxSide: 2048}, *post.Preprocess)
+	assert.Equal(t, []string{
+		filepath.Join("/posts", "cover.jpg"),
+		"https://example.com/a.jpg",
+		filepath.Join("/posts", "photos", "2.jpg"),
+	}, post.Images, "front matter 在前，正文引用在后，重复的只留一次")
+	assert.Equal(t, "第一段\n\n第二段", post.Content)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse([]byte("正文"), "/")
+	assert.Error(t, err, "缺少 front matter")
+
+	_, err = Parse([]byte("---\ntitle: x\n正文"), "/")
+	assert.Error(t, err, "front matter 未结束")
+
+	_, err = Parse([]byte("---\ntag: [x]\n---\n正文"), "/")
+	assert.Error(t, err, "未知字段")
+
+	post, err := Parse([]byte("---\n---\n正文"), "/")
+	require.NoError(t, err, "空 front matter")
+	assert.Equal(t, "正文", post.Content)
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	img := filepath.Join(dir, "1.jpg")
+	require.NoError(t, os.WriteFile(img, []byte("x"), 0644))
+	now := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+
+	ok := Post{Title: "标题", Content: "正文", Images: []string{img}}
+	assert.NoError(t, ok.Validate(now))
+
+	cases := map[string]Post{
+		"缺标题":     {Content: "正文", Images: []string{img}},
+		"标题过长":    {Title: "一二三四五六七八九十一二三四五六七八九十一", Content: "正文", Images: []string{img}},
+		"缺图片":     {Title: "标题", Content: "正文"},
+		"图片不存在":   {Title: "标题", Content: "正文", Images: []string{filepath.Join(dir, "2.jpg")}},
+		"定时格式错误":  {Title: "标题", Content: "正文", Images: []string{img}, ScheduleAt: "明天"},
+		"定时太早":    {Title: "标题", Content: "正文", Images: []string{img}, ScheduleAt: "2024-01-20T00:30:00Z"},
+		"定时太晚":    {Title: "标题", Content: "正文", Images: []string{img}, ScheduleAt: "2024-02-10T00:00:00Z"},
+		"新建合集缺名称": {Title: "标题", Content: "正文", Images: []string{img}, CreateCollection: true},
+	}
+	for name, p := range cases {
+		assert.Error(t, p.Validate(now), name)
+	}
+}
@@ -0,0 +1,389 @@
+// Package schedule 在本地保存定时发布任务：任务存 schedule.json，图片复制到 <id>/ 目录。
+//
+// 平台自带的定时发布只支持 1 小时到 14 天，更远的时间由服务自己到点发布，
+// 所以图片在安排时就复制进来，不依赖原路径或临时目录在几个月后还在。
+package schedule
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/jsonfile"
+)
+
+// Status 任务状态
+type Status string
+
+const (
+	StatusPending  Status = "pending"  // 等待发布，包括失败后等待重试
+	StatusRunning  Status = "running"  // 正在发布
+	StatusDone     Status = "done"     // 已发布
+	StatusFailed   Status = "failed"   // 重试用完或发布中途服务退出，需要人工处理
+	StatusCanceled Status = "canceled" // 已取消
+)
+
+// MaxAttempts 一个任务最多尝试发布的次数
+const MaxAttempts = 4
+
+// Post 要发布的图文，字段与发布请求一致
+type Post struct {
+	Title            string   `json:"title"`
+	Content          string   `json:"content"`
+	Images           []string `json:"images"` // 任务目录下的本地路径
+	Tags             []string `json:"tags,omitempty"`
+	IsOriginal       bool     `json:"is_original,omitempty"`
+	Visibility       string   `json:"visibility,omitempty"`
+	Products         []string `json:"products,omitempty"`
+	Location         string   `json:"location,omitempty"`
+	Collection       string   `json:"collection,omitempty"`
+	CreateCollection bool     `json:"create_collection,omitempty"`
+}
+
+// Job 一个定时发布任务
+type Job struct {
+	ID          string    `json:"id"`
+	Post        Post      `json:"post"`
+	PublishAt   time.Time `json:"publish_at"`
+	Status      Status    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"` // 下一次尝试的时间，首次等于 PublishAt
+	LastError   string    `json:"last_error,omitempty"`
+	// Unconfirmed 上次点了发布却没确认成功，重试前要先核对笔记列表。
+	// KnownNotes 是那次发布前列表里已有的笔记 ID，为空就是列表本来为空；
+	// NotesUnknown 表示发布前没读到列表，无法核对
+	Unconfirmed  bool      `json:"unconfirmed,omitempty"`
+	KnownNotes   []string  `json:"known_notes,omitempty"`
+	NotesUnknown bool      `json:"notes_unknown,omitempty"`
+	NoteID       string    `json:"note_id,omitempty"`
+	URL          string    `json:"url,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Backoff 第 attempts 次失败后等多久重试：2 分钟起每次乘 3，最多 1 小时
+func Backoff(attempts int) time.Duration {
+	d := 2 * time.Minute
+	for i := 1; i < attempts; i++ {
+		d *= 3
+		if d >= time.Hour {
+			return time.Hour
+		}
+	}
+	return d
+}
+
+type jobsFile struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// Store 任务存储，并发安全
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewStore 创建存储，目录在第一次写入时创建
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// NewID 生成任务 ID
+func NewID() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// AssetDir 任务图片目录
+func (s *Store) AssetDir(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id))
+}
+
+// Add 新增待发布任务
+func (s *Store) Add(job Job) (Job, error) {
+	if job.ID == "" {
+		return Job{}, fmt.Errorf("任务 ID 不能为空")
+	}
+	now := time.Now()
+	job.Status = StatusPending
+	job.NextAttempt = job.PublishAt
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return Job{}, err
+	}
+	list.Jobs = append(list.Jobs, job)
+	return job, s.save(list)
+}
+
+// Get 取一个任务，不存在时返回 false
+func (s *Store) Get(id string) (Job, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return Job{}, false, err
+	}
+	if i := indexOf(list.Jobs, id); i >= 0 {
+		return list.Jobs[i], true, nil
+	}
+	return Job{}, false, nil
+}
+
+// List 返回全部任务，按发布时间先后排列
+func (s *Store) List() ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(list.Jobs, func(i, j int) bool {
+		return list.Jobs[i].PublishAt.Before(list.Jobs[j].PublishAt)
+	})
+	return list.Jobs, nil
+}
+
+// Claim 取出最早到期的待发布任务并标为发布中，没有到期任务时返回 false。
+// 取出和改状态在同一把锁里，同一个任务不会被发两次
+func (s *Store) Claim(now time.Time) (Job, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return Job{}, false, err
+	}
+	due := -1
+	for i, j := range list.Jobs {
+		if j.Status != StatusPending || j.NextAttempt.After(now) {
+			continue
+		}
+		if due < 0 || j.NextAttempt.Before(list.Jobs[due].NextAttempt) {
+			due = i
+		}
+	}
+	if due < 0 {
+		return Job{}, false, nil
+	}
+
+	job := &list.Jobs[due]
+	job.Status = StatusRunning
+	job.Attempts++
+	job.UpdatedAt = now
+	if err := s.save(list); err != nil {
+		return Job{}, false, err
+	}
+	return *job, true, nil
+}
+
+// Finish 标记发布成功并删除任务图片，记录保留供查询
+func (s *Store) Finish(id, noteID, url string) error {
+	err := s.update(id, func(j *Job) error {
+		j.Status = StatusDone
+		j.NoteID = noteID
+		j.URL = url
+		j.LastError = ""
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return s.removeAssets(id)
+}
+
+// Fail 记录一次失败。还有次数时按 Backoff 安排重试，否则标为失败，图片保留以便重新安排。
+// 走到这里说明这次没有点过发布，之前未确认的发布也已核对过没发出去
+func (s *Store) Fail(id string, cause error, now time.Time) (Job, error) {
+	var out Job
+	err := s.update(id, func(j *Job) error {
+		clearUnconfirmed(j)
+		retryLater(j, cause, now)
+		out = *j
+		return nil
+	})
+	return out, err
+}
+
+// FailUnconfirmed 点了发布但没确认成功，记下发布前的笔记列表 known（可以为空），下次先核对再决定是否重发
+func (s *Store) FailUnconfirmed(id string, cause error, known []string, now time.Time) (Job, error) {
+	var out Job
+	err := s.update(id, func(j *Job) error {
+		j.Unconfirmed = true
+		j.KnownNotes = known
+		j.NotesUnknown = false
+		retryLater(j, cause, now)
+		out = *j
+		return nil
+	})
+	return out, err
+}
+
+// FailUnverifiable 点了发布但没确认成功，发布前又没读到笔记列表，没法核对，直接标为失败等人确认
+func (s *Store) FailUnverifiable(id string, cause error) (Job, error) {
+	var out Job
+	err := s.update(id, func(j *Job) error {
+		j.Unconfirmed = true
+		j.KnownNotes = nil
+		j.NotesUnknown = true
+		j.Status = StatusFailed
+		j.LastError = cause.Error() + "；发布前没读到笔记列表，无法确认是否已发出，请检查账号后重新安排或取消"
+		out = *j
+		return nil
+	})
+	return out, err
+}
+
+// FailPermanent 内容或参数有问题，重试也不会成功，直接标为失败
+func (s *Store) FailPermanent(id string, cause error) (Job, error) {
+	var out Job
+	err := s.update(id, func(j *Job) error {
+		clearUnconfirmed(j)
+		j.Status = StatusFailed
+		j.LastError = cause.Error()
+		out = *j
+		return nil
+	})
+	return out, err
+}
+
+// clearUnconfirmed 不再需要核对上次的发布
+func clearUnconfirmed(j *Job) {
+	j.Unconfirmed = false
+	j.KnownNotes = nil
+	j.NotesUnknown = false
+}
+
+// retryLater 还有次数时按 Backoff 安排重试，否则标为失败
+func retryLater(j *Job, cause error, now time.Time) {
+	j.LastError = cause.Error()
+	if j.Attempts >= MaxAttempts {
+		j.Status = StatusFailed
+	} else {
+		j.Status = StatusPending
+		j.NextAttempt = now.Add(Backoff(j.Attempts))
+	}
+}
+
+// Cancel 取消未发出的任务并删除图片
+func (s *Store) Cancel(id string) (Job, error) {
+	var out Job
+	err := s.update(id, func(j *Job) error {
+		if j.Status != StatusPending && j.Status != StatusFailed {
+			return fmt.Errorf("定时任务 %s 当前状态为 %s，不能取消", id, j.Status)
+		}
+		j.Status = StatusCanceled
+		out = *j
+		return nil
+	})
+	if err != nil {
+		return Job{}, err
+	}
+	return out, s.removeAssets(id)
+}
+
+// Reschedule 改发布时间，失败的任务也可以重新安排，尝试次数清零。
+// 重新安排说明人已确认上次没发出去，之前未确认的发布不再核对
+func (s *Store) Reschedule(id string, at time.Time) (Job, error) {
+	var out Job
+	err := s.update(id, func(j *Job) error {
+		if j.Status != StatusPending && j.Status != StatusFailed {
+			return fmt.Errorf("定时任务 %s 当前状态为 %s，不能重新安排", id, j.Status)
+		}
+		j.Status = StatusPending
+		j.PublishAt = at
+		j.NextAttempt = at
+		j.Attempts = 0
+		j.LastError = ""
+		clearUnconfirmed(j)
+		out = *j
+		return nil
+	})
+	return out, err
+}
+
+// RecoverInterrupted 服务启动时处理上次退出时仍在发布中的任务。
+// 不知道是否已经发出去，自动重发可能重复发布，所以标为失败，由人确认后重新安排
+func (s *Store) RecoverInterrupted() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for i := range list.Jobs {
+		if list.Jobs[i].Status != StatusRunning {
+			continue
+		}
+		list.Jobs[i].Status = StatusFailed
+		list.Jobs[i].LastError = "服务在发布途中退出，无法确认是否已发出，请检查账号后重新安排或取消"
+		list.Jobs[i].UpdatedAt = time.Now()
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, s.save(list)
+}
+
+func (s *Store) update(id string, fn func(*Job) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return err
+	}
+	i := indexOf(list.Jobs, id)
+	if i < 0 {
+		return fmt.Errorf("定时任务 %s 不存在", id)
+	}
+	if err := fn(&list.Jobs[i]); err != nil {
+		return err
+	}
+	list.Jobs[i].UpdatedAt = time.Now()
+	return s.save(list)
+}
+
+func (s *Store) removeAssets(id string) error {
+	if err := os.RemoveAll(s.AssetDir(id)); err != nil {
+		return fmt.Errorf("删除任务图片失败: %w", err)
+	}
+	return nil
+}
+
+func indexOf(jobs []Job, id string) int {
+	for i, j := range jobs {
+		if j.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Store) load() (*jobsFile, error) {
+	var list jobsFile
+	if _, err := jsonfile.Load(filepath.Join(s.dir, "schedule.json"), &list); err != nil {
+		return nil, fmt.Errorf("读取定时任务失败: %w", err)
+	}
+	return &list, nil
+}
+
+func (s *Store) save(list *jobsFile) error {
+	return jsonfile.Save(filepath.Join(s.dir, "schedule.json"), list)
+}
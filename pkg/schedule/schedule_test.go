@@ -0,0 +1,210 @@
+package schedule
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Minute, Backoff(1))
+	assert.Equal(t, 6*time.Minute, Backoff(2))
+	assert.Equal(t, 18*time.Minute, Backoff(3))
+	assert.Equal(t, 54*time.Minute, Backoff(4))
+	assert.Equal(t, time.Hour, Backoff(5))
+	assert.Equal(t, time.Hour, Backoff(100))
+}
+
+func TestClaimRetryAndFail(t *testing.T) {
+	s := NewStore(t.TempDir())
+	t0 := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	_, err := s.Add(Job{ID: "late", PublishAt: t0.Add(time.Hour)})
+	require.NoError(t, err)
+	_, err = s.Add(Job{ID: "early", PublishAt: t0})
+	require.NoError(t, err)
+	_, err = s.Add(Job{PublishAt: t0})
+	assert.Error(t, err, "缺 ID")
+
+	_, ok, err := s.Claim(t0.Add(-time.Second))
+	require.NoError(t, err)
+	assert.False(t, ok, "还没到点")
+
+	job, ok, err := s.Claim(t0.Add(2 * time.Hour))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "early", job.ID, "先到期的先发")
+	assert.Equal(t, StatusRunning, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+
+	now := t0.Add(2 * time.Hour)
+	job, err = s.Fail("early", errors.New("网络错误"), now)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, job.Status)
+	assert.Equal(t, now.Add(Backoff(1)), job.NextAttempt)
+
+	job, ok, err = s.Claim(now)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "late", job.ID, "early 在等重试")
+
+	for i := 1; i < MaxAttempts; i++ {
+		now = now.Add(time.Hour)
+		job, ok, err = s.Claim(now)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "early", job.ID)
+		job, err = s.Fail("early", errors.New("网络错误"), now)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, StatusFailed, job.Status, "重试用完")
+	assert.Equal(t, MaxAttempts, job.Attempts)
+
+	_, ok, err = s.Claim(now.Add(24 * time.Hour))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestFailUnconfirmedAndPermanent(t *testing.T) {
+	s := NewStore(t.TempDir())
+	t0 := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, id := range []string{"a", "b", "c"} {
+		_, err := s.Add(Job{ID: id, PublishAt: t0})
+		require.NoError(t, err)
+		_, _, err = s.Claim(t0)
+		require.NoError(t, err)
+	}
+
+	job, err := s.FailUnconfirmed("a", errors.New("未跳转"), []string{"n1"}, t0)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, job.Status, "能核对就等核对后重试")
+	assert.True(t, job.Unconfirmed)
+	assert.Equal(t, []string{"n1"}, job.KnownNotes)
+
+	job, err = s.Fail("a", errors.New("网络错误"), t0)
+	require.NoError(t, err)
+	assert.False(t, job.Unconfirmed, "核对过没发出去后按普通失败处理")
+	assert.Nil(t, job.KnownNotes)
+
+	job, err = s.FailUnverifiable("b", errors.New("未跳转"))
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, job.Status, "没有发布前的列表，无法核对")
+	assert.True(t, job.NotesUnknown)
+
+	job, err = s.FailPermanent("c", errors.New("标题长度超过限制"))
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, job.Status)
+	assert.Equal(t, 1, job.Attempts, "不用完重试次数")
+}
+
+func TestUnconfirmedKnownNotesPersisted(t *testing.T) {
+	s := NewStore(t.TempDir())
+	t0 := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	_, err := s.Add(Job{ID: "a", PublishAt: t0})
+	require.NoError(t, err)
+	_, _, err = s.Claim(t0)
+	require.NoError(t, err)
+	_, err = s.FailUnconfirmed("a", errors.New("未跳转"), []string{}, t0)
+	require.NoError(t, err)
+
+	job, _, err := s.Get("a")
+	require.NoError(t, err)
+	assert.True(t, job.Unconfirmed)
+	assert.Empty(t, job.KnownNotes)
+	assert.False(t, job.NotesUnknown, "发布前列表为空，读回来仍能核对")
+}
+
+func TestRescheduleClearsUnconfirmed(t *testing.T) {
+	s := NewStore(t.TempDir())
+	t0 := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	_, err := s.Add(Job{ID: "a", PublishAt: t0})
+	require.NoError(t, err)
+	_, _, err = s.Claim(t0)
+	require.NoError(t, err)
+	_, err = s.FailUnverifiable("a", errors.New("未跳转"))
+	require.NoError(t, err)
+
+	later := t0.Add(time.Hour)
+	_, err = s.Reschedule("a", later)
+	require.NoError(t, err)
+
+	job, _, err := s.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, job.Status)
+	assert.False(t, job.Unconfirmed, "重新安排后不再拿空列表去核对，以免认成同名旧笔记")
+	assert.False(t, job.NotesUnknown)
+	assert.Nil(t, job.KnownNotes)
+}
+
+func TestFinishCancelReschedule(t *testing.T) {
+	s := NewStore(t.TempDir())
+	t0 := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, id := range []string{"a", "b"} {
+		_, err := s.Add(Job{ID: id, PublishAt: t0})
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(s.AssetDir(id), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(s.AssetDir(id), "01.jpg"), []byte("x"), 0644))
+	}
+
+	_, ok, err := s.Claim(t0)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, s.Finish("a", "n1", "https://example.com/n1"))
+	_, err = os.Stat(s.AssetDir("a"))
+	assert.True(t, os.IsNotExist(err), "发完删图片")
+
+	_, err = s.Cancel("a")
+	assert.Error(t, err, "已发布的不能取消")
+	_, err = s.Reschedule("a", t0)
+	assert.Error(t, err)
+
+	later := t0.AddDate(0, 6, 0)
+	job, err := s.Reschedule("b", later)
+	require.NoError(t, err)
+	assert.Equal(t, later, job.NextAttempt)
+
+	job, err = s.Cancel("b")
+	require.NoError(t, err)
+	assert.Equal(t, StatusCanceled, job.Status)
+	_, err = os.Stat(s.AssetDir("b"))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = s.Cancel("missing")
+	assert.Error(t, err)
+
+	list, err := s.List()
+	require.NoError(t, err)
+	assert.Len(t, list, 2, "取消和已发布的记录保留")
+}
+
+func TestRecoverInterrupted(t *testing.T) {
+	s := NewStore(t.TempDir())
+	t0 := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	_, err := s.Add(Job{ID: "a", PublishAt: t0})
+	require.NoError(t, err)
+	_, _, err = s.Claim(t0)
+	require.NoError(t, err)
+
+	n, err := s.RecoverInterrupted()
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	job, ok, err := s.Get("a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, StatusFailed, job.Status, "不自动重发")
+	assert.NotEmpty(t, job.LastError)
+
+	_, err = s.Reschedule("a", t0.Add(time.Hour))
+	assert.NoError(t, err, "确认后可重新安排")
+}
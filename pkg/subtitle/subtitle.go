@@ -0,0 +1,159 @@
+// Package subtitle 解析 SRT 字幕并转换为纯文本、SRT 或 WebVTT。
+package subtitle
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format 输出格式
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatSRT  Format = "srt"
+	FormatVTT  Format = "vtt"
+)
+
+// ParseFormat 解析输出格式，空串按 text 处理
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "text", "txt":
+		return FormatText, nil
+	case "srt":
+		return FormatSRT, nil
+	case "vtt", "webvtt":
+		return FormatVTT, nil
+	default:
+		return "", fmt.Errorf("不支持的字幕格式 %q，可选: text|srt|vtt", s)
+	}
+}
+
+// Cue 一条字幕
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string // 多行字幕以 \n 连接
+}
+
+// 00:00:01,000 --> 00:00:02,500，毫秒分隔符兼容逗号和点
+var timingRegex = regexp.MustCompile(`(\d+):(\d{2}):(\d{2})[,.](\d{1,3})\s*-->\s*(\d+):(\d{2}):(\d{2})[,.](\d{1,3})`)
+
+// ParseSRT 解析 SRT。序号行可有可无，解析不出时间轴的块直接跳过。
+func ParseSRT(data []byte) ([]Cue, error) {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var cues []Cue
+	for _, block := range strings.Split(text, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+
+		timingAt := -1
+		for i, line := range lines {
+			if timingRegex.MatchString(line) {
+				timingAt = i
+				break
+			}
+		}
+		if timingAt < 0 {
+			continue
+		}
+
+		m := timingRegex.FindStringSubmatch(lines[timingAt])
+		content := strings.TrimSpace(strings.Join(lines[timingAt+1:], "\n"))
+		if content == "" {
+			continue
+		}
+
+		cues = append(cues, Cue{
+			Start: parseTimestamp(m[1:5]),
+			End:   parseTimestamp(m[5:9]),
+			Text:  content,
+		})
+	}
+
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("未解析出任何字幕")
+	}
+	return cues, nil
+}
+
+func parseTimestamp(parts []string) time.Duration {
+	h, _ := strconv.Atoi(parts[0])
+	m, _ := strconv.Atoi(parts[1])
+	s, _ := strconv.Atoi(parts[2])
+	// 毫秒位数不足三位时按小数处理：",5" 是 500ms
+	ms, _ := strconv.Atoi((parts[3] + "00")[:3])
+
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(s)*time.Second + time.Duration(ms)*time.Millisecond
+}
+
+// Render 按格式输出。
+// withTimestamps 只对 text 生效：为 true 时每行前加 [hh:mm:ss]；
+// srt/vtt 的时间轴是格式本身的一部分，不能去掉。
+func Render(cues []Cue, format Format, withTimestamps bool) (string, error) {
+	var b strings.Builder
+
+	switch format {
+	case FormatText:
+		prev := ""
+		for _, c := range cues {
+			line := strings.ReplaceAll(c.Text, "\n", " ")
+			if !withTimestamps {
+				// 去掉时间轴后，相邻的重复行（滚动字幕常见）没有信息量
+				if line == prev {
+					continue
+				}
+				prev = line
+				b.WriteString(line)
+				b.WriteString("\n")
+				continue
+			}
+			fmt.Fprintf(&b, "[%s] %s\n", formatClock(c.Start), line)
+		}
+	case FormatSRT:
+		if !withTimestamps {
+			return "", fmt.Errorf("srt 格式必须包含时间轴")
+		}
+		for i, c := range cues {
+			fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, formatSRTTime(c.Start), formatSRTTime(c.End), c.Text)
+		}
+	case FormatVTT:
+		if !withTimestamps {
+			return "", fmt.Errorf("vtt 格式必须包含时间轴")
+		}
+		b.WriteString("WEBVTT\n\n")
+		for _, c := range cues {
+			fmt.Fprintf(&b, "%s --> %s\n%s\n\n", formatVTTTime(c.Start), formatVTTTime(c.End), c.Text)
+		}
+	default:
+		return "", fmt.Errorf("不支持的字幕格式 %q", format)
+	}
+
+	return b.String(), nil
+}
+
+func splitDuration(d time.Duration) (h, m, s, ms int) {
+	total := int(d / time.Millisecond)
+	return total / 3600000, total / 60000 % 60, total / 1000 % 60, total % 1000
+}
+
+func formatClock(d time.Duration) string {
+	h, m, s, _ := splitDuration(d)
+	return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+}
+
+func formatSRTTime(d time.Duration) string {
+	h, m, s, ms := splitDuration(d)
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", h, m, s, ms)
+}
+
+func formatVTTTime(d time.Duration) string {
+	h, m, s, ms := splitDuration(d)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, ms)
+}
@@ -0,0 +1,72 @@
+package subtitle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sample = "\ufeff1\r\n00:00:01,000 --> 00:00:02,500\r\n大家好\r\n\r\n2\r\n00:00:02,500 --> 00:00:04,000\r\n大家好\r\n\r\n3\r\n00:01:05,20 --> 00:01:07,000\r\n今天聊露营\r\n第二行\r\n"
+
+func TestParseSRT(t *testing.T) {
+	cues, err := ParseSRT([]byte(sample))
+	require.NoError(t, err)
+	require.Len(t, cues, 3)
+
+	assert.Equal(t, time.Second, cues[0].Start)
+	assert.Equal(t, 2500*time.Millisecond, cues[0].End)
+	assert.Equal(t, "大家好", cues[0].Text)
+	assert.Equal(t, time.Minute+5*time.Second+200*time.Millisecond, cues[2].Start, "毫秒不足三位按小数处理")
+	assert.Equal(t, "今天聊露营\n第二行", cues[2].Text)
+
+	_, err = ParseSRT([]byte("not a subtitle"))
+	assert.Error(t, err)
+}
+
+func TestRender(t *testing.T) {
+	cues, err := ParseSRT([]byte(sample))
+	require.NoError(t, err)
+
+	t.Run("纯文本去时间轴并合并相邻重复行", func(t *testing.T) {
+		got, err := Render(cues, FormatText, false)
+		require.NoError(t, err)
+		assert.Equal(t, "大家好\n今天聊露营 第二行\n", got)
+	})
+
+	t.Run("纯文本带时间轴", func(t *testing.T) {
+		got, err := Render(cues, FormatText, true)
+		require.NoError(t, err)
+		assert.Equal(t, "[00:00:01] 大家好\n[00:00:02] 大家好\n[00:01:05] 今天聊露营 第二行\n", got)
+	})
+
+	t.Run("SRT 重新编号", func(t *testing.T) {
+		got, err := Render(cues[:1], FormatSRT, true)
+		require.NoError(t, err)
+		assert.Equal(t, "1\n00:00:01,000 --> 00:00:02,500\n大家好\n\n", got)
+	})
+
+	t.Run("WebVTT 用点分隔毫秒", func(t *testing.T) {
+		got, err := Render(cues[:1], FormatVTT, true)
+		require.NoError(t, err)
+		assert.Equal(t, "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\n大家好\n\n", got)
+	})
+
+	t.Run("SRT/VTT 不能去时间轴", func(t *testing.T) {
+		_, err := Render(cues, FormatSRT, false)
+		assert.Error(t, err)
+		_, err = Render(cues, FormatVTT, false)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"": FormatText, "TXT": FormatText, "srt": FormatSRT, "WebVTT": FormatVTT} {
+		got, err := ParseFormat(in)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseFormat("ass")
+	assert.Error(t, err)
+}
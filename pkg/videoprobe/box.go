@@ -0,0 +1,255 @@
+package videoprobe
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// header 顶层 box 头
+type header struct {
+	typ       string
+	size      int64 // 含头部的总长度
+	headerLen int64
+}
+
+// readHeader 读取 off 处的 box 头；size 为 0 表示延伸到文件末尾，为 1 表示使用 64 位长度
+func readHeader(r io.ReaderAt, off, fileSize int64) (header, error) {
+	buf := make([]byte, 16)
+	if _, err := r.ReadAt(buf[:8], off); err != nil {
+		return header{}, fmt.Errorf("读取 box 头失败: %w", err)
+	}
+	h := header{typ: string(buf[4:8]), size: int64(binary.BigEndian.Uint32(buf)), headerLen: 8}
+
+	switch h.size {
+	case 0:
+		h.size = fileSize - off
+	case 1:
+		if _, err := r.ReadAt(buf[8:16], off+8); err != nil {
+			return header{}, fmt.Errorf("读取 box 长度失败: %w", err)
+		}
+		large := binary.BigEndian.Uint64(buf[8:16])
+		if large > math.MaxInt64 {
+			return header{}, fmt.Errorf("box %q 长度非法", h.typ)
+		}
+		h.size, h.headerLen = int64(large), 16
+	}
+
+	if h.size < h.headerLen || off+h.size > fileSize {
+		return header{}, fmt.Errorf("box %q 长度非法: %d", h.typ, h.size)
+	}
+	return h, nil
+}
+
+// box 已读入内存的子 box
+type box struct {
+	typ  string
+	body []byte
+}
+
+// children 拆分内存中的一串 box；遇到长度不合法的直接报错，不猜测
+func children(data []byte) ([]box, error) {
+	var out []box
+	for len(data) > 0 {
+		if len(data) < 8 {
+			return nil, fmt.Errorf("box 被截断")
+		}
+		size := uint64(binary.BigEndian.Uint32(data))
+		typ := string(data[4:8])
+		headerLen := uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return nil, fmt.Errorf("box %q 被截断", typ)
+			}
+			size, headerLen = binary.BigEndian.Uint64(data[8:16]), 16
+		}
+		if size < headerLen || size > uint64(len(data)) {
+			return nil, fmt.Errorf("box %q 长度非法: %d", typ, size)
+		}
+		out = append(out, box{typ: typ, body: data[headerLen:size]})
+		data = data[size:]
+	}
+	return out, nil
+}
+
+// find 按路径查找第一个子 box，如 find(trak, "mdia", "hdlr")
+func find(data []byte, path ...string) ([]byte, bool) {
+	for _, name := range path {
+		boxes, err := children(data)
+		if err != nil {
+			return nil, false
+		}
+		found := false
+		for _, b := range boxes {
+			if b.typ == name {
+				data, found = b.body, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return data, true
+}
+
+// parseMoov 从 moov 中取时长和第一条视频轨的信息。
+// 分片 MP4（fMP4）的 mvhd 时长通常是 0，依次退到 mvex/mehd 和各轨 mdhd 里找
+func parseMoov(moov []byte, info *Info) error {
+	mvhd, ok := find(moov, "mvhd")
+	if !ok {
+		return fmt.Errorf("缺少 mvhd")
+	}
+	timescale, duration, err := parseMediaHeader(mvhd, "mvhd")
+	if err != nil {
+		return err
+	}
+
+	_, info.Fragmented = find(moov, "mvex")
+	if duration == 0 {
+		if mehd, ok := find(moov, "mvex", "mehd"); ok {
+			duration = parseMehd(mehd)
+		}
+	}
+	info.Duration = toDuration(duration, timescale)
+
+	boxes, err := children(moov)
+	if err != nil {
+		return err
+	}
+	var video []byte
+	for _, b := range boxes {
+		if b.typ != "trak" {
+			continue
+		}
+		if info.Duration == 0 {
+			if mdhd, ok := find(b.body, "mdia", "mdhd"); ok {
+				if ts, d, err := parseMediaHeader(mdhd, "mdhd"); err == nil {
+					info.Duration = max(info.Duration, toDuration(d, ts))
+				}
+			}
+		}
+		if hdlr, ok := find(b.body, "mdia", "hdlr"); video == nil && ok && len(hdlr) >= 12 && string(hdlr[8:12]) == "vide" {
+			video = b.body
+		}
+	}
+	if video == nil {
+		return fmt.Errorf("没有视频轨")
+	}
+	return parseVideoTrak(video, info)
+}
+
+// parseMediaHeader 解析 mvhd/mdhd 的 timescale 和 duration，两者布局相同
+func parseMediaHeader(b []byte, name string) (timescale, duration uint64, err error) {
+	if len(b) < 4 {
+		return 0, 0, fmt.Errorf("%s 被截断", name)
+	}
+	if b[0] == 1 {
+		if len(b) < 32 {
+			return 0, 0, fmt.Errorf("%s 被截断", name)
+		}
+		timescale = uint64(binary.BigEndian.Uint32(b[20:24]))
+		duration = binary.BigEndian.Uint64(b[24:32])
+	} else {
+		if len(b) < 20 {
+			return 0, 0, fmt.Errorf("%s 被截断", name)
+		}
+		timescale = uint64(binary.BigEndian.Uint32(b[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(b[16:20]))
+	}
+	if timescale == 0 {
+		return 0, 0, fmt.Errorf("%s timescale 为 0", name)
+	}
+	// 全 1 表示时长未知
+	if duration == math.MaxUint32 || duration == math.MaxUint64 {
+		duration = 0
+	}
+	return timescale, duration, nil
+}
+
+// parseMehd 分片 MP4 的总时长，单位是 mvhd 的 timescale
+func parseMehd(b []byte) uint64 {
+	if len(b) >= 12 && b[0] == 1 {
+		return binary.BigEndian.Uint64(b[4:12])
+	}
+	if len(b) >= 8 {
+		return uint64(binary.BigEndian.Uint32(b[4:8]))
+	}
+	return 0
+}
+
+func toDuration(duration, timescale uint64) time.Duration {
+	if timescale == 0 {
+		return 0
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+}
+
+// parseVideoTrak 取视频轨的编码、编码宽高和旋转角度
+func parseVideoTrak(trak []byte, info *Info) error {
+	if tkhd, ok := find(trak, "tkhd"); ok {
+		w, h, rot, err := parseTkhd(tkhd)
+		if err != nil {
+			return err
+		}
+		info.Width, info.Height, info.Rotation = w, h, rot
+	}
+
+	stsd, ok := find(trak, "mdia", "minf", "stbl", "stsd")
+	if !ok || len(stsd) < 8 {
+		return fmt.Errorf("视频轨缺少 stsd")
+	}
+	entries, err := children(stsd[8:])
+	if err != nil || len(entries) == 0 {
+		return fmt.Errorf("视频轨 stsd 无效")
+	}
+	entry := entries[0]
+	info.Codec = entry.typ
+
+	// VisualSampleEntry: reserved(6) + data_reference_index(2) + pre_defined/reserved(16) + width(2) + height(2)
+	if len(entry.body) >= 28 {
+		w := int(binary.BigEndian.Uint16(entry.body[24:26]))
+		h := int(binary.BigEndian.Uint16(entry.body[26:28]))
+		if w > 0 && h > 0 {
+			info.Width, info.Height = w, h
+		}
+	}
+	return nil
+}
+
+// parseTkhd 解析 track header 的宽高（16.16 定点）和变换矩阵中的旋转角度。
+// 手机竖拍的视频通常是横向编码加 90° 矩阵，宽高仍是旋转前的尺寸。
+func parseTkhd(b []byte) (width, height, rotation int, err error) {
+	if len(b) < 4 {
+		return 0, 0, 0, fmt.Errorf("tkhd 被截断")
+	}
+	// version 0/1 的时间字段分别为 4/8 字节，之后依次是 reserved(8)、layer(2)、
+	// alternate_group(2)、volume(2)、reserved(2)、matrix(36)、width(4)、height(4)
+	matrixOff := 4 + 20 + 16
+	if b[0] == 1 {
+		matrixOff = 4 + 32 + 16
+	}
+	if len(b) < matrixOff+44 {
+		return 0, 0, 0, fmt.Errorf("tkhd 被截断")
+	}
+
+	m := b[matrixOff:]
+	a := float64(int32(binary.BigEndian.Uint32(m[0:4])))
+	bb := float64(int32(binary.BigEndian.Uint32(m[4:8])))
+	rotation = normalizeRotation(math.Atan2(bb, a) * 180 / math.Pi)
+
+	width = int(binary.BigEndian.Uint32(m[36:40]) >> 16)
+	height = int(binary.BigEndian.Uint32(m[40:44]) >> 16)
+	return width, height, rotation, nil
+}
+
+// normalizeRotation 把角度规整到 0/90/180/270
+func normalizeRotation(deg float64) int {
+	r := int(math.Round(deg/90)) * 90
+	return ((r % 360) + 360) % 360
+}
@@ -0,0 +1,80 @@
+package videoprobe
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Limits 平台对上传视频的限制
+type Limits struct {
+	MaxSize     int64         // 文件大小上限（字节）
+	MinDuration time.Duration // 时长下限
+	MaxDuration time.Duration // 时长上限
+	MaxLongSide int           // 长边像素上限
+	Codecs      []string      // 允许的视频编码（sample entry fourcc），为空不限制
+}
+
+// PlatformLimits 网页端创作中心的上传限制。
+// HEVC/AV1 等编码能传上去，但转码常常卡在处理中直到超时，所以只放行 H.264。
+var PlatformLimits = Limits{
+	MaxSize:     20 << 30,
+	MinDuration: time.Second,
+	MaxDuration: 60 * time.Minute,
+	MaxLongSide: 4096,
+	Codecs:      []string{"avc1", "avc3"},
+}
+
+// LimitError 视频不符合限制，列出全部问题而不是只报第一个
+type LimitError struct {
+	Problems []string
+}
+
+func (e *LimitError) Error() string {
+	return "视频不符合平台要求: " + strings.Join(e.Problems, "；")
+}
+
+// Check 校验探测结果，不符合时返回 *LimitError
+func (l Limits) Check(info *Info) error {
+	var problems []string
+
+	if l.MaxSize > 0 && info.Size > l.MaxSize {
+		problems = append(problems, fmt.Sprintf("文件大小 %s 超过上限 %s", formatBytes(info.Size), formatBytes(l.MaxSize)))
+	}
+	// 分片 MP4 头部查不到时长时不按 0 秒拒绝，交给平台判断
+	unknown := info.Fragmented && info.Duration == 0
+	if l.MinDuration > 0 && info.Duration < l.MinDuration && !unknown {
+		problems = append(problems, fmt.Sprintf("时长 %s 短于下限 %s", info.Duration.Round(time.Millisecond), l.MinDuration))
+	}
+	if l.MaxDuration > 0 && info.Duration > l.MaxDuration {
+		problems = append(problems, fmt.Sprintf("时长 %s 超过上限 %s", info.Duration.Round(time.Second), l.MaxDuration))
+	}
+	if l.MaxLongSide > 0 {
+		if long := max(info.Width, info.Height); long > l.MaxLongSide {
+			problems = append(problems, fmt.Sprintf("分辨率 %dx%d 超过长边上限 %d", info.Width, info.Height, l.MaxLongSide))
+		}
+	}
+	if len(l.Codecs) > 0 && !slices.Contains(l.Codecs, info.Codec) {
+		problems = append(problems, fmt.Sprintf("视频编码 %s 不受支持，请转码为 H.264", info.CodecName()))
+	}
+
+	if len(problems) > 0 {
+		return &LimitError{Problems: problems}
+	}
+	return nil
+}
+
+// formatBytes 以 KB/MB/GB 展示字节数
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 2; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(n)/float64(div), "KMG"[exp])
+}
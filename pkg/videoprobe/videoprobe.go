@@ -0,0 +1,145 @@
+// Package videoprobe 不解码地读取 MP4/MOV 容器的元数据：时长、分辨率、编码、码率、旋转角度和文件大小。
+// 纯 Go 实现，只遍历 box 结构，用于在启动浏览器上传前快速校验视频是否符合平台要求。
+package videoprobe
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Info 视频探测结果
+type Info struct {
+	Path      string        `json:"path"`
+	Size      int64         `json:"size"`               // 文件字节数
+	Container string        `json:"container"`          // mp4 或 mov
+	Duration  time.Duration `json:"duration"`           // 纳秒
+	Seconds   float64       `json:"seconds"`            // 时长（秒），便于阅读
+	Width     int           `json:"width"`              // 编码宽度
+	Height    int           `json:"height"`             // 编码高度
+	Rotation  int           `json:"rotation,omitempty"` // 播放时顺时针旋转角度：0/90/180/270
+	Codec     string        `json:"codec"`              // 视频轨 sample entry，如 avc1、hvc1
+	Bitrate   int64         `json:"bitrate"`            // 平均码率 bit/s（按文件大小/时长估算）
+
+	// Fragmented 分片 MP4（fMP4）。这类文件的时长可能在头部查不到，此时 Duration 为 0
+	Fragmented bool `json:"fragmented,omitempty"`
+}
+
+// ErrNotMP4 文件不是 MP4/MOV 容器，调用方可以跳过探测交给平台处理
+var ErrNotMP4 = errors.New("不是 MP4/MOV 文件")
+
+// DisplaySize 按旋转角度换算后的显示宽高
+func (i *Info) DisplaySize() (int, int) {
+	if i.Rotation == 90 || i.Rotation == 270 {
+		return i.Height, i.Width
+	}
+	return i.Width, i.Height
+}
+
+// CodecName 编码的可读名称，未知编码原样返回 fourcc
+func (i *Info) CodecName() string {
+	switch i.Codec {
+	case "avc1", "avc3":
+		return "H.264"
+	case "hvc1", "hev1":
+		return "HEVC"
+	case "av01":
+		return "AV1"
+	case "vp09":
+		return "VP9"
+	case "mp4v":
+		return "MPEG-4"
+	case "ap4h", "apch", "apcn", "apcs", "apco":
+		return "ProRes"
+	}
+	return i.Codec
+}
+
+// maxMoovSize moov 读入内存的上限，正常视频的 moov 远小于此
+const maxMoovSize = 64 << 20
+
+// Probe 读取视频文件的容器元数据
+func Probe(path string) (*Info, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if st.IsDir() {
+		return nil, fmt.Errorf("%s 是目录", path)
+	}
+
+	info, err := probe(f, st.Size())
+	if err != nil {
+		return nil, err
+	}
+	info.Path = path
+	return info, nil
+}
+
+// probe 遍历顶层 box，找到 ftyp 和 moov 后解析
+func probe(r io.ReaderAt, size int64) (*Info, error) {
+	info := &Info{Size: size, Container: "mp4"}
+
+	var moov []byte
+	sawFtyp := false
+	for off := int64(0); off < size; {
+		h, err := readHeader(r, off, size)
+		if err != nil {
+			if !sawFtyp {
+				return nil, fmt.Errorf("%w: %v", ErrNotMP4, err)
+			}
+			return nil, err
+		}
+		if off == 0 && h.typ != "ftyp" && !topLevelTypes[h.typ] {
+			return nil, ErrNotMP4
+		}
+
+		switch h.typ {
+		case "ftyp":
+			sawFtyp = true
+			brand := make([]byte, 4)
+			if h.size-h.headerLen >= 4 {
+				if _, err := r.ReadAt(brand, off+h.headerLen); err != nil {
+					return nil, fmt.Errorf("读取 ftyp 失败: %w", err)
+				}
+				if string(brand) == "qt  " {
+					info.Container = "mov"
+				}
+			}
+		case "moov":
+			body := h.size - h.headerLen
+			if body > maxMoovSize {
+				return nil, fmt.Errorf("moov 过大: %d 字节", body)
+			}
+			moov = make([]byte, body)
+			if _, err := r.ReadAt(moov, off+h.headerLen); err != nil {
+				return nil, fmt.Errorf("读取 moov 失败: %w", err)
+			}
+		}
+		off += h.size
+	}
+
+	if moov == nil {
+		return nil, fmt.Errorf("缺少 moov，文件可能不完整")
+	}
+	if err := parseMoov(moov, info); err != nil {
+		return nil, err
+	}
+
+	if info.Duration > 0 {
+		info.Bitrate = int64(float64(size*8) / info.Duration.Seconds())
+	}
+	info.Seconds = info.Duration.Seconds()
+	return info, nil
+}
+
+// topLevelTypes 没有 ftyp 的老式 MOV 也可能以这些 box 开头
+var topLevelTypes = map[string]bool{"moov": true, "mdat": true, "wide": true, "free": true, "skip": true}
@@ -0,0 +1,182 @@
+package videoprobe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mkbox(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	out := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(out, uint32(8+len(body)))
+	copy(out[4:], typ)
+	return append(out, body...)
+}
+
+func u16(v uint16) []byte { return binary.BigEndian.AppendUint16(nil, v) }
+func u32(v uint32) []byte { return binary.BigEndian.AppendUint32(nil, v) }
+
+// mvhd version 0
+func mvhd(timescale, duration uint32) []byte {
+	return mkbox("mvhd", u32(0), u32(0), u32(0), u32(timescale), u32(duration), make([]byte, 80))
+}
+
+// tkhd version 0，matrix 取 (a, b, c, d)，宽高为整数像素
+func tkhd(a, b, c, d int32, w, h uint32) []byte {
+	matrix := bytes.Join([][]byte{
+		u32(uint32(a)), u32(uint32(b)), u32(0),
+		u32(uint32(c)), u32(uint32(d)), u32(0),
+		u32(0), u32(0), u32(0x40000000),
+	}, nil)
+	return mkbox("tkhd", u32(0), make([]byte, 20), make([]byte, 16), matrix, u32(w<<16), u32(h<<16))
+}
+
+func hdlr(handler string) []byte {
+	return mkbox("hdlr", u32(0), u32(0), []byte(handler), make([]byte, 12))
+}
+
+// stsd 只放一个 VisualSampleEntry
+func stsd(codec string, w, h uint16) []byte {
+	entry := mkbox(codec, make([]byte, 6), u16(1), make([]byte, 16), u16(w), u16(h), make([]byte, 50))
+	return mkbox("stsd", u32(0), u32(1), entry)
+}
+
+func trak(handler string, header, sampleDesc []byte) []byte {
+	return mkbox("trak", header, mkbox("mdia", hdlr(handler), mkbox("minf", mkbox("stbl", sampleDesc))))
+}
+
+const one = 1 << 16
+
+func writeFile(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "v.mp4")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	return path
+}
+
+func TestProbeMP4(t *testing.T) {
+	audio := trak("soun", tkhd(one, 0, 0, one, 0, 0), mkbox("stsd", u32(0), u32(0)))
+	video := trak("vide", tkhd(0, one, -one, 0, 1920, 1080), stsd("avc1", 1920, 1080))
+
+	// moov 放在 mdat 之后，和未做 faststart 的文件一样
+	data := bytes.Join([][]byte{
+		mkbox("ftyp", []byte("isom"), u32(0x200)),
+		mkbox("mdat", make([]byte, 4096)),
+		mkbox("moov", mvhd(1000, 12500), audio, video),
+	}, nil)
+
+	info, err := Probe(writeFile(t, data))
+	require.NoError(t, err)
+
+	assert.Equal(t, "mp4", info.Container)
+	assert.Equal(t, 12500*time.Millisecond, info.Duration)
+	assert.InDelta(t, 12.5, info.Seconds, 1e-9)
+	assert.Equal(t, "avc1", info.Codec)
+	assert.Equal(t, "H.264", info.CodecName())
+	assert.Equal(t, 1920, info.Width)
+	assert.Equal(t, 1080, info.Height)
+	assert.Equal(t, 90, info.Rotation)
+	w, h := info.DisplaySize()
+	assert.Equal(t, [2]int{1080, 1920}, [2]int{w, h}, "竖拍视频显示为竖屏")
+	assert.Equal(t, int64(len(data)), info.Size)
+	assert.Equal(t, int64(float64(len(data)*8)/12.5), info.Bitrate)
+}
+
+func TestProbeMOVAndLargeSize(t *testing.T) {
+	// 64 位长度的 mdat
+	mdat := bytes.Join([][]byte{u32(1), []byte("mdat"), binary.BigEndian.AppendUint64(nil, 16+10), make([]byte, 10)}, nil)
+	data := bytes.Join([][]byte{
+		mkbox("ftyp", []byte("qt  "), u32(0)),
+		mkbox("moov", mvhd(600, 1200), trak("vide", tkhd(-one, 0, 0, -one, 3840, 2160), stsd("hvc1", 3840, 2160))),
+		mdat,
+	}, nil)
+
+	info, err := Probe(writeFile(t, data))
+	require.NoError(t, err)
+	assert.Equal(t, "mov", info.Container)
+	assert.Equal(t, 2*time.Second, info.Duration)
+	assert.Equal(t, "HEVC", info.CodecName())
+	assert.Equal(t, 180, info.Rotation)
+}
+
+func TestProbeInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "不是视频", data: []byte("hello world, definitely not an mp4")},
+		{name: "缺少 moov", data: bytes.Join([][]byte{mkbox("ftyp", []byte("isom")), mkbox("mdat", make([]byte, 8))}, nil)},
+		{name: "没有视频轨", data: bytes.Join([][]byte{mkbox("ftyp", []byte("isom")), mkbox("moov", mvhd(1000, 1000))}, nil)},
+		{name: "box 长度越界", data: append(mkbox("ftyp", []byte("isom")), 0, 0, 0xFF, 0xFF, 'm', 'o', 'o', 'v')},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Probe(writeFile(t, tt.data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestProbeFragmented 分片 MP4 的 mvhd 时长为 0，先取 mehd，没有再取视频轨 mdhd，都没有时不按 0 秒拒绝。
+func TestProbeFragmented(t *testing.T) {
+	mdhd := func(timescale, duration uint32) []byte {
+		return mkbox("mdhd", u32(0), u32(0), u32(0), u32(timescale), u32(duration), make([]byte, 4))
+	}
+	fragTrak := func(media ...[]byte) []byte {
+		mdia := append(bytes.Join(media, nil), hdlr("vide")...)
+		mdia = append(mdia, mkbox("minf", mkbox("stbl", stsd("avc1", 1080, 1920)))...)
+		return mkbox("trak", tkhd(one, 0, 0, one, 1080, 1920), mkbox("mdia", mdia))
+	}
+	file := func(moov ...[]byte) []byte {
+		return bytes.Join([][]byte{
+			mkbox("ftyp", []byte("iso6"), u32(0)),
+			mkbox("moov", moov...),
+			mkbox("moof", make([]byte, 16)),
+			mkbox("mdat", make([]byte, 64)),
+		}, nil)
+	}
+	mehd := mkbox("mehd", u32(0), u32(15000))
+
+	info, err := Probe(writeFile(t, file(mvhd(1000, 0), mkbox("mvex", mehd), fragTrak())))
+	require.NoError(t, err)
+	assert.True(t, info.Fragmented)
+	assert.Equal(t, 15*time.Second, info.Duration, "取 mehd")
+
+	info, err = Probe(writeFile(t, file(mvhd(1000, 0), mkbox("mvex"), fragTrak(mdhd(90000, 900000)))))
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, info.Duration, "没有 mehd 时取 mdhd")
+
+	info, err = Probe(writeFile(t, file(mvhd(1000, 0), mkbox("mvex"), fragTrak())))
+	require.NoError(t, err)
+	assert.Zero(t, info.Duration)
+	assert.NoError(t, PlatformLimits.Check(info), "时长未知的分片 MP4 交给平台判断")
+}
+
+func TestProbeNotMP4(t *testing.T) {
+	_, err := Probe(writeFile(t, append([]byte{0x1A, 0x45, 0xDF, 0xA3}, make([]byte, 64)...))) // Matroska/WebM
+	assert.ErrorIs(t, err, ErrNotMP4)
+}
+
+func TestLimitsCheck(t *testing.T) {
+	ok := &Info{Size: 50 << 20, Duration: 30 * time.Second, Width: 1920, Height: 1080, Codec: "avc1"}
+	assert.NoError(t, PlatformLimits.Check(ok))
+
+	bad := &Info{Size: 21 << 30, Duration: 2 * time.Hour, Width: 7680, Height: 4320, Codec: "hvc1"}
+	err := PlatformLimits.Check(bad)
+	var limitErr *LimitError
+	require.True(t, errors.As(err, &limitErr))
+	assert.Len(t, limitErr.Problems, 4, "所有问题一次报全")
+	assert.Contains(t, err.Error(), "21.0GB")
+	assert.Contains(t, err.Error(), "HEVC")
+
+	short := &Info{Duration: 500 * time.Millisecond, Width: 720, Height: 1280, Codec: "avc1"}
+	assert.ErrorContains(t, PlatformLimits.Check(short), "短于下限")
+}
@@ -0,0 +1,48 @@
+package watchlist
+
+import "time"
+
+// Growth 一段时间内的增量
+type Growth struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Liked     int64     `json:"liked"`
+	Collected int64     `json:"collected"`
+	Comment   int64     `json:"comment"`
+	Shared    int64     `json:"shared"`
+	// LikedPerHour 点赞的平均小时增速，时间跨度为 0 时为 0
+	LikedPerHour float64 `json:"liked_per_hour"`
+	// Exact 两端采样都是精确值时增量才精确
+	Exact bool `json:"exact"`
+}
+
+// Summarize 给出整个序列的总增量和相邻两次采样之间的增量。序列少于两个点时返回 nil。
+func Summarize(series []Snapshot) (total *Growth, steps []Growth) {
+	if len(series) < 2 {
+		return nil, nil
+	}
+
+	steps = make([]Growth, 0, len(series)-1)
+	for i := 1; i < len(series); i++ {
+		steps = append(steps, growthBetween(series[i-1], series[i]))
+	}
+
+	g := growthBetween(series[0], series[len(series)-1])
+	return &g, steps
+}
+
+func growthBetween(a, b Snapshot) Growth {
+	g := Growth{
+		From:      a.At,
+		To:        b.At,
+		Liked:     b.Liked - a.Liked,
+		Collected: b.Collected - a.Collected,
+		Comment:   b.Comment - a.Comment,
+		Shared:    b.Shared - a.Shared,
+		Exact:     a.Exact && b.Exact,
+	}
+	if hours := b.At.Sub(a.At).Hours(); hours > 0 {
+		g.LikedPerHour = float64(g.Liked) / hours
+	}
+	return g
+}
@@ -0,0 +1,195 @@
+// Package watchlist 维护关注的笔记列表和每篇笔记的互动数序列，数据存成本地 JSON 文件。
+//
+// 列表一个文件（watchlist.json），每篇笔记的序列各一个文件（series/<feed_id>.json），
+// 采样时只重写对应笔记的序列，不会因为笔记多而越写越慢。
+package watchlist
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/jsonfile"
+)
+
+// maxSnapshots 单篇笔记最多保留的采样数，超出丢最早的；按小时采样约够七个月
+const maxSnapshots = 5000
+
+// Note 关注的笔记
+type Note struct {
+	FeedID        string     `json:"feed_id"`
+	XsecToken     string     `json:"xsec_token"`
+	Label         string     `json:"label,omitempty"` // 调用方自定义的备注，如「竞品A-新品」
+	AddedAt       time.Time  `json:"added_at"`
+	LastSampledAt *time.Time `json:"last_sampled_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"` // 最近一次采样失败的原因，成功后清空
+}
+
+// Snapshot 一次采样的互动数
+type Snapshot struct {
+	At        time.Time `json:"at"`
+	Liked     int64     `json:"liked"`
+	Collected int64     `json:"collected"`
+	Comment   int64     `json:"comment"`
+	Shared    int64     `json:"shared"`
+	// Exact 四个数是否都是精确值。过万后页面只给「1.2万」，此时增量也只是近似
+	Exact bool `json:"exact"`
+}
+
+// listFile watchlist.json 的结构
+type listFile struct {
+	Notes []Note `json:"notes"`
+}
+
+// Store 关注列表与序列的存储，并发安全
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewStore 创建存储，目录在第一次写入时创建
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Add 加入关注，已存在时只更新 xsec_token 和备注，返回是否为新加入
+func (s *Store) Add(note Note) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return false, err
+	}
+
+	for i := range list.Notes {
+		if list.Notes[i].FeedID == note.FeedID {
+			list.Notes[i].XsecToken = note.XsecToken
+			if note.Label != "" {
+				list.Notes[i].Label = note.Label
+			}
+			return false, s.save(list)
+		}
+	}
+
+	if note.AddedAt.IsZero() {
+		note.AddedAt = time.Now()
+	}
+	list.Notes = append(list.Notes, note)
+	return true, s.save(list)
+}
+
+// Remove 取消关注，返回是否存在。已采集的序列保留，重新关注后接着记录
+func (s *Store) Remove(feedID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return false, err
+	}
+
+	for i, n := range list.Notes {
+		if n.FeedID == feedID {
+			list.Notes = append(list.Notes[:i], list.Notes[i+1:]...)
+			return true, s.save(list)
+		}
+	}
+	return false, nil
+}
+
+// List 返回全部关注的笔记，按加入时间排序
+func (s *Store) List() ([]Note, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(list.Notes, func(i, j int) bool {
+		return list.Notes[i].AddedAt.Before(list.Notes[j].AddedAt)
+	})
+	return list.Notes, nil
+}
+
+// Record 记录一次采样结果：成功时追加快照，失败时记下原因
+func (s *Store) Record(feedID string, snap *Snapshot, sampleErr error) error {
+	if snap == nil && sampleErr == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if snap != nil {
+		var series []Snapshot
+		if _, err := jsonfile.Load(s.seriesPath(feedID), &series); err != nil {
+			return err
+		}
+		series = append(series, *snap)
+		if len(series) > maxSnapshots {
+			series = series[len(series)-maxSnapshots:]
+		}
+		if err := jsonfile.Save(s.seriesPath(feedID), series); err != nil {
+			return err
+		}
+	}
+
+	list, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i := range list.Notes {
+		if list.Notes[i].FeedID != feedID {
+			continue
+		}
+		if sampleErr != nil {
+			list.Notes[i].LastError = sampleErr.Error()
+		} else {
+			at := snap.At
+			list.Notes[i].LastSampledAt = &at
+			list.Notes[i].LastError = ""
+		}
+		return s.save(list)
+	}
+	return nil
+}
+
+// History 返回 since 之后的序列，按时间升序；since 为零值时返回全部
+func (s *Store) History(feedID string, since time.Time) ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var series []Snapshot
+	if _, err := jsonfile.Load(s.seriesPath(feedID), &series); err != nil {
+		return nil, err
+	}
+
+	filtered := series[:0]
+	for _, snap := range series {
+		if !snap.At.Before(since) {
+			filtered = append(filtered, snap)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *Store) load() (*listFile, error) {
+	var list listFile
+	if _, err := jsonfile.Load(filepath.Join(s.dir, "watchlist.json"), &list); err != nil {
+		return nil, fmt.Errorf("读取关注列表失败: %w", err)
+	}
+	return &list, nil
+}
+
+func (s *Store) save(list *listFile) error {
+	return jsonfile.Save(filepath.Join(s.dir, "watchlist.json"), list)
+}
+
+func (s *Store) seriesPath(feedID string) string {
+	// feed_id 是十六进制串，Base 只是防止调用方传入路径
+	return filepath.Join(s.dir, "series", filepath.Base(feedID)+".json")
+}
@@ -0,0 +1,83 @@
+package watchlist
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	s := NewStore(t.TempDir())
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	added, err := s.Add(Note{FeedID: "a", XsecToken: "t1", Label: "竞品", AddedAt: t0})
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = s.Add(Note{FeedID: "a", XsecToken: "t2"})
+	require.NoError(t, err)
+	assert.False(t, added, "重复添加只更新")
+
+	_, err = s.Add(Note{FeedID: "b", XsecToken: "t", AddedAt: t0.Add(time.Hour)})
+	require.NoError(t, err)
+
+	notes, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+	assert.Equal(t, "t2", notes[0].XsecToken)
+	assert.Equal(t, "竞品", notes[0].Label, "空备注不覆盖已有备注")
+
+	require.NoError(t, s.Record("a", &Snapshot{At: t0, Liked: 10}, nil))
+	require.NoError(t, s.Record("a", &Snapshot{At: t0.Add(time.Hour), Liked: 30}, nil))
+	require.NoError(t, s.Record("b", nil, errors.New("笔记已删除")))
+
+	series, err := s.History("a", time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, series, 2)
+
+	series, err = s.History("a", t0.Add(30*time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, series, 1, "since 之前的点被过滤")
+
+	notes, _ = s.List()
+	assert.Equal(t, t0.Add(time.Hour), *notes[0].LastSampledAt)
+	assert.Equal(t, "笔记已删除", notes[1].LastError)
+
+	removed, err := s.Remove("a")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, _ = s.Remove("a")
+	assert.False(t, removed)
+
+	series, _ = s.History("a", time.Time{})
+	assert.Len(t, series, 2, "取消关注后序列保留")
+}
+
+func TestSummarize(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := []Snapshot{
+		{At: t0, Liked: 100, Collected: 10, Exact: true},
+		{At: t0.Add(time.Hour), Liked: 160, Collected: 12, Exact: true},
+		{At: t0.Add(2 * time.Hour), Liked: 12000, Collected: 20, Exact: false},
+	}
+
+	total, steps := Summarize(series)
+	require.NotNil(t, total)
+	require.Len(t, steps, 2)
+
+	assert.Equal(t, int64(60), steps[0].Liked)
+	assert.Equal(t, 60.0, steps[0].LikedPerHour)
+	assert.True(t, steps[0].Exact)
+	assert.False(t, steps[1].Exact, "任一端是近似值，增量就是近似的")
+
+	assert.Equal(t, int64(11900), total.Liked)
+	assert.Equal(t, int64(10), total.Collected)
+	assert.Equal(t, 5950.0, total.LikedPerHour)
+
+	total, steps = Summarize(series[:1])
+	assert.Nil(t, total)
+	assert.Nil(t, steps)
+}
@@ -0,0 +1,80 @@
+// Package xhscdn 由小红书页面里的 fileid 拼出可直接访问的图片地址。
+//
+// 页面上的封面、视频首帧、缩略图有些只给 fileid，地址需要自己拼。
+// 图片处理走 CDN 的 imageView2 参数：按宽度等比缩放、转格式。
+package xhscdn
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Host 图片 CDN 域名，不需要签名
+const Host = "https://ci.xiaohongshu.com"
+
+// Format 输出格式
+type Format string
+
+const (
+	FormatOriginal Format = ""     // 不转格式
+	FormatWebP     Format = "webp" // 体积最小
+	FormatJPG      Format = "jpg"  // 兼容性最好
+	FormatPNG      Format = "png"
+)
+
+// ParseFormat 解析格式，空串表示不转格式；jpeg 视同 jpg
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case FormatOriginal, FormatWebP, FormatJPG, FormatPNG:
+		return f, nil
+	case "jpeg":
+		return FormatJPG, nil
+	default:
+		return "", fmt.Errorf("不支持的图片格式 %q，可选: webp|jpg|png", s)
+	}
+}
+
+// Options 图片处理参数，零值为原图
+type Options struct {
+	Width  int    // 等比缩放到该宽度，0 不缩放
+	Format Format // 为空不转格式
+}
+
+// Validate 检查参数
+func (o Options) Validate() error {
+	if o.Width < 0 {
+		return fmt.Errorf("图片宽度不能为负数: %d", o.Width)
+	}
+	_, err := ParseFormat(string(o.Format))
+	return err
+}
+
+// ImageURL 拼出 fileid 对应的图片地址，fileid 为空时返回空串。
+//
+//	ImageURL("abc", Options{Width: 720, Format: FormatWebP})
+//	// https://ci.xiaohongshu.com/abc?imageView2/2/w/720/format/webp
+func ImageURL(fileID string, opts Options) string {
+	fileID = strings.Trim(strings.TrimSpace(fileID), "/")
+	if fileID == "" {
+		return ""
+	}
+
+	u := Host + "/" + url.PathEscape(fileID)
+	// fileid 里的 / 是路径的一部分（如 spectrum/xxx），不能转义
+	u = strings.ReplaceAll(u, "%2F", "/")
+
+	var params []string
+	if opts.Width > 0 {
+		params = append(params, fmt.Sprintf("w/%d", opts.Width))
+	}
+	if opts.Format != FormatOriginal {
+		params = append(params, "format/"+string(opts.Format))
+	}
+	if len(params) == 0 {
+		return u
+	}
+
+	// mode 2：限定宽度、高度等比，不裁剪
+	return u + "?imageView2/2/" + strings.Join(params, "/")
+}
@@ -0,0 +1,42 @@
+package xhscdn
+
+import "testing"
+
+func TestImageURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		fileID string
+		opts   Options
+		want   string
+	}{
+		{"原图", "1040g2sg31abc", Options{}, "https://ci.xiaohongshu.com/1040g2sg31abc"},
+		{"缩放加转格式", "1040g2sg31abc", Options{Width: 720, Format: FormatWebP}, "https://ci.xiaohongshu.com/1040g2sg31abc?imageView2/2/w/720/format/webp"},
+		{"只转格式", "abc", Options{Format: FormatJPG}, "https://ci.xiaohongshu.com/abc?imageView2/2/format/jpg"},
+		{"只缩放", "abc", Options{Width: 360}, "https://ci.xiaohongshu.com/abc?imageView2/2/w/360"},
+		{"带目录的 fileid 保留斜杠", "/spectrum/abc", Options{}, "https://ci.xiaohongshu.com/spectrum/abc"},
+		{"空 fileid", "  ", Options{Width: 720}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ImageURL(tt.fileID, tt.opts); got != tt.want {
+				t.Errorf("ImageURL() = %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("JPEG"); err != nil || f != FormatJPG {
+		t.Errorf("jpeg 应视同 jpg, got %q %v", f, err)
+	}
+	if f, err := ParseFormat(""); err != nil || f != FormatOriginal {
+		t.Errorf("空串应为原格式, got %q %v", f, err)
+	}
+	if _, err := ParseFormat("gif"); err == nil {
+		t.Errorf("不支持的格式应报错")
+	}
+	if err := (Options{Width: -1}).Validate(); err == nil {
+		t.Errorf("负数宽度应报错")
+	}
+}
@@ -0,0 +1,56 @@
+package xhsutil
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Count 解析后的互动数。
+// 小红书只给展示用的字符串，过万后按「1.2万」取整，评论数还有「10+」这种下限写法，
+// 所以只有纯数字才是精确值，其余 Value 是近似值或下限。
+type Count struct {
+	Value int64 `json:"value"`
+	Exact bool  `json:"exact"`
+}
+
+// countUnits 数量单位。w、k 出现在部分海外账号的展示里
+var countUnits = map[string]float64{
+	"万": 1e4,
+	"w": 1e4,
+	"亿": 1e8,
+	"k": 1e3,
+}
+
+// ParseCount 解析展示用的数量字符串，如 "1234"、"1.2万"、"10+"、"1,024"。
+// 空串和无法识别的内容（如未互动时显示的「赞」）返回 false。
+func ParseCount(s string) (Count, bool) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if s == "" {
+		return Count{}, false
+	}
+
+	exact := true
+	if trimmed, ok := strings.CutSuffix(s, "+"); ok {
+		s, exact = trimmed, false
+	}
+
+	multiplier := 1.0
+	for unit, m := range countUnits {
+		if trimmed, ok := strings.CutSuffix(strings.ToLower(s), unit); ok {
+			s, multiplier, exact = strings.TrimSpace(trimmed), m, false
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+		return Count{}, false
+	}
+	if multiplier == 1 && n != math.Trunc(n) {
+		// 不带单位的小数不是合法的数量
+		return Count{}, false
+	}
+
+	return Count{Value: int64(math.Round(n * multiplier)), Exact: exact}, true
+}
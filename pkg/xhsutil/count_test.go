@@ -0,0 +1,39 @@
+package xhsutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Count
+		ok    bool
+	}{
+		{"1234", Count{Value: 1234, Exact: true}, true},
+		{"0", Count{Value: 0, Exact: true}, true},
+		{" 1,024 ", Count{Value: 1024, Exact: true}, true},
+		{"1.2万", Count{Value: 12000}, true},
+		{"10万", Count{Value: 100000}, true},
+		{"1.5w", Count{Value: 15000}, true},
+		{"2.3W", Count{Value: 23000}, true},
+		{"1.1亿", Count{Value: 110000000}, true},
+		{"10+", Count{Value: 10}, true},
+		{"1万+", Count{Value: 10000}, true},
+		{"3.5k", Count{Value: 3500}, true},
+		{"", Count{}, false},
+		{"赞", Count{}, false},
+		{"1.5", Count{}, false},
+		{"-3", Count{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ParseCount(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/postfile"
+)
+
+// PublishFromFileRequest 从 Markdown 稿件发布
+type PublishFromFileRequest struct {
+	Path   string `json:"path" binding:"required"`
+	Draft  bool   `json:"draft,omitempty"`   // 只存草稿不发布
+	DryRun bool   `json:"dry_run,omitempty"` // 只解析校验，不打开浏览器
+}
+
+// PublishFromFileResponse 从 Markdown 稿件发布的结果
+type PublishFromFileResponse struct {
+	Post   *postfile.Post   `json:"post"`
+	Result *PublishResponse `json:"result,omitempty"` // dry_run 时为空
+}
+
+// PublishFromFile 解析稿件，按发布接口的规则校验后走 PublishContent
+func (s *XiaohongshuService) PublishFromFile(ctx context.Context, req *PublishFromFileRequest) (*PublishFromFileResponse, error) {
+	post, err := postfile.Load(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := post.Validate(time.Now()); err != nil {
+		return nil, fmt.Errorf("稿件校验失败: %w", err)
+	}
+
+	publishReq := &PublishRequest{
+		Title:      post.Title,
+		Content:    post.Content,
+		Images:     post.Images,
+		Tags:       post.Tags,
+		ScheduleAt: post.ScheduleAt,
+		IsOriginal: post.IsOriginal,
+		Visibility: post.Visibility,
+		Products:   post.Products,
+		Location:   post.Location,
+		Draft:      req.Draft,
+
+		Collection:       post.Collection,
+		CreateCollection: post.CreateCollection,
+		Preprocess:       post.Preprocess,
+	}
+	// 与 HTTP 发布接口同一套 binding 规则
+	if err := binding.Validator.ValidateStruct(publishReq); err != nil {
+		return nil, fmt.Errorf("稿件校验失败: %w", err)
+	}
+
+	resp := &PublishFromFileResponse{Post: post}
+	if req.DryRun {
+		return resp, nil
+	}
+
+	result, err := s.PublishContent(ctx, publishReq)
+	if err != nil {
+		return nil, err
+	}
+	resp.Result = result
+	return resp, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPublishFromFileDryRun dry_run 只解析校验，不起浏览器。
+func TestPublishFromFileDryRun(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "img"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "img", "1.jpg"), []byte("x"), 0644))
+
+	path := filepath.Join(dir, "post.md")
+	require.NoError(t, os.WriteFile(path, []byte("---\ntitle: 标题\ntags: [a]\n---\n正文\n\n![](img/1.jpg)\n"), 0644))
+
+	s := &XiaohongshuService{}
+	resp, err := s.PublishFromFile(context.Background(), &PublishFromFileRequest{Path: path, DryRun: true})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Result)
+	assert.Equal(t, "正文", resp.Post.Content)
+	assert.Equal(t, []string{filepath.Join(dir, "img", "1.jpg")}, resp.Post.Images)
+
+	require.NoError(t, os.WriteFile(path, []byte("---\ntitle: 标题\n---\n正文\n"), 0644))
+	_, err = s.PublishFromFile(context.Background(), &PublishFromFileRequest{Path: path, DryRun: true})
+	assert.Error(t, err, "没有图片")
+}
@@ -0,0 +1,218 @@
+// Package publishing 图文发布的公共流程：参数校验、发布前检查、图片下载和预处理，最后在发布页提交。
+//
+// HTTP/MCP 服务和 cmd/publish 都走这里，命令行发布与接口发布经过同样的检查。
+package publishing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/imageprep"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/lint"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhsutil"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// Request 图文发布参数
+type Request struct {
+	Title      string
+	Content    string
+	Images     []string // 本地路径或 URL
+	Tags       []string
+	ScheduleAt string // 平台定时发布时间，ISO8601，为空则立即发布
+	IsOriginal bool
+	Visibility string
+	Products   []string
+	Location   string
+
+	Collection       string
+	CreateCollection bool
+
+	Preprocess *imageprep.Options // 上传前的图片预处理，nil 则原样上传
+}
+
+// Prepared 通过检查、图片已就绪的发布内容
+type Prepared struct {
+	Content      xiaohongshu.PublishImageContent
+	Preprocessed []imageprep.Result
+	Lint         []lint.Issue // 没有拦下发布的问题
+}
+
+// InvalidError 内容本身没通过检查，原样重试不会成功
+type InvalidError struct {
+	Err error
+}
+
+func (e *InvalidError) Error() string { return e.Err.Error() }
+
+func (e *InvalidError) Unwrap() error { return e.Err }
+
+// Prepare 打开浏览器之前能做的都在这里做完：校验参数、发布前检查、下载并预处理图片
+func Prepare(req *Request, now time.Time) (*Prepared, error) {
+	if err := CheckTitle(req.Title); err != nil {
+		return nil, err
+	}
+	if err := CheckCollection(req.Collection, req.CreateCollection); err != nil {
+		return nil, err
+	}
+	scheduleTime, err := ParseScheduleAt(req.ScheduleAt, now)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := Check(req.Title, req.Content, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	imagePaths, prepared, err := PrepareImages(req.Images, req.Preprocess)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Prepared{
+		Content: xiaohongshu.PublishImageContent{
+			Title:        req.Title,
+			Content:      req.Content,
+			Tags:         req.Tags,
+			ImagePaths:   imagePaths,
+			ScheduleTime: scheduleTime,
+			IsOriginal:   req.IsOriginal,
+			Visibility:   req.Visibility,
+			Products:     req.Products,
+			Location:     req.Location,
+
+			Collection:       req.Collection,
+			CreateCollection: req.CreateCollection,
+		},
+		Preprocessed: prepared,
+		Lint:         issues,
+	}, nil
+}
+
+// Publish 在 page 上打开发布页，填写 content 并提交
+func Publish(ctx context.Context, page *rod.Page, content xiaohongshu.PublishImageContent) (*xiaohongshu.PublishResult, error) {
+	action, err := xiaohongshu.NewPublishImageAction(page)
+	if err != nil {
+		return nil, err
+	}
+	return action.Publish(ctx, content)
+}
+
+// CheckTitle 小红书标题最多 20 个字
+func CheckTitle(title string) error {
+	if xhsutil.CalcTitleLength(title) > lint.TitleMaxLength {
+		return &InvalidError{Err: fmt.Errorf("标题长度超过限制")}
+	}
+	return nil
+}
+
+// CheckCollection 只开 create_collection 没给合集名，多半是漏填了名字
+func CheckCollection(name string, create bool) error {
+	if create && strings.TrimSpace(name) == "" {
+		return &InvalidError{Err: fmt.Errorf("create_collection 需要同时指定 collection")}
+	}
+	return nil
+}
+
+// ParseScheduleAt 解析平台定时发布时间，平台只支持 1 小时至 14 天。空串返回 nil
+func ParseScheduleAt(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("定时发布时间格式错误，请使用 ISO8601 格式: %v", err)
+	}
+
+	minTime := now.Add(1 * time.Hour)
+	maxTime := now.Add(14 * 24 * time.Hour)
+	if t.Before(minTime) {
+		return nil, fmt.Errorf("定时发布时间必须至少在1小时后，当前设置: %s，最早可选: %s",
+			t.Format("2006-01-02 15:04"), minTime.Format("2006-01-02 15:04"))
+	}
+	if t.After(maxTime) {
+		return nil, fmt.Errorf("定时发布时间不能超过14天，当前设置: %s，最晚可选: %s",
+			t.Format("2006-01-02 15:04"), maxTime.Format("2006-01-02 15:04"))
+	}
+
+	logrus.Infof("设置定时发布时间: %s", t.Format("2006-01-02 15:04"))
+	return &t, nil
+}
+
+// LintConfigPath 检查配置文件，XHS_LINT_CONFIG 优先，默认数据目录下的 lint.yaml
+func LintConfigPath() string {
+	if path := os.Getenv("XHS_LINT_CONFIG"); path != "" {
+		return path
+	}
+	return filepath.Join(configs.GetDataPath(), "lint.yaml")
+}
+
+// Lint 检查标题、正文和话题，不打开浏览器。每次都重新读配置，改完词表不用重启
+func Lint(title, content string, tags []string) (*lint.Report, error) {
+	cfg, err := lint.LoadConfig(LintConfigPath())
+	if err != nil {
+		return nil, err
+	}
+	if n := xiaohongshu.ContentMaxLength(); n > 0 {
+		cfg.ContentMaxLength = n
+	}
+
+	return lint.Check(lint.Post{
+		Title:   title,
+		Content: xiaohongshu.MentionText(content),
+		Tags:    tags,
+	}, cfg), nil
+}
+
+// Check 发布前检查：有 error 级问题时拦下，其余问题返回给调用方附在结果里
+func Check(title, content string, tags []string) ([]lint.Issue, error) {
+	report, err := Lint(title, content, tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := report.Err(); err != nil {
+		return nil, &InvalidError{Err: err}
+	}
+	for _, i := range report.Issues {
+		logrus.Infof("发布前检查 [%s] %s: %s", i.Severity, i.Rule, i.Message)
+	}
+	if len(report.Issues) == 0 {
+		return nil, nil
+	}
+	return report.Issues, nil
+}
+
+// PrepareImages 下载图片，opts 非空时再做预处理，返回上传用的本地路径
+func PrepareImages(images []string, opts *imageprep.Options) ([]string, []imageprep.Result, error) {
+	if opts != nil {
+		if err := opts.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("图片预处理参数错误: %w", err)
+		}
+	}
+
+	imagePaths, err := downloader.NewImageProcessor().ProcessImages(images)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts == nil {
+		return imagePaths, nil, nil
+	}
+
+	prepared, err := imageprep.Prepare(imagePaths, filepath.Join(configs.GetImagesPath(), "prepared"), *opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("图片预处理失败: %w", err)
+	}
+	imagePaths = make([]string, len(prepared))
+	for i, r := range prepared {
+		imagePaths[i] = r.Path
+	}
+	return imagePaths, prepared, nil
+}
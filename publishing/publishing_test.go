@@ -0,0 +1,47 @@
+package publishing
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseScheduleAt 空串立即发布；平台定时只支持 1 小时至 14 天。
+func TestParseScheduleAt(t *testing.T) {
+	now := time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC)
+
+	got, err := ParseScheduleAt("", now)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = ParseScheduleAt("2024-01-21T10:00:00Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(24*time.Hour), *got)
+
+	for _, v := range []string{"明天", "2024-01-20T10:30:00Z", "2024-02-20T10:00:00Z"} {
+		_, err := ParseScheduleAt(v, now)
+		assert.Error(t, err, v)
+	}
+}
+
+// TestPrepareChecksBeforeImages 参数和发布前检查不通过时直接返回，不去下载图片。
+func TestPrepareChecksBeforeImages(t *testing.T) {
+	t.Setenv("XHS_LINT_CONFIG", filepath.Join(t.TempDir(), "lint.yaml"))
+	missing := []string{filepath.Join(t.TempDir(), "missing.jpg")}
+	now := time.Now()
+
+	cases := map[string]*Request{
+		"标题太长":  {Title: "这是一个超过二十个字的标题这是一个超过二十个字的标题", Content: "正文", Images: missing},
+		"合集名缺失": {Title: "标题", Content: "正文", Images: missing, CreateCollection: true},
+		"定时太早":  {Title: "标题", Content: "正文", Images: missing, ScheduleAt: now.Format(time.RFC3339)},
+		"检查未通过": {Title: "标题", Content: "加我 13812345678", Images: missing},
+	}
+	for name, req := range cases {
+		_, err := Prepare(req, now)
+		require.Error(t, err, name)
+		assert.NotContains(t, err.Error(), "missing.jpg", name)
+	}
+}
@@ -49,7 +49,19 @@ func setupRoutes(appServer *AppServer) *gin.Engine {
 		api.GET("/login/qrcode", appServer.getLoginQrcodeHandler)
 		api.DELETE("/login/cookies", appServer.deleteCookiesHandler)
 		api.POST("/publish", appServer.publishHandler)
+		api.POST("/publish/file", appServer.publishFromFileHandler)
 		api.POST("/publish_video", appServer.publishVideoHandler)
+		api.GET("/drafts", appServer.listDraftsHandler)
+		api.POST("/drafts/:id/publish", appServer.publishDraftHandler)
+		api.DELETE("/drafts/:id", appServer.deleteDraftHandler)
+		api.GET("/collections", appServer.listCollectionsHandler)
+		api.DELETE("/notes/:feed_id", appServer.deleteNoteHandler)
+		api.POST("/notes/:feed_id/edit", appServer.editNoteHandler)
+		api.POST("/schedule", appServer.schedulePublishHandler)
+		api.GET("/schedule", appServer.listScheduledHandler)
+		api.DELETE("/schedule/:id", appServer.cancelScheduledHandler)
+		api.POST("/schedule/:id/reschedule", appServer.rescheduleHandler)
+		api.POST("/lint", appServer.lintPostHandler)
 		api.GET("/feeds/list", appServer.listFeedsHandler)
 		api.GET("/feeds/search", appServer.searchFeedsHandler)
 		api.POST("/feeds/search", appServer.searchFeedsHandler)
@@ -57,6 +69,12 @@ func setupRoutes(appServer *AppServer) *gin.Engine {
 		api.POST("/user/profile", appServer.userProfileHandler)
 		api.POST("/feeds/comment", appServer.postCommentHandler)
 		api.POST("/feeds/comment/reply", appServer.replyCommentHandler)
+		api.POST("/feeds/comment/replies", appServer.getCommentRepliesHandler)
+		api.POST("/feeds/media/download", appServer.downloadNoteMediaHandler)
+		api.POST("/feeds/video/download", appServer.downloadVideoHandler)
+		api.POST("/feeds/video/subtitles", appServer.getVideoSubtitlesHandler)
+		api.POST("/feeds/export/markdown", appServer.exportNoteHandler)
+		api.POST("/feeds/export/comments", appServer.exportCommentsHandler)
 		api.POST("/feeds/like", appServer.likeFeedHandler)
 		api.POST("/feeds/favorite", appServer.favoriteFeedHandler)
 		api.GET("/user/me", appServer.myProfileHandler)
@@ -65,6 +83,15 @@ func setupRoutes(appServer *AppServer) *gin.Engine {
 		api.POST("/notifications/list", appServer.listNotificationsHandler)
 		api.POST("/notifications/reply", appServer.replyNotificationHandler)
 		api.POST("/notifications/like", appServer.likeNotificationHandler)
+		api.GET("/watchlist", appServer.listWatchedNotesHandler)
+		api.POST("/watchlist", appServer.watchNoteHandler)
+		api.DELETE("/watchlist/:feed_id", appServer.unwatchNoteHandler)
+		api.GET("/watchlist/:feed_id/history", appServer.noteMetricsHistoryHandler)
+		api.GET("/monitor/searches", appServer.listSavedSearchesHandler)
+		api.POST("/monitor/searches", appServer.saveSearchHandler)
+		api.DELETE("/monitor/searches/:id", appServer.deleteSavedSearchHandler)
+		api.GET("/monitor/alerts", appServer.searchAlertsHandler)
+		api.POST("/monitor/alerts", appServer.searchAlertsHandler)
 	}
 
 	return router
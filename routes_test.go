@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -156,3 +158,160 @@ func TestMCPAcceptsConfiguredBearerToken(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, recorder.Code)
 }
+
+// TestFeatureEndpointsRegistered 固定各功能的 MCP 工具和 HTTP 路由都已注册。
+//
+// 每个功能的注册都是 registerTools 和 setupRoutes 里各自独立的一段，漏掉任何一个编译都不会报错。
+func TestFeatureEndpointsRegistered(t *testing.T) {
+	router := setupRoutes(NewAppServer(NewXiaohongshuService(), ""))
+	tools := registeredToolNames(t, router)
+	routes := registeredRoutes(router)
+
+	tests := []struct {
+		name   string
+		tools  []string
+		routes []string
+	}{
+		{"评论回复", []string{"get_comment_replies"}, []string{"POST /api/v1/feeds/comment/replies"}},
+		{"媒体下载", []string{"download_note_media", "download_video", "get_video_subtitles"}, []string{
+			"POST /api/v1/feeds/media/download",
+			"POST /api/v1/feeds/video/download",
+			"POST /api/v1/feeds/video/subtitles",
+		}},
+		{"导出", []string{"export_note", "export_comments"}, []string{
+			"POST /api/v1/feeds/export/markdown",
+			"POST /api/v1/feeds/export/comments",
+		}},
+		{"关注列表", []string{"watch_note", "unwatch_note", "list_watched_notes", "get_note_metrics_history"}, []string{
+			"GET /api/v1/watchlist",
+			"POST /api/v1/watchlist",
+			"DELETE /api/v1/watchlist/:feed_id",
+			"GET /api/v1/watchlist/:feed_id/history",
+		}},
+		{"搜索监控", []string{"save_search", "delete_saved_search", "list_saved_searches", "get_search_alerts"}, []string{
+			"GET /api/v1/monitor/searches",
+			"POST /api/v1/monitor/searches",
+			"DELETE /api/v1/monitor/searches/:id",
+			"GET /api/v1/monitor/alerts",
+			"POST /api/v1/monitor/alerts",
+		}},
+		{"草稿", []string{"list_drafts", "publish_draft", "delete_draft"}, []string{
+			"GET /api/v1/drafts",
+			"POST /api/v1/drafts/:id/publish",
+			"DELETE /api/v1/drafts/:id",
+		}},
+		{"从稿件发布", []string{"publish_from_file"}, []string{"POST /api/v1/publish/file"}},
+		{"合集", []string{"list_collections"}, []string{"GET /api/v1/collections"}},
+		{"笔记管理", []string{"delete_note", "edit_note"}, []string{
+			"DELETE /api/v1/notes/:feed_id",
+			"POST /api/v1/notes/:feed_id/edit",
+		}},
+		{"本地定时发布", []string{"schedule_publish", "list_scheduled_publishes", "cancel_scheduled_publish", "reschedule_publish"}, []string{
+			"POST /api/v1/schedule",
+			"GET /api/v1/schedule",
+			"DELETE /api/v1/schedule/:id",
+			"POST /api/v1/schedule/:id/reschedule",
+		}},
+		{"发布前检查", []string{"lint_post"}, []string{"POST /api/v1/lint"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range tt.tools {
+				assert.True(t, tools[name], "工具 %s 应已注册", name)
+			}
+			for _, r := range tt.routes {
+				assert.True(t, routes[r], "路由 %s 应已注册", r)
+			}
+		})
+	}
+}
+
+// TestDeleteNoteRequiresConfirm 没有 confirm=true 时直接拒绝，不启动浏览器。
+func TestDeleteNoteRequiresConfirm(t *testing.T) {
+	router := setupRoutes(NewAppServer(NewXiaohongshuService(), ""))
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/notes/66a1b2c3d4e5f6a7b8c9d0e1", nil))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "confirm=true")
+}
+
+// TestScheduleRejectsPlatformSchedule 本地定时任务不接受平台的 schedule_at，在下载图片之前拒绝。
+func TestScheduleRejectsPlatformSchedule(t *testing.T) {
+	t.Setenv("XHS_DATA_DIR", t.TempDir())
+	router := setupRoutes(NewAppServer(NewXiaohongshuService(), ""))
+
+	body := `{"title":"周末去哪","content":"正文","images":["/nonexistent.jpg"],` +
+		`"publish_at":"2099-01-01T09:00:00+08:00","schedule_at":"2099-01-01T09:00:00+08:00"}`
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/schedule", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "不支持 schedule_at")
+}
+
+// TestLintPostReportsIssues lint 接口只报告问题，不拦截，也不启动浏览器。
+func TestLintPostReportsIssues(t *testing.T) {
+	t.Setenv("XHS_LINT_CONFIG", filepath.Join(t.TempDir(), "lint.yaml"))
+	router := setupRoutes(NewAppServer(NewXiaohongshuService(), ""))
+
+	body := `{"title":"新品上市","content":"咨询电话 13812345678"}`
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/lint", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"rule":"phone_number"`)
+}
+
+// TestPublishRejectedByLint 有 error 级问题时在打开浏览器之前拒绝。
+func TestPublishRejectedByLint(t *testing.T) {
+	t.Setenv("XHS_LINT_CONFIG", filepath.Join(t.TempDir(), "lint.yaml"))
+	router := setupRoutes(NewAppServer(NewXiaohongshuService(), ""))
+
+	body := `{"title":"新品上市","content":"咨询电话 13812345678","images":["/nonexistent.jpg"]}`
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/publish", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "发布前检查未通过")
+	assert.Contains(t, recorder.Body.String(), "13812345678")
+}
+
+// registeredToolNames 通过 tools/list 取已注册的工具名。
+func registeredToolNames(t *testing.T, router http.Handler) map[string]bool {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/mcp",
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json, text/event-stream")
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var result struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	names := make(map[string]bool, len(result.Result.Tools))
+	for _, tool := range result.Result.Tools {
+		names[tool.Name] = true
+	}
+	return names
+}
+
+// registeredRoutes 读路由表，返回 "METHOD path" 集合。
+func registeredRoutes(router *gin.Engine) map[string]bool {
+	registered := make(map[string]bool)
+	for _, r := range router.Routes() {
+		registered[r.Method+" "+r.Path] = true
+	}
+	return registered
+}
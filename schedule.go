@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/lint"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/schedule"
+	"github.com/xpzouying/xiaohongshu-mcp/publishing"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// 检查到期任务的间隔，XHS_SCHEDULE_INTERVAL 可调。只读本地文件，可以查得勤，发布时间误差在一个间隔内
+const (
+	defaultScheduleInterval = 30 * time.Second
+	minScheduleInterval     = 10 * time.Second
+)
+
+// SchedulePublishRequest 本地定时发布请求，不受平台 1 小时至 14 天的限制
+type SchedulePublishRequest struct {
+	PublishRequest
+	PublishAt string `json:"publish_at" binding:"required"` // 发布时间，ISO8601 格式
+}
+
+// SchedulePublishResponse 安排好的任务，附带发布前检查发现但没有拦下的问题
+type SchedulePublishResponse struct {
+	schedule.Job
+	Lint []lint.Issue `json:"lint,omitempty"`
+}
+
+// RescheduleRequest 修改发布时间
+type RescheduleRequest struct {
+	PublishAt string `json:"publish_at" binding:"required"`
+}
+
+// SchedulePublish 下载并复制图片到任务目录，保存任务，到点由后台任务发布。
+// 标题、合集参数、发布前检查和图片在这里校验；地点、商品等只有页面能校验的内容到发布时才知道
+func (s *XiaohongshuService) SchedulePublish(ctx context.Context, req *SchedulePublishRequest) (*SchedulePublishResponse, error) {
+	publishAt, err := parsePublishAt(req.PublishAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if req.ScheduleAt != "" || req.Draft {
+		return nil, fmt.Errorf("定时任务不支持 schedule_at 和 draft，用 publish_at 指定发布时间")
+	}
+	if err := publishing.CheckTitle(req.Title); err != nil {
+		return nil, err
+	}
+	if err := publishing.CheckCollection(req.Collection, req.CreateCollection); err != nil {
+		return nil, err
+	}
+	lintIssues, err := publishing.Check(req.Title, req.Content, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	imagePaths, _, err := publishing.PrepareImages(req.Images, req.Preprocess)
+	if err != nil {
+		return nil, err
+	}
+
+	id := schedule.NewID()
+	dir := s.schedule.AssetDir(id)
+	images, err := copyImages(dir, imagePaths)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	job, err := s.schedule.Add(schedule.Job{
+		ID: id,
+		Post: schedule.Post{
+			Title:      req.Title,
+			Content:    req.Content,
+			Images:     images,
+			Tags:       req.Tags,
+			IsOriginal: req.IsOriginal,
+			Visibility: req.Visibility,
+			Products:   req.Products,
+			Location:   req.Location,
+
+			Collection:       req.Collection,
+			CreateCollection: req.CreateCollection,
+		},
+		PublishAt: publishAt,
+	})
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	logrus.Infof("已安排定时发布 %s: title=%s 时间=%s", id, req.Title, publishAt.Format("2006-01-02 15:04"))
+	return &SchedulePublishResponse{Job: job, Lint: lintIssues}, nil
+}
+
+// ListScheduled 全部定时任务，按发布时间先后排列
+func (s *XiaohongshuService) ListScheduled() ([]schedule.Job, error) {
+	return s.schedule.List()
+}
+
+// CancelScheduled 取消未发出的任务
+func (s *XiaohongshuService) CancelScheduled(id string) (*schedule.Job, error) {
+	job, err := s.schedule.Cancel(id)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Reschedule 改发布时间；重试用完或发布中断的任务也用它重新安排
+func (s *XiaohongshuService) Reschedule(id, publishAt string) (*schedule.Job, error) {
+	at, err := parsePublishAt(publishAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	job, err := s.schedule.Reschedule(id, at)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// parsePublishAt 解析发布时间，必须晚于当前时间，不限上限
+func parsePublishAt(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("缺少发布时间 publish_at")
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("发布时间格式错误，请使用 ISO8601 格式: %v", err)
+	}
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("发布时间必须晚于当前时间，当前设置: %s", t.Format("2006-01-02 15:04"))
+	}
+	return t, nil
+}
+
+// recoverScheduled 服务启动时把上次中断的发布标为失败，避免重复发布
+func (s *XiaohongshuService) recoverScheduled() {
+	n, err := s.schedule.RecoverInterrupted()
+	if err != nil {
+		logrus.Errorf("检查中断的定时任务失败: %v", err)
+		return
+	}
+	if n > 0 {
+		logrus.Warnf("有 %d 个定时任务在上次发布途中中断，已标为失败，请检查后重新安排", n)
+	}
+}
+
+// runScheduledPublishes 依次发布所有到期任务。多个任务同时到期时按时间先后一个一个发
+func (s *XiaohongshuService) runScheduledPublishes(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, ok, err := s.schedule.Claim(time.Now())
+		if err != nil {
+			logrus.Errorf("读取定时任务失败: %v", err)
+			return
+		}
+		if !ok {
+			return
+		}
+		s.runScheduledPublish(ctx, job)
+	}
+}
+
+func (s *XiaohongshuService) runScheduledPublish(ctx context.Context, job schedule.Job) {
+	if late := time.Since(job.PublishAt); job.Attempts == 1 && late > time.Hour {
+		logrus.Warnf("定时任务 %s 晚了 %s 才发布（服务未运行）", job.ID, late.Round(time.Minute))
+	}
+	logrus.Infof("开始发布定时任务 %s: title=%s 第 %d 次", job.ID, job.Post.Title, job.Attempts)
+
+	// 上次点了发布却没确认成功，先看笔记是不是已经发出去了，没发出去才重发
+	if job.Unconfirmed {
+		note, err := s.findPublishedNote(job.Post.Title, job.KnownNotes)
+		if err != nil {
+			// 核对不了就不发，下次再核对
+			err = fmt.Errorf("上次发布未确认成功，核对笔记列表失败: %w", err)
+			s.failScheduled(job, err, func() (schedule.Job, error) {
+				return s.schedule.FailUnconfirmed(job.ID, err, job.KnownNotes, time.Now())
+			})
+			return
+		}
+		if note != nil {
+			logrus.Infof("定时任务 %s 上次发布其实已成功", job.ID)
+			s.finishScheduled(job, note.NoteID, note.URL)
+			return
+		}
+		logrus.Infof("定时任务 %s 上次发布没有发出去，重新发布", job.ID)
+	}
+
+	p := job.Post
+	resp, err := s.PublishContent(ctx, &PublishRequest{
+		Title:      p.Title,
+		Content:    p.Content,
+		Images:     p.Images,
+		Tags:       p.Tags,
+		IsOriginal: p.IsOriginal,
+		Visibility: p.Visibility,
+		Products:   p.Products,
+		Location:   p.Location,
+
+		Collection:       p.Collection,
+		CreateCollection: p.CreateCollection,
+	})
+	if err != nil {
+		var unconfirmed *xiaohongshu.UnconfirmedError
+		switch {
+		case errors.As(err, &unconfirmed) && unconfirmed.KnownNotes == nil:
+			s.failScheduled(job, err, func() (schedule.Job, error) {
+				return s.schedule.FailUnverifiable(job.ID, err)
+			})
+		case errors.As(err, &unconfirmed):
+			s.failScheduled(job, err, func() (schedule.Job, error) {
+				return s.schedule.FailUnconfirmed(job.ID, err, unconfirmed.KnownNotes, time.Now())
+			})
+		case !retryable(err):
+			s.failScheduled(job, err, func() (schedule.Job, error) {
+				return s.schedule.FailPermanent(job.ID, err)
+			})
+		default:
+			s.failScheduled(job, err, func() (schedule.Job, error) {
+				return s.schedule.Fail(job.ID, err, time.Now())
+			})
+		}
+		return
+	}
+
+	s.finishScheduled(job, resp.NoteID, resp.URL)
+}
+
+// retryable 只有浏览器、网络这类偶发问题值得重试；内容没通过检查、地点或合集对不上、超长，重试多少次都一样
+func retryable(err error) bool {
+	var (
+		invalid    *publishing.InvalidError
+		location   *xiaohongshu.LocationError
+		collection *xiaohongshu.CollectionError
+		length     *xiaohongshu.LengthError
+	)
+	return !errors.As(err, &invalid) && !errors.As(err, &location) &&
+		!errors.As(err, &collection) && !errors.As(err, &length)
+}
+
+// failScheduled 用 record 记下失败结果并打日志
+func (s *XiaohongshuService) failScheduled(job schedule.Job, err error, record func() (schedule.Job, error)) {
+	failed, ferr := record()
+	if ferr != nil {
+		logrus.Errorf("记录定时任务 %s 失败结果出错: %v", job.ID, ferr)
+		return
+	}
+	if failed.Status == schedule.StatusFailed {
+		logrus.Errorf("定时任务 %s 发布失败，第 %d 次，不再重试: %v", job.ID, failed.Attempts, err)
+	} else {
+		logrus.Warnf("定时任务 %s 发布失败，%s 重试: %v", job.ID, failed.NextAttempt.Format("15:04"), err)
+	}
+}
+
+// finishScheduled 已经发出去了，记录失败只记日志，任务停在 running，重启后会标为失败等人确认，不会重发
+func (s *XiaohongshuService) finishScheduled(job schedule.Job, noteID, url string) {
+	if err := s.schedule.Finish(job.ID, noteID, url); err != nil {
+		logrus.Errorf("定时任务 %s 已发布，但记录结果失败: %v", job.ID, err)
+		return
+	}
+	logrus.Infof("定时任务 %s 已发布: note_id=%s", job.ID, noteID)
+}
+
+// findPublishedNote 打开浏览器，在笔记列表里找发布前不在 known 里、标题为 title 的笔记
+func (s *XiaohongshuService) findPublishedNote(title string, known []string) (*xiaohongshu.PublishedNote, error) {
+	b := newBrowser()
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	return xiaohongshu.FindPublishedNote(page, title, known)
+}
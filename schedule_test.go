@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParsePublishAt 只要求晚于当前时间，远超平台 14 天上限也可以。
+func TestParsePublishAt(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	at, err := parsePublishAt("2025-09-01T20:00:00+08:00", now)
+	require.NoError(t, err)
+	assert.Equal(t, 12, at.UTC().Hour())
+
+	_, err = parsePublishAt("2025-01-01T12:00:00Z", now)
+	assert.Error(t, err, "不能是当前或过去的时间")
+
+	_, err = parsePublishAt("2025-09-01 20:00", now)
+	assert.Error(t, err)
+
+	_, err = parsePublishAt("", now)
+	assert.Error(t, err)
+}
@@ -3,8 +3,11 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
@@ -13,19 +16,39 @@ import (
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
 	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/cache"
 	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
-	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhsutil"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/drafts"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/imageprep"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/lint"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/monitor"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/schedule"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/videoprobe"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/watchlist"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhscdn"
+	"github.com/xpzouying/xiaohongshu-mcp/publishing"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 // XiaohongshuService 小红书业务服务
 type XiaohongshuService struct {
-	logins loginSessions
+	logins    loginSessions
+	cache     *cache.Store
+	watchlist *watchlist.Store
+	monitor   *monitor.Store
+	drafts    *drafts.Store
+	schedule  *schedule.Store
 }
 
 // NewXiaohongshuService 创建小红书服务实例
 func NewXiaohongshuService() *XiaohongshuService {
-	return &XiaohongshuService{}
+	return &XiaohongshuService{
+		cache:     cache.NewStore(configs.GetCachePath()),
+		watchlist: watchlist.NewStore(filepath.Join(configs.GetDataPath(), "watchlist")),
+		monitor:   monitor.NewStore(filepath.Join(configs.GetDataPath(), "monitor")),
+		drafts:    drafts.NewStore(filepath.Join(configs.GetDataPath(), "drafts")),
+		schedule:  schedule.NewStore(filepath.Join(configs.GetDataPath(), "schedule")),
+	}
 }
 
 // PublishRequest 发布请求
@@ -38,6 +61,14 @@ type PublishRequest struct {
 	IsOriginal bool     `json:"is_original,omitempty"` // 是否声明原创
 	Visibility string   `json:"visibility,omitempty"`  // 可见范围: "公开可见"(默认), "仅自己可见", "仅互关好友可见"
 	Products   []string `json:"products,omitempty"`    // 商品关键词列表，用于绑定带货商品
+	Location   string   `json:"location,omitempty"`    // 地点，搜索后选中最匹配的一项，没有可信匹配则报错
+	Draft      bool     `json:"draft,omitempty"`       // 只存草稿不发布，之后用草稿 ID 发布
+
+	Collection       string `json:"collection,omitempty"`        // 加入的合集名称，需已存在，除非 create_collection
+	CreateCollection bool   `json:"create_collection,omitempty"` // 合集不存在时新建
+
+	// Preprocess 上传前的图片预处理（转 JPEG、去元数据、缩小、统一比例），不填则原样上传
+	Preprocess *imageprep.Options `json:"preprocess,omitempty"`
 }
 
 // LoginStatusResponse 登录状态响应
@@ -60,17 +91,34 @@ type PublishResponse struct {
 	Content string `json:"content"`
 	Images  int    `json:"images"`
 	Status  string `json:"status"`
+	DraftID string `json:"draft_id,omitempty"`
+
+	// 发布后从创作中心笔记列表找回的笔记，没找到（或存草稿）时为空
+	NoteID    string `json:"note_id,omitempty"`
+	XsecToken string `json:"xsec_token,omitempty"`
+	URL       string `json:"url,omitempty"`
+
+	FailedMentions []string `json:"failed_mentions,omitempty"` // 未能选中的 @ 用户，已按普通文本发出
+
+	Preprocessed []imageprep.Result `json:"preprocessed,omitempty"` // 开启预处理时每张图的处理结果
+	Lint         []lint.Issue       `json:"lint,omitempty"`         // 发布前检查发现但没有拦下的问题
 }
 
 // PublishVideoRequest 发布视频请求（仅支持本地单个视频文件）
 type PublishVideoRequest struct {
 	Title      string   `json:"title" binding:"required"`
 	Content    string   `json:"content" binding:"required"`
-	Video      string   `json:"video" binding:"required"`
+	Video      string   `json:"video" binding:"required"` // 本地视频路径或 http(s) URL
 	Tags       []string `json:"tags,omitempty"`
 	ScheduleAt string   `json:"schedule_at,omitempty"` // 定时发布时间，ISO8601格式，为空则立即发布
 	Visibility string   `json:"visibility,omitempty"`  // 可见范围: "公开可见"(默认), "仅自己可见", "仅互关好友可见"
 	Products   []string `json:"products,omitempty"`    // 商品关键词列表，用于绑定带货商品
+	Location   string   `json:"location,omitempty"`    // 地点，搜索后选中最匹配的一项，没有可信匹配则报错
+	Cover      string   `json:"cover,omitempty"`       // 自定义封面，本地路径或图片URL
+	CoverTime  *float64 `json:"cover_time,omitempty"`  // 以视频第几秒的画面作为封面，与 cover 二选一
+
+	Collection       string `json:"collection,omitempty"`        // 加入的合集名称，需已存在，除非 create_collection
+	CreateCollection bool   `json:"create_collection,omitempty"` // 合集不存在时新建
 }
 
 // PublishVideoResponse 发布视频响应
@@ -79,12 +127,23 @@ type PublishVideoResponse struct {
 	Content string `json:"content"`
 	Video   string `json:"video"`
 	Status  string `json:"status"`
+
+	// 发布后从创作中心笔记列表找回的笔记，没找到（或存草稿）时为空
+	NoteID    string `json:"note_id,omitempty"`
+	XsecToken string `json:"xsec_token,omitempty"`
+	URL       string `json:"url,omitempty"`
+
+	FailedMentions []string                `json:"failed_mentions,omitempty"` // 未能选中的 @ 用户，已按普通文本发出
+	Probe          *videoprobe.Info        `json:"probe,omitempty"`           // 上传前的视频探测结果
+	Remote         *downloader.RemoteVideo `json:"remote,omitempty"`          // video 为 URL 时的下载信息，本地文件发布后已删除
+	Lint           []lint.Issue            `json:"lint,omitempty"`            // 发布前检查发现但没有拦下的问题
 }
 
 // FeedsListResponse Feeds列表响应
 type FeedsListResponse struct {
 	Feeds []xiaohongshu.Feed `json:"feeds"`
 	Count int                `json:"count"`
+	Cache *CacheInfo         `json:"cache,omitempty"` // 列表首页不走缓存，为空
 }
 
 // UserProfileResponse 用户主页响应
@@ -92,6 +151,7 @@ type UserProfileResponse struct {
 	UserBasicInfo xiaohongshu.UserBasicInfo      `json:"userBasicInfo"`
 	Interactions  []xiaohongshu.UserInteractions `json:"interactions"`
 	Feeds         []xiaohongshu.Feed             `json:"feeds"`
+	Cache         *CacheInfo                     `json:"cache,omitempty"` // 我的主页不走缓存，为空
 }
 
 // DeleteCookies 删除 cookies 文件，用于登录重置
@@ -203,154 +263,217 @@ func (s *XiaohongshuService) waitScanInBackground(
 
 // PublishContent 发布内容
 func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
-	// 验证标题长度（小红书限制：最大20个字）
-	if xhsutil.CalcTitleLength(req.Title) > 20 {
-		return nil, fmt.Errorf("标题长度超过限制")
+	if req.Draft && req.ScheduleAt != "" {
+		return nil, fmt.Errorf("草稿不保存定时设置，请在发布草稿时指定 schedule_at")
 	}
 
-	imagePaths, err := s.processImages(req.Images)
+	p, err := publishing.Prepare(req.publishing(), time.Now())
 	if err != nil {
 		return nil, err
 	}
+	imagePaths, prepared, lintIssues := p.Content.ImagePaths, p.Preprocessed, p.Lint
 
-	var scheduleTime *time.Time
-	if req.ScheduleAt != "" {
-		t, err := time.Parse(time.RFC3339, req.ScheduleAt)
+	if req.Draft {
+		resp, err := s.saveDraft(req, imagePaths)
 		if err != nil {
-			return nil, fmt.Errorf("定时发布时间格式错误，请使用 ISO8601 格式: %v", err)
+			return nil, err
 		}
-
-		// 校验定时发布时间范围：1小时至14天
-		now := time.Now()
-		minTime := now.Add(1 * time.Hour)
-		maxTime := now.Add(14 * 24 * time.Hour)
-
-		if t.Before(minTime) {
-			return nil, fmt.Errorf("定时发布时间必须至少在1小时后，当前设置: %s，最早可选: %s",
-				t.Format("2006-01-02 15:04"), minTime.Format("2006-01-02 15:04"))
-		}
-		if t.After(maxTime) {
-			return nil, fmt.Errorf("定时发布时间不能超过14天，当前设置: %s，最晚可选: %s",
-				t.Format("2006-01-02 15:04"), maxTime.Format("2006-01-02 15:04"))
-		}
-
-		scheduleTime = &t
-		logrus.Infof("设置定时发布时间: %s", t.Format("2006-01-02 15:04"))
-	}
-
-	content := xiaohongshu.PublishImageContent{
-		Title:        req.Title,
-		Content:      req.Content,
-		Tags:         req.Tags,
-		ImagePaths:   imagePaths,
-		ScheduleTime: scheduleTime,
-		IsOriginal:   req.IsOriginal,
-		Visibility:   req.Visibility,
-		Products:     req.Products,
+		resp.Preprocessed = prepared
+		resp.Lint = lintIssues
+		return resp, nil
 	}
 
-	if err := s.publishContent(ctx, content); err != nil {
-		logrus.Errorf("发布内容失败: title=%s %v", content.Title, err)
+	result, err := s.publishContent(ctx, p.Content)
+	if err != nil {
+		logrus.Errorf("发布内容失败: title=%s %v", req.Title, err)
 		return nil, err
 	}
 
 	response := &PublishResponse{
-		Title:   req.Title,
-		Content: req.Content,
-		Images:  len(imagePaths),
-		Status:  "发布完成",
+		Title:          req.Title,
+		Content:        req.Content,
+		Images:         len(imagePaths),
+		Status:         "发布完成",
+		FailedMentions: result.FailedMentions,
+		Preprocessed:   prepared,
+		Lint:           lintIssues,
+	}
+	if note := result.Note; note != nil {
+		response.NoteID, response.XsecToken, response.URL = note.NoteID, note.XsecToken, note.URL
 	}
 
 	return response, nil
 }
 
+// videoCover 把请求里的封面参数转成浏览器侧的封面设置，URL 封面先下载到本地
+func (s *XiaohongshuService) videoCover(req *PublishVideoRequest) (xiaohongshu.VideoCover, error) {
+	var cover xiaohongshu.VideoCover
+	if req.Cover != "" && req.CoverTime != nil {
+		return cover, fmt.Errorf("cover 和 cover_time 只能二选一")
+	}
+
+	if req.CoverTime != nil {
+		if *req.CoverTime < 0 {
+			return cover, fmt.Errorf("cover_time 不能为负数")
+		}
+		at := time.Duration(*req.CoverTime * float64(time.Second))
+		cover.Time = &at
+	}
+
+	if req.Cover != "" {
+		paths, err := s.processImages([]string{req.Cover})
+		if err != nil {
+			return cover, fmt.Errorf("处理封面图片失败: %w", err)
+		}
+		if len(paths) == 0 {
+			return cover, fmt.Errorf("封面图片无效")
+		}
+		cover.Image = paths[0]
+	}
+	return cover, nil
+}
+
 // processImages 处理图片列表，支持URL下载和本地路径
 func (s *XiaohongshuService) processImages(images []string) ([]string, error) {
 	processor := downloader.NewImageProcessor()
 	return processor.ProcessImages(images)
 }
 
+// publishing 转成公共发布流程的参数
+func (r *PublishRequest) publishing() *publishing.Request {
+	return &publishing.Request{
+		Title:      r.Title,
+		Content:    r.Content,
+		Images:     r.Images,
+		Tags:       r.Tags,
+		ScheduleAt: r.ScheduleAt,
+		IsOriginal: r.IsOriginal,
+		Visibility: r.Visibility,
+		Products:   r.Products,
+		Location:   r.Location,
+
+		Collection:       r.Collection,
+		CreateCollection: r.CreateCollection,
+		Preprocess:       r.Preprocess,
+	}
+}
+
 // publishContent 执行内容发布
-func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) error {
+func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) (*xiaohongshu.PublishResult, error) {
 	b := newBrowser()
 	defer b.Close()
 
 	page := b.NewPage()
 	defer page.Close()
 
-	action, err := xiaohongshu.NewPublishImageAction(page)
-	if err != nil {
-		return err
-	}
-
-	return action.Publish(ctx, content)
+	return publishing.Publish(ctx, page, content)
 }
 
 // PublishVideo 发布视频（本地文件）
 func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideoRequest) (*PublishVideoResponse, error) {
-	// 标题长度校验（小红书限制：最大20个字）
-	if xhsutil.CalcTitleLength(req.Title) > 20 {
-		return nil, fmt.Errorf("标题长度超过限制")
+	if err := publishing.CheckTitle(req.Title); err != nil {
+		return nil, err
 	}
 
-	// 本地视频文件校验
 	if req.Video == "" {
-		return nil, fmt.Errorf("必须提供本地视频文件")
+		return nil, fmt.Errorf("必须提供视频文件路径或URL")
 	}
-	if _, err := os.Stat(req.Video); err != nil {
-		return nil, fmt.Errorf("视频文件不存在或不可访问: %v", err)
+
+	if err := publishing.CheckCollection(req.Collection, req.CreateCollection); err != nil {
+		return nil, err
+	}
+	scheduleTime, err := publishing.ParseScheduleAt(req.ScheduleAt, time.Now())
+	if err != nil {
+		return nil, err
 	}
 
-	var scheduleTime *time.Time
-	if req.ScheduleAt != "" {
-		t, err := time.Parse(time.RFC3339, req.ScheduleAt)
+	lintIssues, err := publishing.Check(req.Title, req.Content, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// 远程视频先下载到本地，发布结束后删除；下载中断的 .part 保留，重试时续传
+	videoPath := req.Video
+	var remote *downloader.RemoteVideo
+	if downloader.IsRemoteURL(req.Video) {
+		var err error
+		remote, err = downloader.NewVideoFetcher(configs.GetVideosPath(), videoprobe.PlatformLimits.MaxSize).Fetch(ctx, req.Video)
 		if err != nil {
-			return nil, fmt.Errorf("定时发布时间格式错误，请使用 ISO8601 格式: %v", err)
+			return nil, fmt.Errorf("下载视频失败: %w", err)
 		}
+		videoPath = remote.Path
+		defer os.Remove(videoPath)
+		logrus.Infof("远程视频已下载: %s size=%d resumed=%v", videoPath, remote.Size, remote.Resumed)
+	}
 
-		// 校验定时发布时间范围：1小时至14天
-		now := time.Now()
-		minTime := now.Add(1 * time.Hour)
-		maxTime := now.Add(14 * 24 * time.Hour)
+	// 本地视频文件校验
+	if _, err := os.Stat(videoPath); err != nil {
+		return nil, fmt.Errorf("视频文件不存在或不可访问: %v", err)
+	}
 
-		if t.Before(minTime) {
-			return nil, fmt.Errorf("定时发布时间必须至少在1小时后，当前设置: %s，最早可选: %s",
-				t.Format("2006-01-02 15:04"), minTime.Format("2006-01-02 15:04"))
+	// 启动浏览器前先探测视频，不合规的文件直接失败，不用等上传处理超时
+	// 只认得 MP4/MOV，其他容器（WebM、MKV 等）跳过探测，交给平台判断
+	probe, err := videoprobe.Probe(videoPath)
+	switch {
+	case errors.Is(err, videoprobe.ErrNotMP4):
+		logrus.Warnf("视频不是 MP4/MOV，跳过上传前探测: %s", videoPath)
+		probe = nil
+	case err != nil:
+		return nil, fmt.Errorf("无法解析视频文件: %v", err)
+	}
+	if probe != nil {
+		if err := videoprobe.PlatformLimits.Check(probe); err != nil {
+			return nil, err
 		}
-		if t.After(maxTime) {
-			return nil, fmt.Errorf("定时发布时间不能超过14天，当前设置: %s，最晚可选: %s",
-				t.Format("2006-01-02 15:04"), maxTime.Format("2006-01-02 15:04"))
+		if req.CoverTime != nil && probe.Duration > 0 && *req.CoverTime > probe.Seconds {
+			return nil, fmt.Errorf("cover_time %.2f 秒超出视频时长 %.2f 秒", *req.CoverTime, probe.Seconds)
 		}
+		logrus.Infof("视频探测: %s %dx%d %s 时长 %.1fs", probe.Container, probe.Width, probe.Height, probe.CodecName(), probe.Seconds)
+	}
 
-		scheduleTime = &t
-		logrus.Infof("设置定时发布时间: %s", t.Format("2006-01-02 15:04"))
+	cover, err := s.videoCover(req)
+	if err != nil {
+		return nil, err
 	}
 
 	content := xiaohongshu.PublishVideoContent{
 		Title:        req.Title,
 		Content:      req.Content,
 		Tags:         req.Tags,
-		VideoPath:    req.Video,
+		VideoPath:    videoPath,
 		ScheduleTime: scheduleTime,
 		Visibility:   req.Visibility,
 		Products:     req.Products,
+		Location:     req.Location,
+		Cover:        cover,
+
+		Collection:       req.Collection,
+		CreateCollection: req.CreateCollection,
 	}
 
-	if err := s.publishVideo(ctx, content); err != nil {
+	result, err := s.publishVideo(ctx, content)
+	if err != nil {
 		return nil, err
 	}
 
 	resp := &PublishVideoResponse{
-		Title:   req.Title,
-		Content: req.Content,
-		Video:   req.Video,
-		Status:  "发布完成",
+		Title:          req.Title,
+		Content:        req.Content,
+		Video:          req.Video,
+		Status:         "发布完成",
+		FailedMentions: result.FailedMentions,
+		Probe:          probe,
+		Remote:         remote,
+		Lint:           lintIssues,
+	}
+	if note := result.Note; note != nil {
+		resp.NoteID, resp.XsecToken, resp.URL = note.NoteID, note.XsecToken, note.URL
 	}
 	return resp, nil
 }
 
 // publishVideo 执行视频发布
-func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) error {
+func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) (*xiaohongshu.PublishResult, error) {
 	b := newBrowser()
 	defer b.Close()
 
@@ -359,14 +482,14 @@ func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongs
 
 	action, err := xiaohongshu.NewPublishVideoAction(page)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	return action.PublishVideo(ctx, content)
 }
 
-// ListFeeds 获取Feeds列表
-func (s *XiaohongshuService) ListFeeds(ctx context.Context) (*FeedsListResponse, error) {
+// ListFeeds 获取Feeds列表，images 决定封面地址的尺寸和格式
+func (s *XiaohongshuService) ListFeeds(ctx context.Context, images xhscdn.Options) (*FeedsListResponse, error) {
 	b := newBrowser()
 	defer b.Close()
 
@@ -380,6 +503,7 @@ func (s *XiaohongshuService) ListFeeds(ctx context.Context) (*FeedsListResponse,
 		logrus.Errorf("获取 Feeds 列表失败: %v", err)
 		return nil, err
 	}
+	xiaohongshu.FillFeedsCDNURLs(feeds, images)
 
 	response := &FeedsListResponse{
 		Feeds: feeds,
@@ -389,79 +513,120 @@ func (s *XiaohongshuService) ListFeeds(ctx context.Context) (*FeedsListResponse,
 	return response, nil
 }
 
-func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string, filters ...xiaohongshu.FilterOption) (*FeedsListResponse, error) {
-	b := newBrowser()
-	defer b.Close()
+// SearchFeeds 搜索笔记，mode 决定是否使用缓存，images 决定封面地址的尺寸和格式
+func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string, mode cache.Mode, images xhscdn.Options, filters ...xiaohongshu.FilterOption) (*FeedsListResponse, error) {
+	filterKey, _ := json.Marshal(filters)
+	key := keyword + "|" + string(filterKey)
 
-	page := b.NewPage()
-	defer page.Close()
+	feeds, info, err := withCache(s.cache, mode, cacheKindSearch, key, func() (*[]xiaohongshu.Feed, error) {
+		b := newBrowser()
+		defer b.Close()
 
-	action := xiaohongshu.NewSearchAction(page)
+		page := b.NewPage()
+		defer page.Close()
 
-	feeds, err := action.Search(ctx, keyword, filters...)
+		action := xiaohongshu.NewSearchAction(page)
+
+		feeds, err := action.Search(ctx, keyword, filters...)
+		if err != nil {
+			return nil, err
+		}
+		return &feeds, nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	// 地址按本次请求的参数拼，不进缓存的 key
+	xiaohongshu.FillFeedsCDNURLs(*feeds, images)
 
 	response := &FeedsListResponse{
-		Feeds: feeds,
-		Count: len(feeds),
+		Feeds: *feeds,
+		Count: len(*feeds),
+		Cache: info,
 	}
 
 	return response, nil
 }
 
-// GetFeedDetail 获取Feed详情
-func (s *XiaohongshuService) GetFeedDetail(ctx context.Context, feedID, xsecToken string, loadAllComments bool) (*FeedDetailResponse, error) {
-	return s.GetFeedDetailWithConfig(ctx, feedID, xsecToken, loadAllComments, xiaohongshu.DefaultCommentLoadConfig())
+// parseImageOptions 解析调用方给的图片尺寸和格式，都不填为原图
+func parseImageOptions(format string, width int) (xhscdn.Options, error) {
+	f, err := xhscdn.ParseFormat(format)
+	if err != nil {
+		return xhscdn.Options{}, err
+	}
+	opts := xhscdn.Options{Width: width, Format: f}
+	return opts, opts.Validate()
 }
 
-// GetFeedDetailWithConfig 使用配置获取Feed详情
-func (s *XiaohongshuService) GetFeedDetailWithConfig(ctx context.Context, feedID, xsecToken string, loadAllComments bool, config xiaohongshu.CommentLoadConfig) (*FeedDetailResponse, error) {
-	b := newBrowser()
-	defer b.Close()
+// GetFeedDetailWithConfig 使用配置获取Feed详情，images 决定补全的首帧、缩略图地址的尺寸和格式，mode 决定是否使用缓存
+func (s *XiaohongshuService) GetFeedDetailWithConfig(ctx context.Context, feedID, xsecToken string, loadAllComments bool, config xiaohongshu.CommentLoadConfig, images xhscdn.Options, mode cache.Mode) (*FeedDetailResponse, error) {
+	// 评论加载方式不同，拿到的评论也不同，要分开缓存
+	key := fmt.Sprintf("%s|%v|%+v", feedID, loadAllComments, config)
 
-	page := b.NewPage()
-	defer page.Close()
+	result, info, err := withCache(s.cache, mode, cacheKindFeedDetail, key, func() (*xiaohongshu.FeedDetailResponse, error) {
+		b := newBrowser()
+		defer b.Close()
 
-	action := xiaohongshu.NewFeedDetailAction(page)
+		page := b.NewPage()
+		defer page.Close()
 
-	result, err := action.GetFeedDetailWithConfig(ctx, feedID, xsecToken, loadAllComments, config)
+		action := xiaohongshu.NewFeedDetailAction(page)
+		return action.GetFeedDetailWithConfig(ctx, feedID, xsecToken, loadAllComments, config)
+	})
 	if err != nil {
 		return nil, err
 	}
+	// 地址按本次请求的参数拼，不进缓存的 key
+	result.Note.FillCDNURLs(images)
 
 	response := &FeedDetailResponse{
 		FeedID: feedID,
 		Data:   result,
+		Cache:  info,
 	}
 
 	return response, nil
 }
 
-// UserProfile 获取用户信息
-func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken, tab string) (*UserProfileResponse, error) {
-	parsed, err := xiaohongshu.ParseProfileTab(tab)
-	if err != nil {
-		return nil, err
-	}
-
+// GetCommentReplies 获取一级评论下的全部回复
+func (s *XiaohongshuService) GetCommentReplies(ctx context.Context, feedID, xsecToken, commentID string) (*xiaohongshu.CommentReplies, error) {
 	b := newBrowser()
 	defer b.Close()
 
 	page := b.NewPage()
 	defer page.Close()
 
-	action := xiaohongshu.NewUserProfileAction(page)
+	action := xiaohongshu.NewFeedDetailAction(page)
+	return action.GetCommentReplies(ctx, feedID, xsecToken, commentID)
+}
 
-	result, err := action.UserProfile(ctx, userID, xsecToken, parsed)
+// UserProfile 获取用户信息，mode 决定是否使用缓存，images 决定封面地址的尺寸和格式
+func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken, tab string, mode cache.Mode, images xhscdn.Options) (*UserProfileResponse, error) {
+	parsed, err := xiaohongshu.ParseProfileTab(tab)
 	if err != nil {
 		return nil, err
 	}
+
+	key := fmt.Sprintf("%s|%v", userID, parsed)
+	result, info, err := withCache(s.cache, mode, cacheKindUserProfile, key, func() (*xiaohongshu.UserProfileResponse, error) {
+		b := newBrowser()
+		defer b.Close()
+
+		page := b.NewPage()
+		defer page.Close()
+
+		action := xiaohongshu.NewUserProfileAction(page)
+		return action.UserProfile(ctx, userID, xsecToken, parsed)
+	})
+	if err != nil {
+		return nil, err
+	}
+	xiaohongshu.FillFeedsCDNURLs(result.Feeds, images)
 	response := &UserProfileResponse{
 		UserBasicInfo: result.UserBasicInfo,
 		Interactions:  result.Interactions,
 		Feeds:         result.Feeds,
+		Cache:         info,
 	}
 
 	return response, nil
@@ -478,11 +643,12 @@ func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsec
 
 	action := xiaohongshu.NewCommentFeedAction(page)
 
-	if err := action.PostComment(ctx, feedID, xsecToken, content); err != nil {
+	result, err := action.PostComment(ctx, feedID, xsecToken, content)
+	if err != nil {
 		return nil, err
 	}
 
-	return &PostCommentResponse{FeedID: feedID, Success: true, Message: "评论发表成功"}, nil
+	return &PostCommentResponse{FeedID: feedID, Success: true, Message: "评论发表成功", FailedMentions: result.FailedMentions}, nil
 }
 
 // LikeFeed 点赞笔记
@@ -555,7 +721,8 @@ func (s *XiaohongshuService) ReplyCommentToFeed(ctx context.Context, feedID, xse
 
 	action := xiaohongshu.NewCommentFeedAction(page)
 
-	if err := action.ReplyToComment(ctx, feedID, xsecToken, commentID, userID, content); err != nil {
+	result, err := action.ReplyToComment(ctx, feedID, xsecToken, commentID, userID, content)
+	if err != nil {
 		return nil, err
 	}
 
@@ -565,6 +732,7 @@ func (s *XiaohongshuService) ReplyCommentToFeed(ctx context.Context, feedID, xse
 		TargetUserID:    userID,
 		Success:         true,
 		Message:         "评论回复成功",
+		FailedMentions:  result.FailedMentions,
 	}, nil
 }
 
@@ -650,8 +818,8 @@ func withBrowserPage(fn func(*rod.Page) error) error {
 	return fn(page)
 }
 
-// GetMyProfile 获取当前登录用户的个人信息
-func (s *XiaohongshuService) GetMyProfile(ctx context.Context, tab string) (*UserProfileResponse, error) {
+// GetMyProfile 获取当前登录用户的个人信息，images 决定封面地址的尺寸和格式
+func (s *XiaohongshuService) GetMyProfile(ctx context.Context, tab string, images xhscdn.Options) (*UserProfileResponse, error) {
 	parsed, err := xiaohongshu.ParseProfileTab(tab)
 	if err != nil {
 		return nil, err
@@ -669,6 +837,7 @@ func (s *XiaohongshuService) GetMyProfile(ctx context.Context, tab string) (*Use
 		return nil, err
 	}
 
+	xiaohongshu.FillFeedsCDNURLs(result.Feeds, images)
 	response := &UserProfileResponse{
 		UserBasicInfo: result.UserBasicInfo,
 		Interactions:  result.Interactions,
@@ -677,3 +846,82 @@ func (s *XiaohongshuService) GetMyProfile(ctx context.Context, tab string) (*Use
 
 	return response, nil
 }
+
+// ListCollections 账号下的合集
+func (s *XiaohongshuService) ListCollections(ctx context.Context) ([]xiaohongshu.Collection, error) {
+	var list []xiaohongshu.Collection
+	err := withBrowserPage(func(page *rod.Page) error {
+		var err error
+		list, err = xiaohongshu.NewCollectionAction(page).List(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// DeleteNote 删除当前账号的笔记，删除不可恢复，必须显式 confirm
+func (s *XiaohongshuService) DeleteNote(ctx context.Context, feedID string, confirm bool) (*xiaohongshu.DeletedNote, error) {
+	if feedID == "" {
+		return nil, fmt.Errorf("缺少 feed_id")
+	}
+	if !confirm {
+		return nil, fmt.Errorf("删除笔记不可恢复，点赞、评论和收藏都会一起消失；确认要删除请传 confirm=true")
+	}
+
+	var deleted *xiaohongshu.DeletedNote
+	err := withBrowserPage(func(page *rod.Page) error {
+		var err error
+		deleted, err = xiaohongshu.NewNoteManageAction(page).Delete(ctx, feedID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// EditNoteRequest 编辑已发布笔记，只填要改的字段
+type EditNoteRequest struct {
+	FeedID     string   `json:"feed_id"`
+	Title      string   `json:"title,omitempty"`      // 新标题，为空不改
+	Content    string   `json:"content,omitempty"`    // 新正文，为空不改，支持 @{昵称}
+	Tags       []string `json:"tags"`                 // 新话题，不传不改，传 [] 清空
+	Visibility string   `json:"visibility,omitempty"` // 新可见范围，为空不改
+}
+
+// EditNote 修改当前账号的笔记并重新发布，返回实际改动的字段
+func (s *XiaohongshuService) EditNote(ctx context.Context, req *EditNoteRequest) (*xiaohongshu.EditResult, error) {
+	if req.FeedID == "" {
+		return nil, fmt.Errorf("缺少 feed_id")
+	}
+	edit := xiaohongshu.NoteEdit{
+		Title:      strings.TrimSpace(req.Title),
+		Content:    req.Content,
+		Tags:       req.Tags,
+		Visibility: req.Visibility,
+	}
+	if edit.IsZero() {
+		return nil, fmt.Errorf("至少指定 title、content、tags、visibility 中的一项")
+	}
+	if err := publishing.CheckTitle(edit.Title); err != nil {
+		return nil, err
+	}
+	switch edit.Visibility {
+	case "", "公开可见", "仅自己可见", "仅互关好友可见":
+	default:
+		return nil, fmt.Errorf("不支持的可见范围: %s，支持: 公开可见、仅自己可见、仅互关好友可见", edit.Visibility)
+	}
+
+	var result *xiaohongshu.EditResult
+	err := withBrowserPage(func(page *rod.Page) error {
+		var err error
+		result, err = xiaohongshu.NewNoteManageAction(page).Edit(ctx, req.FeedID, edit)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/subtitle"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// sourceSubtitleKey 字幕 map 里表示原始语种的 key
+const sourceSubtitleKey = "source"
+
+// VideoSubtitlesRequest 获取视频字幕请求
+type VideoSubtitlesRequest struct {
+	FeedID          string `json:"feed_id" binding:"required"`
+	XsecToken       string `json:"xsec_token" binding:"required"`
+	Language        string `json:"language,omitempty"`         // 为空取原始语种
+	Format          string `json:"format,omitempty"`           // text(默认) | srt | vtt
+	StripTimestamps bool   `json:"strip_timestamps,omitempty"` // 仅 text 生效
+}
+
+// VideoSubtitlesResponse 获取视频字幕响应
+type VideoSubtitlesResponse struct {
+	FeedID    string   `json:"feed_id"`
+	Language  string   `json:"language"`
+	Format    string   `json:"format"`
+	Available []string `json:"available"` // 笔记提供的全部字幕语种
+	Cues      int      `json:"cues"`
+	Content   string   `json:"content"`
+}
+
+// GetVideoSubtitles 下载视频笔记的字幕，按指定格式返回
+func (s *XiaohongshuService) GetVideoSubtitles(ctx context.Context, req *VideoSubtitlesRequest) (*VideoSubtitlesResponse, error) {
+	format, err := subtitle.ParseFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+	if req.StripTimestamps && format != subtitle.FormatText {
+		return nil, fmt.Errorf("%s 格式必须包含时间轴，去时间轴请用 text", format)
+	}
+
+	detail, err := s.fetchFeedDetail(ctx, req.FeedID, req.XsecToken)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Note.Video == nil {
+		return nil, fmt.Errorf("笔记 %s 不是视频笔记", req.FeedID)
+	}
+
+	language, sub, err := pickSubtitle(detail.Note.Video.Subtitles, req.Language)
+	if err != nil {
+		return nil, fmt.Errorf("笔记 %s %w", req.FeedID, err)
+	}
+
+	data, err := downloader.Fetch(ctx, sub.URL)
+	if err != nil {
+		return nil, fmt.Errorf("下载字幕失败: %w", err)
+	}
+
+	cues, err := subtitle.ParseSRT(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析字幕失败: %w", err)
+	}
+
+	content, err := subtitle.Render(cues, format, !req.StripTimestamps)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VideoSubtitlesResponse{
+		FeedID:    req.FeedID,
+		Language:  language,
+		Format:    string(format),
+		Available: subtitleLanguages(detail.Note.Video.Subtitles),
+		Cues:      len(cues),
+		Content:   content,
+	}, nil
+}
+
+// pickSubtitle 按语种挑字幕，返回实际命中的语种。
+// language 为空时取原始语种，没有原始语种再取排序后的第一个；
+// 否则先比 key，再比字幕自带的 language 字段，都不分大小写。
+func pickSubtitle(subs map[string][]xiaohongshu.VideoSubtitle, language string) (string, *xiaohongshu.VideoSubtitle, error) {
+	available := subtitleLanguages(subs)
+	if len(available) == 0 {
+		return "", nil, fmt.Errorf("没有字幕")
+	}
+
+	if language == "" {
+		language = sourceSubtitleKey
+		if _, ok := firstSubtitle(subs[language]); !ok {
+			language = available[0]
+		}
+	}
+
+	if sub, ok := firstSubtitle(subs[language]); ok {
+		return language, sub, nil
+	}
+
+	for _, key := range available {
+		for i, sub := range subs[key] {
+			if sub.URL == "" {
+				continue
+			}
+			if strings.EqualFold(key, language) || strings.EqualFold(sub.Language, language) {
+				return key, &subs[key][i], nil
+			}
+		}
+	}
+
+	return "", nil, fmt.Errorf("没有 %s 字幕，可选: %s", language, strings.Join(available, ", "))
+}
+
+// subtitleLanguages 有可用地址的语种，排序后返回
+func subtitleLanguages(subs map[string][]xiaohongshu.VideoSubtitle) []string {
+	var languages []string
+	for key, list := range subs {
+		if _, ok := firstSubtitle(list); ok {
+			languages = append(languages, key)
+		}
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+func firstSubtitle(list []xiaohongshu.VideoSubtitle) (*xiaohongshu.VideoSubtitle, bool) {
+	for i := range list {
+		if list[i].URL != "" {
+			return &list[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// TestPickSubtitle 空语种取原始语种；key 与 language 字段都能命中；未命中时报出可选语种。
+func TestPickSubtitle(t *testing.T) {
+	subs := map[string][]xiaohongshu.VideoSubtitle{
+		"source": {{URL: "https://s/zh.srt", Language: "zh-CN"}},
+		"en":     {{URL: "https://s/en.srt", Language: "en-US"}},
+		"ja":     {{Language: "ja"}}, // 没有地址，不算可选
+	}
+
+	lang, sub, err := pickSubtitle(subs, "")
+	require.NoError(t, err)
+	assert.Equal(t, "source", lang)
+	assert.Equal(t, "https://s/zh.srt", sub.URL)
+
+	lang, sub, err = pickSubtitle(subs, "EN")
+	require.NoError(t, err)
+	assert.Equal(t, "en", lang)
+	assert.Equal(t, "https://s/en.srt", sub.URL)
+
+	lang, _, err = pickSubtitle(subs, "zh-cn")
+	require.NoError(t, err)
+	assert.Equal(t, "source", lang, "按字幕自带的 language 字段匹配")
+
+	_, _, err = pickSubtitle(subs, "ja")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "可选: en, source")
+
+	lang, _, err = pickSubtitle(map[string][]xiaohongshu.VideoSubtitle{"fr": {{URL: "f"}}, "de": {{URL: "d"}}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "de", lang, "没有原始语种时取排序后的第一个")
+
+	_, _, err = pickSubtitle(nil, "")
+	assert.Error(t, err)
+}
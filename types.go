@@ -52,17 +52,24 @@ type FeedDetailRequest struct {
 	XsecToken       string             `json:"xsec_token" binding:"required"`
 	LoadAllComments bool               `json:"load_all_comments,omitempty"`
 	CommentConfig   *CommentLoadConfig `json:"comment_config,omitempty"`
+	ImageFormat     string             `json:"image_format,omitempty"` // 首帧、缩略图地址的格式: webp | jpg | png，为空不转
+	ImageWidth      int                `json:"image_width,omitempty"`  // 首帧、缩略图等比缩放到的宽度，0 不缩放
+	Cache           string             `json:"cache,omitempty"`        // bypass(默认) | prefer | only
 }
 
 type SearchFeedsRequest struct {
-	Keyword string                   `json:"keyword" binding:"required"`
-	Filters xiaohongshu.FilterOption `json:"filters,omitempty"`
+	Keyword     string                   `json:"keyword" binding:"required"`
+	Filters     xiaohongshu.FilterOption `json:"filters,omitempty"`
+	Cache       string                   `json:"cache,omitempty"`        // bypass(默认) | prefer | only
+	ImageFormat string                   `json:"image_format,omitempty"` // 封面地址的格式: webp | jpg | png，为空不转
+	ImageWidth  int                      `json:"image_width,omitempty"`  // 封面等比缩放到的宽度，0 不缩放
 }
 
 // FeedDetailResponse Feed详情响应
 type FeedDetailResponse struct {
-	FeedID string `json:"feed_id"`
-	Data   any    `json:"data"`
+	FeedID string     `json:"feed_id"`
+	Data   any        `json:"data"`
+	Cache  *CacheInfo `json:"cache,omitempty"`
 }
 
 // PostCommentRequest 发表评论请求
@@ -74,9 +81,10 @@ type PostCommentRequest struct {
 
 // PostCommentResponse 发表评论响应
 type PostCommentResponse struct {
-	FeedID  string `json:"feed_id"`
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	FeedID         string   `json:"feed_id"`
+	Success        bool     `json:"success"`
+	Message        string   `json:"message"`
+	FailedMentions []string `json:"failed_mentions,omitempty"` // 未能选中的 @ 用户，已按普通文本发出
 }
 
 // ReplyCommentRequest 回复评论请求
@@ -90,18 +98,29 @@ type ReplyCommentRequest struct {
 
 // ReplyCommentResponse 回复评论响应
 type ReplyCommentResponse struct {
-	FeedID          string `json:"feed_id"`
-	TargetCommentID string `json:"target_comment_id,omitempty"`
-	TargetUserID    string `json:"target_user_id,omitempty"`
-	Success         bool   `json:"success"`
-	Message         string `json:"message"`
+	FeedID          string   `json:"feed_id"`
+	TargetCommentID string   `json:"target_comment_id,omitempty"`
+	TargetUserID    string   `json:"target_user_id,omitempty"`
+	Success         bool     `json:"success"`
+	Message         string   `json:"message"`
+	FailedMentions  []string `json:"failed_mentions,omitempty"` // 未能选中的 @ 用户，已按普通文本发出
+}
+
+// CommentRepliesRequest 评论回复列表请求
+type CommentRepliesRequest struct {
+	FeedID    string `json:"feed_id" binding:"required"`
+	XsecToken string `json:"xsec_token" binding:"required"`
+	CommentID string `json:"comment_id" binding:"required"`
 }
 
 // UserProfileRequest 用户主页请求
 type UserProfileRequest struct {
-	UserID    string `json:"user_id" binding:"required"`
-	XsecToken string `json:"xsec_token" binding:"required"`
-	Tab       string `json:"tab,omitempty"`
+	UserID      string `json:"user_id" binding:"required"`
+	XsecToken   string `json:"xsec_token" binding:"required"`
+	Tab         string `json:"tab,omitempty"`
+	Cache       string `json:"cache,omitempty"`        // bypass(默认) | prefer | only
+	ImageFormat string `json:"image_format,omitempty"` // 封面地址的格式: webp | jpg | png，为空不转
+	ImageWidth  int    `json:"image_width,omitempty"`  // 封面等比缩放到的宽度，0 不缩放
 }
 
 // LikeFeedRequest 点赞/取消点赞请求
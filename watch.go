@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/humanize"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/watchlist"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhsutil"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// 采样间隔，XHS_WATCH_INTERVAL 可调，最短 10 分钟，免得把账号刷出风控
+const (
+	defaultWatchInterval = time.Hour
+	minWatchInterval     = 10 * time.Minute
+)
+
+// WatchNoteRequest 关注笔记请求
+type WatchNoteRequest struct {
+	FeedID    string `json:"feed_id" binding:"required"`
+	XsecToken string `json:"xsec_token" binding:"required"`
+	Label     string `json:"label,omitempty"`
+}
+
+// WatchNoteResponse 关注笔记响应，附带加入时采的第一个点
+type WatchNoteResponse struct {
+	Added    bool                `json:"added"` // false 表示已在列表里，只更新了 xsec_token 和备注
+	Note     watchlist.Note      `json:"note"`
+	Snapshot *watchlist.Snapshot `json:"snapshot"`
+}
+
+// NoteMetricsHistoryResponse 互动数序列
+type NoteMetricsHistoryResponse struct {
+	FeedID string               `json:"feed_id"`
+	Series []watchlist.Snapshot `json:"series"`
+	Total  *watchlist.Growth    `json:"total,omitempty"` // 序列首尾的增量，少于两个点时为空
+	Steps  []watchlist.Growth   `json:"steps,omitempty"` // 相邻两次采样之间的增量
+}
+
+// WatchNote 把笔记加入关注列表，并立即采一次：既是基线，也顺带验证 xsec_token 可用
+func (s *XiaohongshuService) WatchNote(ctx context.Context, req *WatchNoteRequest) (*WatchNoteResponse, error) {
+	snap, err := s.sampleNote(ctx, req.FeedID, req.XsecToken)
+	if err != nil {
+		return nil, err
+	}
+
+	note := watchlist.Note{FeedID: req.FeedID, XsecToken: req.XsecToken, Label: req.Label}
+	added, err := s.watchlist.Add(note)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.watchlist.Record(req.FeedID, snap, nil); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.watchlist.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range notes {
+		if n.FeedID == req.FeedID {
+			note = n
+		}
+	}
+
+	return &WatchNoteResponse{Added: added, Note: note, Snapshot: snap}, nil
+}
+
+// UnwatchNote 取消关注，已采集的序列保留
+func (s *XiaohongshuService) UnwatchNote(feedID string) error {
+	removed, err := s.watchlist.Remove(feedID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("笔记 %s 不在关注列表里", feedID)
+	}
+	return nil
+}
+
+// ListWatchedNotes 关注列表
+func (s *XiaohongshuService) ListWatchedNotes() ([]watchlist.Note, error) {
+	return s.watchlist.List()
+}
+
+// NoteMetricsHistory 返回最近 sinceHours 小时的互动数序列和增量，sinceHours 为 0 时返回全部
+func (s *XiaohongshuService) NoteMetricsHistory(feedID string, sinceHours int) (*NoteMetricsHistoryResponse, error) {
+	var since time.Time
+	if sinceHours > 0 {
+		since = time.Now().Add(-time.Duration(sinceHours) * time.Hour)
+	}
+
+	series, err := s.watchlist.History(feedID, since)
+	if err != nil {
+		return nil, err
+	}
+	if len(series) == 0 {
+		return nil, fmt.Errorf("笔记 %s 没有采样记录，请先用 watch_note 关注", feedID)
+	}
+
+	total, steps := watchlist.Summarize(series)
+	return &NoteMetricsHistoryResponse{FeedID: feedID, Series: series, Total: total, Steps: steps}, nil
+}
+
+// sampleWatchlist 给关注列表里的每篇笔记采一次，由后台任务定时调用
+func (s *XiaohongshuService) sampleWatchlist(ctx context.Context) {
+	notes, err := s.watchlist.List()
+	if err != nil {
+		logrus.Errorf("读取关注列表失败: %v", err)
+		return
+	}
+
+	for i, note := range notes {
+		if ctx.Err() != nil {
+			return
+		}
+		if i > 0 {
+			// 笔记之间停顿一下，不要连续打开详情页
+			humanize.Delay(ctx, humanize.Reading)
+		}
+
+		snap, err := s.sampleNote(ctx, note.FeedID, note.XsecToken)
+		if err != nil {
+			logrus.Warnf("采样笔记 %s 失败: %v", note.FeedID, err)
+		}
+		if err := s.watchlist.Record(note.FeedID, snap, err); err != nil {
+			logrus.Errorf("记录笔记 %s 采样结果失败: %v", note.FeedID, err)
+		}
+	}
+}
+
+// sampleNote 打开详情页取一次互动数，不走缓存
+func (s *XiaohongshuService) sampleNote(ctx context.Context, feedID, xsecToken string) (*watchlist.Snapshot, error) {
+	detail, err := s.fetchFeedDetail(ctx, feedID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+	snap := snapshotOf(detail.Note.InteractInfo, time.Now())
+	return &snap, nil
+}
+
+// snapshotOf 取解析后的互动数，解析不了的按 0 记并标记为不精确
+func snapshotOf(info xiaohongshu.InteractInfo, at time.Time) watchlist.Snapshot {
+	snap := watchlist.Snapshot{At: at, Exact: true}
+
+	for _, f := range []struct {
+		dst *int64
+		src *xhsutil.Count
+	}{
+		{&snap.Liked, info.LikedCountNum},
+		{&snap.Collected, info.CollectedCountNum},
+		{&snap.Comment, info.CommentCountNum},
+		{&snap.Shared, info.SharedCountNum},
+	} {
+		if f.src == nil {
+			snap.Exact = false
+			continue
+		}
+		*f.dst = f.src.Value
+		snap.Exact = snap.Exact && f.src.Exact
+	}
+	return snap
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhsutil"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// TestSnapshotOf 任一项是近似值或解析不了，整个快照标为不精确。
+func TestSnapshotOf(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	exact := snapshotOf(xiaohongshu.InteractInfo{
+		LikedCountNum:     &xhsutil.Count{Value: 10, Exact: true},
+		CollectedCountNum: &xhsutil.Count{Value: 2, Exact: true},
+		CommentCountNum:   &xhsutil.Count{Value: 3, Exact: true},
+		SharedCountNum:    &xhsutil.Count{Value: 0, Exact: true},
+	}, at)
+	assert.True(t, exact.Exact)
+	assert.Equal(t, int64(10), exact.Liked)
+	assert.Equal(t, int64(3), exact.Comment)
+
+	approx := snapshotOf(xiaohongshu.InteractInfo{
+		LikedCountNum:   &xhsutil.Count{Value: 12000},
+		CommentCountNum: &xhsutil.Count{Value: 5, Exact: true},
+	}, at)
+	assert.False(t, approx.Exact)
+	assert.Equal(t, int64(12000), approx.Liked)
+	assert.Equal(t, int64(0), approx.Shared)
+	assert.Equal(t, at, approx.At)
+}
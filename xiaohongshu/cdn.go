@@ -0,0 +1,28 @@
+package xiaohongshu
+
+import "github.com/xpzouying/xiaohongshu-mcp/pkg/xhscdn"
+
+// FillCDNURLs 按 fileid 补全封面地址
+func (c *Cover) FillCDNURLs(opts xhscdn.Options) {
+	c.FileURL = xhscdn.ImageURL(c.FileID, opts)
+}
+
+// FillCDNURLs 按 fileid 补全视频首帧与缩略图地址
+func (v *VideoImage) FillCDNURLs(opts xhscdn.Options) {
+	v.FirstFrameURL = xhscdn.ImageURL(v.FirstFrameFileID, opts)
+	v.ThumbnailURL = xhscdn.ImageURL(v.ThumbnailFileID, opts)
+}
+
+// FillCDNURLs 补全详情里只有 fileid 的图片地址，图文笔记没有需要补的
+func (f *FeedDetail) FillCDNURLs(opts xhscdn.Options) {
+	if f.Video != nil {
+		f.Video.Image.FillCDNURLs(opts)
+	}
+}
+
+// FillFeedsCDNURLs 补全列表里每条笔记的封面地址
+func FillFeedsCDNURLs(feeds []Feed, opts xhscdn.Options) {
+	for i := range feeds {
+		feeds[i].NoteCard.Cover.FillCDNURLs(opts)
+	}
+}
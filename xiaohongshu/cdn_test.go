@@ -0,0 +1,25 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhscdn"
+)
+
+func TestFillCDNURLs(t *testing.T) {
+	opts := xhscdn.Options{Width: 720, Format: xhscdn.FormatWebP}
+
+	detail := FeedDetail{Video: &VideoDetail{Image: VideoImage{FirstFrameFileID: "ff", ThumbnailFileID: ""}}}
+	detail.FillCDNURLs(opts)
+	assert.Equal(t, "https://ci.xiaohongshu.com/ff?imageView2/2/w/720/format/webp", detail.Video.Image.FirstFrameURL)
+	assert.Empty(t, detail.Video.Image.ThumbnailURL, "没有 fileid 不拼地址")
+
+	// 图文笔记没有 video，不应 panic
+	(&FeedDetail{}).FillCDNURLs(opts)
+
+	feeds := []Feed{{NoteCard: NoteCard{Cover: Cover{FileID: "c1"}}}, {}}
+	FillFeedsCDNURLs(feeds, xhscdn.Options{})
+	assert.Equal(t, "https://ci.xiaohongshu.com/c1", feeds[0].NoteCard.Cover.FileURL)
+	assert.Empty(t, feeds[1].NoteCard.Cover.FileURL)
+}
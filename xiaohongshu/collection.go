@@ -0,0 +1,300 @@
+package xiaohongshu
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/humanize"
+)
+
+const urlOfNoteManager = "https://creator.xiaohongshu.com/new/note-manager"
+
+// Collection 账号下的一个合集
+type Collection struct {
+	Name      string `json:"name"`
+	NoteCount int    `json:"note_count"` // 合集内笔记数，页面上没有显示时为 0
+}
+
+// CollectionError 合集不存在且不允许创建，带上已有合集方便调用方改名重试
+type CollectionError struct {
+	Name      string
+	Available []string
+}
+
+func (e *CollectionError) Error() string {
+	if len(e.Available) == 0 {
+		return fmt.Sprintf("合集 %q 不存在，账号下还没有合集；如需自动创建请开启 create_collection", e.Name)
+	}
+	return fmt.Sprintf("合集 %q 不存在，已有合集: %s；如需自动创建请开启 create_collection", e.Name, strings.Join(e.Available, "、"))
+}
+
+// CollectionAction 合集相关操作
+type CollectionAction struct {
+	page *rod.Page
+}
+
+// NewCollectionAction 创建合集操作
+func NewCollectionAction(page *rod.Page) *CollectionAction {
+	return &CollectionAction{page: page}
+}
+
+// List 打开创作中心笔记管理的「合集」页，读取账号下的合集
+func (a *CollectionAction) List(ctx context.Context) ([]Collection, error) {
+	page := a.page.Context(ctx).Timeout(60 * time.Second)
+
+	if err := page.Navigate(urlOfNoteManager); err != nil {
+		return nil, errors.Wrap(err, "打开笔记管理失败")
+	}
+	if err := page.WaitLoad(); err != nil {
+		logrus.Warnf("等待页面加载出现问题: %v，继续尝试", err)
+	}
+	if err := page.WaitDOMStable(time.Second, 0.1); err != nil {
+		logrus.Warnf("等待 DOM 稳定出现问题: %v，继续尝试", err)
+	}
+	humanize.Delay(ctx, humanize.AfterNavigate)
+
+	tab, err := page.Timeout(10*time.Second).ElementR("div, span", `^\s*合集`)
+	if err != nil {
+		return nil, errors.Wrap(err, "未找到合集标签页，可能未登录创作中心")
+	}
+	if err := humanize.Click(tab); err != nil {
+		return nil, errors.Wrap(err, "切换到合集标签页失败")
+	}
+	time.Sleep(2 * time.Second) // 技术等待：等合集列表加载
+
+	cards, err := page.Elements(`[class*="collection"] [class*="card"], [class*="collection-item"]`)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取合集列表失败")
+	}
+
+	var list []Collection
+	seen := make(map[string]bool)
+	for _, card := range cards {
+		text, err := card.Text()
+		if err != nil {
+			continue
+		}
+		c := parseCollectionCard(text)
+		if c.Name == "" || seen[c.Name] {
+			continue
+		}
+		seen[c.Name] = true
+		list = append(list, c)
+	}
+	logrus.Infof("读取到 %d 个合集", len(list))
+	return list, nil
+}
+
+// noteCountPattern 合集卡片上的笔记数，如「共 12 篇笔记」「12篇」
+var noteCountPattern = regexp.MustCompile(`(\d+)\s*篇`)
+
+// parseCollectionCard 第一行是合集名，其余行里找「N 篇」作为笔记数
+func parseCollectionCard(text string) Collection {
+	var c Collection
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if c.Name == "" {
+			c.Name = line
+			continue
+		}
+		if m := noteCountPattern.FindStringSubmatch(line); m != nil && c.NoteCount == 0 {
+			c.NoteCount, _ = strconv.Atoi(m[1])
+		}
+	}
+	return c
+}
+
+// matchCollection 按名称找合集选项，先比原文再忽略大小写，都不区分首尾空白
+func matchCollection(names []string, name string) int {
+	want := strings.TrimSpace(name)
+	for i, n := range names {
+		if strings.TrimSpace(n) == want {
+			return i
+		}
+	}
+	for i, n := range names {
+		if strings.EqualFold(strings.TrimSpace(n), want) {
+			return i
+		}
+	}
+	return -1
+}
+
+// collectionTrigger 发布表单里打开合集下拉的入口文案
+const collectionTrigger = `^\s*(添加到合集|选择合集)\s*$`
+
+// setCollection 在发布表单里把笔记加入合集；合集不存在时按 create 决定新建还是返回 *CollectionError
+func setCollection(ctx context.Context, page *rod.Page, name string, create bool) error {
+	if name == "" {
+		return nil
+	}
+
+	trigger, err := page.Timeout(5*time.Second).ElementR("div, span", collectionTrigger)
+	if err != nil {
+		return errors.Wrap(err, "未找到添加到合集入口")
+	}
+	if err := humanize.Click(trigger); err != nil {
+		return errors.Wrap(err, "点击添加到合集失败")
+	}
+	items, names := waitCollectionOptions(page, 5*time.Second)
+	if idx := matchCollection(names, name); idx >= 0 {
+		if err := humanize.Click(items[idx]); err != nil {
+			return errors.Wrap(err, "点击合集选项失败")
+		}
+		slog.Info("已加入合集", "collection", names[idx])
+		humanize.Delay(ctx, humanize.AfterClick)
+		return nil
+	}
+
+	if !create {
+		closeDropdown(page)
+		return &CollectionError{Name: name, Available: names}
+	}
+	return createCollection(ctx, page, name)
+}
+
+// createCollection 在合集下拉里新建合集；新建后平台一般会自动选中，没选中时再从下拉里点一次，
+// 仍然没选中就报错，不能让笔记悄悄发到合集外面
+func createCollection(ctx context.Context, page *rod.Page, name string) error {
+	btn, err := page.Timeout(5*time.Second).ElementR("div, span, button", `^\s*[+＋]?\s*(创建合集|新建合集)\s*$`)
+	if err != nil {
+		return errors.Wrap(err, "未找到创建合集按钮")
+	}
+	if err := humanize.Click(btn); err != nil {
+		return errors.Wrap(err, "点击创建合集失败")
+	}
+
+	modal, err := waitForModal(page, "合集", 10*time.Second)
+	if err != nil {
+		return err
+	}
+	nameInput, err := modal.Timeout(5 * time.Second).Element("input")
+	if err != nil {
+		return errors.Wrap(err, "未找到合集名称输入框")
+	}
+	if err := humanize.Type(ctx, nameInput, name); err != nil {
+		return errors.Wrap(err, "输入合集名称失败")
+	}
+	humanize.Delay(ctx, humanize.AfterType)
+
+	confirm, err := modal.Timeout(5*time.Second).ElementR("button", `^\s*(确定|创建|完成)\s*$`)
+	if err != nil {
+		return errors.Wrap(err, "未找到创建合集确认按钮")
+	}
+	if err := humanize.Click(confirm); err != nil {
+		return errors.Wrap(err, "点击创建合集确认按钮失败")
+	}
+	if err := waitModalClosed(page, "合集", 10*time.Second); err != nil {
+		return errors.Wrap(err, "创建合集未完成")
+	}
+	slog.Info("已创建合集", "collection", name)
+
+	if waitCollectionSelected(page, name, 3*time.Second) {
+		return nil
+	}
+
+	// 没自动选中：下拉还开着就直接找，收起了就重新打开
+	items, names := waitCollectionOptions(page, 2*time.Second)
+	if len(items) == 0 {
+		if trigger, err := page.Timeout(2*time.Second).ElementR("div, span", collectionTrigger); err == nil {
+			if err := humanize.Click(trigger); err != nil {
+				return errors.Wrap(err, "点击添加到合集失败")
+			}
+			items, names = waitCollectionOptions(page, 5*time.Second)
+		}
+	}
+	if idx := matchCollection(names, name); idx >= 0 {
+		if err := humanize.Click(items[idx]); err != nil {
+			return errors.Wrap(err, "点击新建的合集失败")
+		}
+	}
+	if !waitCollectionSelected(page, name, 3*time.Second) {
+		closeDropdown(page)
+		return errors.Errorf("合集「%s」已创建，但未能在表单里选中", name)
+	}
+	return nil
+}
+
+// waitCollectionOptions 轮询合集下拉，直到选项出现且连续两次读到的一样（分页加载完了），超时返回最后一次的结果
+func waitCollectionOptions(page *rod.Page, timeout time.Duration) ([]*rod.Element, []string) {
+	deadline := time.Now().Add(timeout)
+	items, names := collectionOptions(page)
+	for time.Now().Before(deadline) {
+		time.Sleep(300 * time.Millisecond)
+		nextItems, nextNames := collectionOptions(page)
+		if len(nextNames) > 0 && slices.Equal(names, nextNames) {
+			return nextItems, nextNames
+		}
+		items, names = nextItems, nextNames
+	}
+	return items, names
+}
+
+// waitCollectionSelected 等表单里显示已选中的合集名。下拉选项和弹窗里的同名文本不算
+func waitCollectionSelected(page *rod.Page, name string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		res, err := page.Eval(`(name) => {
+			const skip = '.d-option, .d-select-dropdown, .d-dropdown, .d-modal';
+			for (const el of document.querySelectorAll('div, span')) {
+				if (el.children.length > 0 || el.offsetParent === null || el.closest(skip)) continue;
+				if (el.textContent.trim() === name) return true;
+			}
+			return false;
+		}`, strings.TrimSpace(name))
+		if err == nil && res.Value.Bool() {
+			return true
+		}
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+}
+
+// collectionOptions 读取可见的合集下拉选项，名称取第一行
+func collectionOptions(page *rod.Page) ([]*rod.Element, []string) {
+	elems, err := page.Elements(".d-select-dropdown .d-option, .d-dropdown .d-option")
+	if err != nil {
+		return nil, nil
+	}
+
+	var items []*rod.Element
+	var names []string
+	for _, el := range elems {
+		if visible, _ := el.Visible(); !visible {
+			continue
+		}
+		text, err := el.Text()
+		if err != nil {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+		if name = strings.TrimSpace(name); name == "" || strings.Contains(name, "合集") && strings.Contains(name, "创建") {
+			continue
+		}
+		items = append(items, el)
+		names = append(names, name)
+	}
+	return items, names
+}
+
+// closeDropdown 按 Esc 收起当前下拉
+func closeDropdown(page *rod.Page) {
+	if err := page.Keyboard.Type(input.Escape); err != nil {
+		slog.Warn("收起下拉失败", "error", err)
+	}
+}
@@ -0,0 +1,29 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCollectionCard(t *testing.T) {
+	assert.Equal(t, Collection{Name: "周末探店", NoteCount: 12}, parseCollectionCard("  周末探店\n\n共 12 篇笔记\n更新于 3 天前"))
+	assert.Equal(t, Collection{Name: "新合集"}, parseCollectionCard("新合集"))
+	assert.Equal(t, Collection{}, parseCollectionCard(" \n "))
+}
+
+func TestMatchCollection(t *testing.T) {
+	names := []string{"周末探店", "Travel Log", "探店"}
+
+	assert.Equal(t, 2, matchCollection(names, "探店"), "完全一致，不会命中包含它的合集")
+	assert.Equal(t, 1, matchCollection(names, " travel log "), "忽略大小写和首尾空白")
+	assert.Equal(t, -1, matchCollection(names, "周末"))
+	assert.Equal(t, -1, matchCollection(nil, "探店"))
+}
+
+func TestCollectionError(t *testing.T) {
+	err := &CollectionError{Name: "旅行", Available: []string{"周末探店", "穿搭"}}
+	assert.Equal(t, `合集 "旅行" 不存在，已有合集: 周末探店、穿搭；如需自动创建请开启 create_collection`, err.Error())
+
+	assert.Contains(t, (&CollectionError{Name: "旅行"}).Error(), "账号下还没有合集")
+}
@@ -20,8 +20,13 @@ func NewCommentFeedAction(page *rod.Page) *CommentFeedAction {
 	return &CommentFeedAction{page: page}
 }
 
+// CommentResult 评论/回复结果
+type CommentResult struct {
+	FailedMentions []string // 未能在联想列表里选中的 @ 用户，已按普通文本发出
+}
+
 // PostComment 发表评论到 Feed
-func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken, content string) error {
+func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken, content string) (*CommentResult, error) {
 	// 不使用 Context(ctx)，避免继承外部 context 的超时
 	page := f.page.Timeout(60 * time.Second)
 
@@ -35,30 +40,31 @@ func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken,
 
 	// 检测页面是否可访问
 	if err := checkPageAccessible(page); err != nil {
-		return err
+		return nil, err
 	}
 
 	elem, err := page.Element("div.input-box div.content-edit span")
 	if err != nil {
 		logrus.Warnf("Failed to find comment input box: %v", err)
-		return fmt.Errorf("未找到评论输入框，该帖子可能不支持评论或网页端不可访问: %w", err)
+		return nil, fmt.Errorf("未找到评论输入框，该帖子可能不支持评论或网页端不可访问: %w", err)
 	}
 
 	if err := humanize.Click(elem); err != nil {
 		logrus.Warnf("Failed to click comment input box: %v", err)
-		return fmt.Errorf("无法点击评论输入框: %w", err)
+		return nil, fmt.Errorf("无法点击评论输入框: %w", err)
 	}
 	humanize.Delay(ctx, humanize.AfterClick)
 
 	elem2, err := page.Element("div.input-box div.content-edit p.content-input")
 	if err != nil {
 		logrus.Warnf("Failed to find comment input field: %v", err)
-		return fmt.Errorf("未找到评论输入区域: %w", err)
+		return nil, fmt.Errorf("未找到评论输入区域: %w", err)
 	}
 
-	if err := humanize.Type(ctx, elem2, content); err != nil {
+	failedMentions, err := typeWithMentions(ctx, elem2, content, commentMentionPopup)
+	if err != nil {
 		logrus.Warnf("Failed to input comment content: %v", err)
-		return fmt.Errorf("无法输入评论内容: %w", err)
+		return nil, fmt.Errorf("无法输入评论内容: %w", err)
 	}
 
 	humanize.Delay(ctx, humanize.AfterType)
@@ -66,24 +72,24 @@ func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken,
 	submitButton, err := page.Element("div.bottom button.submit")
 	if err != nil {
 		logrus.Warnf("Failed to find submit button: %v", err)
-		return fmt.Errorf("未找到提交按钮: %w", err)
+		return nil, fmt.Errorf("未找到提交按钮: %w", err)
 	}
 
 	if err := humanize.Click(submitButton); err != nil {
 		logrus.Warnf("Failed to click submit button: %v", err)
-		return fmt.Errorf("无法点击提交按钮: %w", err)
+		return nil, fmt.Errorf("无法点击提交按钮: %w", err)
 	}
 
 	humanize.Delay(ctx, humanize.AfterClick)
 
 	// 就地校验：提交后评论应在评论区渲染出现；未出现则判定失败，避免假成功。
-	if !waitCommentRendered(page, content, 4*time.Second) {
+	if !waitCommentRendered(page, MentionText(content), 4*time.Second) {
 		logrus.Warnf("评论提交后未在评论区渲染，判定未成功: feed=%s", feedID)
-		return fmt.Errorf("评论未确认成功：提交后未在评论区出现（可能账号被限制或发送失败），feed: %s", feedID)
+		return nil, fmt.Errorf("评论未确认成功：提交后未在评论区出现（可能账号被限制或发送失败），feed: %s", feedID)
 	}
 
 	logrus.Infof("Comment posted and verified to feed: %s", feedID)
-	return nil
+	return &CommentResult{FailedMentions: failedMentions}, nil
 }
 
 // commentRendered 就地读当前页评论区 DOM，判断指定文本的评论是否已渲染出现。
@@ -111,7 +117,7 @@ func waitCommentRendered(page *rod.Page, content string, timeout time.Duration)
 }
 
 // ReplyToComment 回复指定评论
-func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToken, commentID, userID, content string) error {
+func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToken, commentID, userID, content string) (*CommentResult, error) {
 	// 增加超时时间，因为需要滚动查找评论
 	// 注意：不使用 Context(ctx)，避免继承外部 context 的超时
 	page := f.page.Timeout(5 * time.Minute)
@@ -125,7 +131,7 @@ func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToke
 
 	// 检测页面是否可访问
 	if err := checkPageAccessible(page); err != nil {
-		return err
+		return nil, err
 	}
 
 	time.Sleep(2 * time.Second)
@@ -133,7 +139,7 @@ func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToke
 	// 使用 Go 实现的查找逻辑
 	commentEl, err := findCommentElement(ctx, page, commentID, userID)
 	if err != nil {
-		return fmt.Errorf("无法找到评论: %w", err)
+		return nil, fmt.Errorf("无法找到评论: %w", err)
 	}
 
 	// 滚动到评论位置
@@ -146,11 +152,11 @@ func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToke
 	// 查找并点击回复按钮
 	replyBtn, err := commentEl.Element(".right .interactions .reply")
 	if err != nil {
-		return fmt.Errorf("无法找到回复按钮: %w", err)
+		return nil, fmt.Errorf("无法找到回复按钮: %w", err)
 	}
 
 	if err := humanize.Click(replyBtn); err != nil {
-		return fmt.Errorf("点击回复按钮失败: %w", err)
+		return nil, fmt.Errorf("点击回复按钮失败: %w", err)
 	}
 
 	humanize.Delay(ctx, humanize.AfterClick)
@@ -158,12 +164,13 @@ func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToke
 	// 查找回复输入框
 	inputEl, err := page.Element("div.input-box div.content-edit p.content-input")
 	if err != nil {
-		return fmt.Errorf("无法找到回复输入框: %w", err)
+		return nil, fmt.Errorf("无法找到回复输入框: %w", err)
 	}
 
 	// 输入内容
-	if err := humanize.Type(ctx, inputEl, content); err != nil {
-		return fmt.Errorf("输入回复内容失败: %w", err)
+	failedMentions, err := typeWithMentions(ctx, inputEl, content, commentMentionPopup)
+	if err != nil {
+		return nil, fmt.Errorf("输入回复内容失败: %w", err)
 	}
 
 	humanize.Delay(ctx, humanize.AfterType)
@@ -171,23 +178,23 @@ func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToke
 	// 查找并点击提交按钮
 	submitBtn, err := page.Element("div.bottom button.submit")
 	if err != nil {
-		return fmt.Errorf("无法找到提交按钮: %w", err)
+		return nil, fmt.Errorf("无法找到提交按钮: %w", err)
 	}
 
 	if err := humanize.Click(submitBtn); err != nil {
-		return fmt.Errorf("点击提交按钮失败: %w", err)
+		return nil, fmt.Errorf("点击提交按钮失败: %w", err)
 	}
 
 	humanize.Delay(ctx, humanize.AfterClick)
 
 	// 就地校验：回复应在评论区渲染出现，否则判定未成功。
-	if !waitCommentRendered(page, content, 4*time.Second) {
+	if !waitCommentRendered(page, MentionText(content), 4*time.Second) {
 		logrus.Warnf("回复提交后未在评论区渲染，判定未成功: feed=%s", feedID)
-		return fmt.Errorf("回复未确认成功：提交后未在评论区出现（可能账号被限制或发送失败）")
+		return nil, fmt.Errorf("回复未确认成功：提交后未在评论区出现（可能账号被限制或发送失败）")
 	}
 
 	logrus.Infof("回复评论成功并已确认")
-	return nil
+	return &CommentResult{FailedMentions: failedMentions}, nil
 }
 
 // findCommentElement 滚动查找指定评论：优先按 commentID 命中，否则按 userID 匹配。
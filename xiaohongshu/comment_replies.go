@@ -0,0 +1,173 @@
+package xiaohongshu
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/humanize"
+)
+
+const (
+	// maxThreadExpandRounds 单个楼层最多点几次展开。每次约加载 10 条，足够覆盖上千条回复的长楼。
+	maxThreadExpandRounds = 200
+	// maxThreadClickFailures 连续点击失败几次就放弃，避免卡在点不动的按钮上空转。
+	maxThreadClickFailures = 3
+)
+
+// CommentReplies 单条一级评论及其完整回复。
+type CommentReplies struct {
+	FeedID  string    `json:"feed_id"`
+	Comment Comment   `json:"comment"` // 一级评论本身，SubComments 置空，回复见 Replies
+	Replies []Comment `json:"replies"`
+	// Total 页面标注的回复数（subCommentCount 原值）
+	Total string `json:"total"`
+	// Complete 为 true 表示楼层里已没有可展开的按钮；为 false 时 Replies 可能不全。
+	Complete bool `json:"complete"`
+}
+
+// GetCommentReplies 获取一级评论下的全部回复。
+//
+// 与 loadAll + ClickMoreReplies 的区别：那条路径在整页范围内展开，并按
+// MaxRepliesThreshold 跳过长楼；这里只展开目标楼层，且一直展开到没有按钮为止。
+func (f *FeedDetailAction) GetCommentReplies(ctx context.Context, feedID, xsecToken, commentID string) (*CommentReplies, error) {
+	page := f.page.Context(ctx).Timeout(10 * time.Minute)
+	url := makeFeedDetailURL(feedID, xsecToken)
+	logrus.Infof("打开 feed 详情页获取评论回复: %s, comment=%s", url, commentID)
+
+	if err := navigateFeedDetail(page, url); err != nil {
+		return nil, err
+	}
+	humanize.Delay(ctx, humanize.AfterNavigate)
+
+	if err := checkPageAccessible(page); err != nil {
+		return nil, err
+	}
+
+	commentEl, err := findCommentElement(ctx, page, commentID, "")
+	if err != nil {
+		return nil, fmt.Errorf("无法找到评论: %w", err)
+	}
+
+	// 先确认目标是一级评论，楼中楼没有自己的回复线程
+	detail, err := f.extractFeedDetail(page, feedID)
+	if err != nil {
+		return nil, err
+	}
+	if _, parentID := locateComment(detail.Comments.List, commentID); parentID != "" {
+		return nil, fmt.Errorf("评论 %s 是楼中楼回复，请改用其所在楼层的一级评论 %s", commentID, parentID)
+	}
+
+	thread, err := commentEl.ElementByJS(rod.Eval(`() => this.closest('.parent-comment')`))
+	if err != nil {
+		return nil, fmt.Errorf("未找到评论所在楼层: %w", err)
+	}
+
+	complete := expandThread(ctx, page, thread)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	detail, err = f.extractFeedDetail(page, feedID)
+	if err != nil {
+		return nil, err
+	}
+
+	top, _ := locateComment(detail.Comments.List, commentID)
+	if top == nil {
+		return nil, fmt.Errorf("页面数据中未找到评论 %s", commentID)
+	}
+
+	replies := top.SubComments
+	if replies == nil {
+		replies = []Comment{}
+	}
+	comment := *top
+	comment.SubComments = nil
+
+	if total, err := strconv.Atoi(top.SubCommentCount); err == nil && len(replies) < total {
+		logrus.Warnf("评论 %s 回复未取全: 已取 %d / 标注 %d", commentID, len(replies), total)
+	}
+
+	return &CommentReplies{
+		FeedID:   feedID,
+		Comment:  comment,
+		Replies:  replies,
+		Total:    top.SubCommentCount,
+		Complete: complete,
+	}, nil
+}
+
+// expandThread 反复点击楼层内的展开按钮，直到没有按钮为止。返回是否已展开完。
+func expandThread(ctx context.Context, page *rod.Page, thread *rod.Element) bool {
+	failures := 0
+
+	for round := 0; round < maxThreadExpandRounds; round++ {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		btn, text := nextThreadExpandButton(thread)
+		if btn == nil {
+			logrus.Infof("楼层已展开完毕，共点击 %d 次", round)
+			return true
+		}
+
+		if !clickElementWithHumanBehavior(ctx, page, btn, text) {
+			failures++
+			if failures >= maxThreadClickFailures {
+				logrus.Warnf("展开按钮连续点击失败 %d 次，停止展开", failures)
+				return false
+			}
+			continue
+		}
+		failures = 0
+
+		humanize.Delay(ctx, humanize.Reading)
+	}
+
+	logrus.Warnf("楼层展开超过 %d 次仍有按钮，停止", maxThreadExpandRounds)
+	return false
+}
+
+// nextThreadExpandButton 返回楼层内第一个可点的展开按钮，没有则返回 nil。
+func nextThreadExpandButton(thread *rod.Element) (*rod.Element, string) {
+	elements, err := thread.Elements(".show-more")
+	if err != nil {
+		return nil, ""
+	}
+
+	for _, el := range elements {
+		if !isElementClickable(el) {
+			continue
+		}
+		text, err := el.Text()
+		if err != nil {
+			continue
+		}
+		if isSafeExpandButton(el, text) {
+			return el, text
+		}
+	}
+	return nil, ""
+}
+
+// locateComment 在一级评论里查找 commentID。
+// 命中一级评论时返回该评论；命中楼中楼时返回 nil 和其所在楼层的 ID。
+func locateComment(list []Comment, commentID string) (*Comment, string) {
+	for i := range list {
+		if list[i].ID == commentID {
+			return &list[i], ""
+		}
+		for _, sub := range list[i].SubComments {
+			if sub.ID == commentID {
+				return nil, list[i].ID
+			}
+		}
+	}
+	return nil, ""
+}
@@ -0,0 +1,41 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLocateComment 一级评论直接命中；楼中楼要报出所在楼层，供调用方改用楼层 ID。
+func TestLocateComment(t *testing.T) {
+	list := []Comment{
+		{ID: "c1", SubComments: []Comment{{ID: "r1"}, {ID: "r2"}}},
+		{ID: "c2"},
+	}
+
+	t.Run("一级评论", func(t *testing.T) {
+		top, parentID := locateComment(list, "c2")
+		require.NotNil(t, top)
+		assert.Equal(t, "c2", top.ID)
+		assert.Empty(t, parentID)
+	})
+
+	t.Run("返回的是列表里的元素而非副本", func(t *testing.T) {
+		top, _ := locateComment(list, "c1")
+		require.NotNil(t, top)
+		assert.Len(t, top.SubComments, 2)
+	})
+
+	t.Run("楼中楼", func(t *testing.T) {
+		top, parentID := locateComment(list, "r2")
+		assert.Nil(t, top)
+		assert.Equal(t, "c1", parentID)
+	})
+
+	t.Run("不存在", func(t *testing.T) {
+		top, parentID := locateComment(list, "missing")
+		assert.Nil(t, top)
+		assert.Empty(t, parentID)
+	})
+}
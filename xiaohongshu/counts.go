@@ -0,0 +1,55 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhsutil"
+)
+
+// 下面几个 UnmarshalJSON 在解析页面数据时顺带填上 *CountNum，
+// 调用方按互动数排序、过滤时不用各自去处理「万」「+」。
+
+// UnmarshalJSON 解析后补全数值字段
+func (i *InteractInfo) UnmarshalJSON(data []byte) error {
+	type alias InteractInfo // 借别名避免递归调用本方法
+	if err := json.Unmarshal(data, (*alias)(i)); err != nil {
+		return err
+	}
+
+	i.LikedCountNum = parseCount(i.LikedCount)
+	i.SharedCountNum = parseCount(i.SharedCount)
+	i.CommentCountNum = parseCount(i.CommentCount)
+	i.CollectedCountNum = parseCount(i.CollectedCount)
+	return nil
+}
+
+// UnmarshalJSON 解析后补全数值字段，子评论走同一个方法
+func (c *Comment) UnmarshalJSON(data []byte) error {
+	type alias Comment
+	if err := json.Unmarshal(data, (*alias)(c)); err != nil {
+		return err
+	}
+
+	c.LikeCountNum = parseCount(c.LikeCount)
+	c.SubCommentCountNum = parseCount(c.SubCommentCount)
+	return nil
+}
+
+// UnmarshalJSON 解析后补全数值字段
+func (u *UserInteractions) UnmarshalJSON(data []byte) error {
+	type alias UserInteractions
+	if err := json.Unmarshal(data, (*alias)(u)); err != nil {
+		return err
+	}
+
+	u.CountNum = parseCount(u.Count)
+	return nil
+}
+
+// parseCount 解析不了时返回 nil，序列化时省略
+func parseCount(s string) *xhsutil.Count {
+	if c, ok := xhsutil.ParseCount(s); ok {
+		return &c
+	}
+	return nil
+}
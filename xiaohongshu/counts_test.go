@@ -0,0 +1,42 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhsutil"
+)
+
+func TestCountNumFields(t *testing.T) {
+	var detail FeedDetailResponse
+	err := json.Unmarshal([]byte(`{
+		"note": {"interactInfo": {"likedCount": "1.2万", "commentCount": "10+", "collectedCount": "88", "sharedCount": ""}},
+		"comments": {"list": [{
+			"likeCount": "3", "subCommentCount": "2",
+			"subComments": [{"likeCount": "1.1w"}]
+		}]}
+	}`), &detail)
+	require.NoError(t, err)
+
+	info := detail.Note.InteractInfo
+	assert.Equal(t, "1.2万", info.LikedCount, "原字符串保留")
+	assert.Equal(t, &xhsutil.Count{Value: 12000}, info.LikedCountNum)
+	assert.Equal(t, &xhsutil.Count{Value: 10}, info.CommentCountNum)
+	assert.Equal(t, &xhsutil.Count{Value: 88, Exact: true}, info.CollectedCountNum)
+	assert.Nil(t, info.SharedCountNum, "空串解析不了时为 nil")
+
+	c := detail.Comments.List[0]
+	assert.Equal(t, &xhsutil.Count{Value: 3, Exact: true}, c.LikeCountNum)
+	assert.Equal(t, &xhsutil.Count{Value: 2, Exact: true}, c.SubCommentCountNum)
+	assert.Equal(t, &xhsutil.Count{Value: 11000}, c.SubComments[0].LikeCountNum, "子评论同样补全")
+
+	var interactions []UserInteractions
+	require.NoError(t, json.Unmarshal([]byte(`[{"type": "fans", "count": "10万+"}]`), &interactions))
+	assert.Equal(t, &xhsutil.Count{Value: 100000}, interactions[0].CountNum)
+
+	out, err := json.Marshal(InteractInfo{LikedCount: "赞"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "likedCountNum")
+}
@@ -0,0 +1,408 @@
+package xiaohongshu
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/humanize"
+)
+
+// 编辑页和发布页是同一张表单，只是预先填好了原笔记。正文和话题在同一个编辑器里：
+// 改正文会清空编辑器后重新输入正文和话题；只改话题时只替换最后的话题行，
+// 正文里的 @、表情和排版原样保留，提交前确认正文没被碰过。
+
+// NoteEdit 对已发布笔记的部分修改。字符串为空表示不改；Tags 为 nil 不改，空切片表示清空话题
+type NoteEdit struct {
+	Title      string
+	Content    string
+	Tags       []string
+	Visibility string
+}
+
+// IsZero 没有要改的字段
+func (e NoteEdit) IsZero() bool {
+	return e.Title == "" && e.Content == "" && e.Tags == nil && e.Visibility == ""
+}
+
+// FieldChange 一个字段修改前后的值
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// EditResult 编辑结果，Changes 为空表示与原笔记一致，没有重新发布
+type EditResult struct {
+	NoteID         string        `json:"note_id"`
+	Changes        []FieldChange `json:"changes"`
+	FailedMentions []string      `json:"failed_mentions,omitempty"`
+}
+
+// noteState 编辑页上读到的笔记现状
+type noteState struct {
+	Title      string
+	Body       string
+	Tags       []string
+	Visibility string
+}
+
+// Edit 从笔记管理页进入编辑页，只改有变化的字段后重新发布
+func (a *NoteManageAction) Edit(ctx context.Context, noteID string, edit NoteEdit) (*EditResult, error) {
+	if edit.IsZero() {
+		return nil, errors.New("没有要修改的字段")
+	}
+
+	page := a.page.Context(ctx).Timeout(300 * time.Second)
+
+	note, err := findOwnNote(ctx, page, noteID)
+	if err != nil {
+		return nil, err
+	}
+	humanize.Delay(ctx, humanize.AfterNavigate)
+
+	titleElem, err := openEditPage(page, note)
+	if err != nil {
+		return nil, err
+	}
+	info, err := page.Info()
+	if err != nil {
+		return nil, errors.Wrap(err, "读取编辑页地址失败")
+	}
+	editURL := info.URL
+	humanize.Delay(ctx, humanize.AfterNavigate)
+
+	contentElem, err := getContentElement(page, contentElemTimeout)
+	if err != nil {
+		return nil, err
+	}
+	current, err := readNoteState(page, titleElem, contentElem)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffNoteEdit(current, edit)
+	result := &EditResult{NoteID: noteID, Changes: changes}
+	if len(changes) == 0 {
+		slog.Info("修改内容与原笔记一致，不重新发布", "note_id", noteID)
+		return result, nil
+	}
+
+	for _, c := range changes {
+		switch c.Field {
+		case "title":
+			if err := replaceText(ctx, titleElem, c.After); err != nil {
+				return nil, errors.Wrap(err, "修改标题失败")
+			}
+			humanize.Delay(ctx, humanize.AfterType)
+			if err := checkTitleMaxLength(page); err != nil {
+				return nil, err
+			}
+		case "visibility":
+			if err := selectVisibility(page, edit.Visibility); err != nil {
+				return nil, errors.Wrap(err, "修改可见范围失败")
+			}
+		}
+	}
+
+	switch {
+	case hasChange(changes, "content"):
+		tags := current.Tags
+		if edit.Tags != nil {
+			tags = edit.Tags
+		}
+		if result.FailedMentions, err = rewriteContent(ctx, contentElem, titleElem, edit.Content, tags); err != nil {
+			return nil, err
+		}
+	case hasChange(changes, "tags"):
+		if err := replaceTagLine(ctx, contentElem, current, normalizeTags(edit.Tags)); err != nil {
+			return nil, err
+		}
+	}
+	if hasChange(changes, "content") || hasChange(changes, "tags") {
+		humanize.Delay(ctx, humanize.AfterType)
+		if err := checkContentMaxLength(page); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := clickPublishButton(page); err != nil {
+		return nil, err
+	}
+	if err := waitLeavePage(page, editURL, 15*time.Second); err != nil {
+		return nil, err
+	}
+	slog.Info("笔记已修改并重新发布", "note_id", noteID, "changes", len(changes))
+	return result, nil
+}
+
+// openEditPage 在笔记卡片上点「编辑」，等编辑页的标题输入框出现
+func openEditPage(page *rod.Page, note postedNote) (*rod.Element, error) {
+	card, err := findNoteCard(page, note)
+	if err != nil {
+		return nil, err
+	}
+	if err := humanize.Hover(card); err != nil {
+		return nil, errors.Wrap(err, "移动到笔记卡片失败")
+	}
+	btn, err := card.Timeout(5*time.Second).ElementR("span, div, button", `^\s*编辑\s*$`)
+	if err != nil {
+		return nil, errors.Wrap(err, "未找到笔记的编辑按钮")
+	}
+	if err := humanize.Click(btn); err != nil {
+		return nil, errors.Wrap(err, "点击编辑按钮失败")
+	}
+
+	titleElem, err := page.Timeout(30 * time.Second).Element("div.d-input input")
+	if err != nil {
+		return nil, errors.Wrap(err, "等待编辑页加载失败")
+	}
+	if err := page.WaitDOMStable(time.Second, 0.1); err != nil {
+		slog.Warn("等待编辑页 DOM 稳定出现问题，继续尝试", "error", err)
+	}
+	return titleElem, nil
+}
+
+// readNoteState 读取编辑页上预填的标题、正文、话题和可见范围
+func readNoteState(page *rod.Page, titleElem, contentElem *rod.Element) (noteState, error) {
+	var state noteState
+
+	title, err := titleElem.Property("value")
+	if err != nil {
+		return state, errors.Wrap(err, "读取原标题失败")
+	}
+	state.Title = strings.TrimSpace(title.String())
+
+	text, err := contentElem.Text()
+	if err != nil {
+		return state, errors.Wrap(err, "读取原正文失败")
+	}
+	state.Body, state.Tags = splitContentTags(text)
+
+	state.Visibility = "公开可见"
+	if elem, err := page.Element("div.permission-card-wrapper div.d-select-content"); err == nil {
+		if v, err := elem.Text(); err == nil {
+			state.Visibility = visibilityFromText(v)
+		}
+	}
+	return state, nil
+}
+
+// topicToken 编辑器里的一个话题，显示为 #话题，部分版本带 [话题]# 后缀
+var topicToken = regexp.MustCompile(`^#([^#\s\[]+)(\[话题\]#?)?$`)
+
+// splitContentTags 正文最后一行全是话题时，把这行拆成话题，其余是正文
+func splitContentTags(text string) (string, []string) {
+	text = strings.TrimRight(text, " \t\r\n")
+	lines := strings.Split(text, "\n")
+	last := lines[len(lines)-1]
+
+	fields := strings.Fields(last)
+	if len(fields) == 0 {
+		return text, nil
+	}
+	tags := make([]string, 0, len(fields))
+	for _, f := range fields {
+		m := topicToken.FindStringSubmatch(f)
+		if m == nil {
+			return text, nil
+		}
+		tags = append(tags, m[1])
+	}
+	return strings.TrimRight(strings.Join(lines[:len(lines)-1], "\n"), " \t\r\n"), tags
+}
+
+// visibilityFromText 可见范围下拉的显示文本里找已知选项，认不出时按公开处理
+func visibilityFromText(text string) string {
+	for _, v := range []string{"仅自己可见", "仅互关好友可见"} {
+		if strings.Contains(text, v) {
+			return v
+		}
+	}
+	return "公开可见"
+}
+
+// diffNoteEdit 只保留与现状不同的字段；正文按发出后的显示文本比较
+func diffNoteEdit(current noteState, edit NoteEdit) []FieldChange {
+	var changes []FieldChange
+	if title := strings.TrimSpace(edit.Title); title != "" && title != current.Title {
+		changes = append(changes, FieldChange{Field: "title", Before: current.Title, After: title})
+	}
+	if edit.Content != "" {
+		if after := strings.TrimSpace(MentionText(edit.Content)); after != strings.TrimSpace(current.Body) {
+			changes = append(changes, FieldChange{Field: "content", Before: current.Body, After: after})
+		}
+	}
+	if edit.Tags != nil {
+		after := normalizeTags(edit.Tags)
+		if strings.Join(after, " ") != strings.Join(current.Tags, " ") {
+			changes = append(changes, FieldChange{Field: "tags", Before: formatTags(current.Tags), After: formatTags(after)})
+		}
+	}
+	if edit.Visibility != "" && edit.Visibility != current.Visibility {
+		changes = append(changes, FieldChange{Field: "visibility", Before: current.Visibility, After: edit.Visibility})
+	}
+	return changes
+}
+
+// normalizeTags 去掉前导 # 和空白，丢掉空话题
+func normalizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(t), "#")); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func formatTags(tags []string) string {
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = "#" + t
+	}
+	return strings.Join(out, " ")
+}
+
+func hasChange(changes []FieldChange, field string) bool {
+	for _, c := range changes {
+		if c.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceText 全选后删除，再输入新内容
+func replaceText(ctx context.Context, elem *rod.Element, text string) error {
+	if err := clearElement(elem); err != nil {
+		return err
+	}
+	return humanize.Type(ctx, elem, text)
+}
+
+// clearElement 全选元素内的文本并删除
+func clearElement(elem *rod.Element) error {
+	if err := humanize.Click(elem); err != nil {
+		return errors.Wrap(err, "点击输入框失败")
+	}
+	if err := elem.SelectAllText(); err != nil {
+		return errors.Wrap(err, "全选文本失败")
+	}
+	ka, err := elem.KeyActions()
+	if err != nil {
+		return errors.Wrap(err, "创建键盘操作失败")
+	}
+	if err := ka.Press(input.Backspace).Do(); err != nil {
+		return errors.Wrap(err, "删除原文本失败")
+	}
+	return nil
+}
+
+// rewriteContent 清空编辑器后按发布时的顺序重新输入正文和话题
+func rewriteContent(ctx context.Context, contentElem, titleElem *rod.Element, body string, tags []string) ([]string, error) {
+	if err := clearElement(contentElem); err != nil {
+		return nil, errors.Wrap(err, "清空正文失败")
+	}
+	failedMentions, err := typeWithMentions(ctx, contentElem, body, creatorMentionPopup)
+	if err != nil {
+		return nil, errors.Wrap(err, "输入正文失败")
+	}
+	if err := waitAndClickTitleInput(titleElem); err != nil {
+		return nil, err
+	}
+	if err := inputTags(ctx, contentElem, normalizeTags(tags)); err != nil {
+		return nil, err
+	}
+	return failedMentions, nil
+}
+
+// replaceTagLine 只改话题：选中编辑器最后的话题行删掉，在原位置输入新话题；原来没有话题时接在正文后面。
+// 正文不重新输入，改完后读回来与原正文比对，不一致就报错不提交，免得弄丢 @ 和排版
+func replaceTagLine(ctx context.Context, contentElem *rod.Element, current noteState, tags []string) error {
+	res, err := contentElem.Eval(`(hasTags) => {
+		this.focus();
+		const blocks = Array.from(this.children).filter(el => el.textContent.trim() !== '');
+		const sel = window.getSelection();
+		const range = document.createRange();
+		if (!hasTags || blocks.length === 0) {
+			range.selectNodeContents(this);
+			range.collapse(false);
+			sel.removeAllRanges();
+			sel.addRange(range);
+			return 0;
+		}
+		const line = blocks[blocks.length - 1];
+		range.selectNodeContents(line);
+		sel.removeAllRanges();
+		sel.addRange(range);
+		// 话题行前面的空行，清空话题时一起删掉
+		let empty = 0;
+		for (let el = line.previousElementSibling; el && el.textContent.trim() === ''; el = el.previousElementSibling) empty++;
+		return empty;
+	}`, len(current.Tags) > 0)
+	if err != nil {
+		return errors.Wrap(err, "定位话题行失败")
+	}
+
+	switch {
+	case len(current.Tags) == 0:
+		if err := inputTags(ctx, contentElem, tags); err != nil {
+			return err
+		}
+	default:
+		presses := 1
+		if len(tags) == 0 {
+			// 删掉话题后再退掉空的话题行和它前面的空行
+			presses += 1 + res.Value.Int()
+		}
+		for i := 0; i < presses; i++ {
+			ka, err := contentElem.KeyActions()
+			if err != nil {
+				return errors.Wrap(err, "创建键盘操作失败")
+			}
+			if err := ka.Press(input.Backspace).Do(); err != nil {
+				return errors.Wrap(err, "删除原话题失败")
+			}
+		}
+		for _, tag := range tags {
+			if err := inputTag(ctx, contentElem, tag); err != nil {
+				return errors.Wrapf(err, "输入标签[%s]失败", tag)
+			}
+		}
+	}
+
+	text, err := contentElem.Text()
+	if err != nil {
+		return errors.Wrap(err, "读取修改后的正文失败")
+	}
+	if body, _ := splitContentTags(text); !sameBody(body, current.Body) {
+		return errors.New("只改话题时正文被意外改动，已放弃提交，原笔记未变")
+	}
+	return nil
+}
+
+// sameBody 忽略首尾空白比较正文
+func sameBody(a, b string) bool {
+	return strings.TrimSpace(a) == strings.TrimSpace(b)
+}
+
+// waitLeavePage 点发布后等页面离开编辑页，判定方式与 waitPublishSuccess 一致
+func waitLeavePage(page *rod.Page, from string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if info, err := page.Info(); err == nil && info.URL != from {
+			slog.Info("修改已提交，已跳转离开编辑页", "url", info.URL)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("修改未确认成功：点击发布后未跳转离开编辑页（可能校验未过或被拦截）")
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
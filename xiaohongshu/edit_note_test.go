@@ -0,0 +1,55 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitContentTags(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		body string
+		tags []string
+	}{
+		{name: "末行是话题", text: "周末去了新开的店\n味道不错\n#美食 #探店[话题]# \n", body: "周末去了新开的店\n味道不错", tags: []string{"美食", "探店"}},
+		{name: "末行夹着普通文字", text: "第一行\n今天 #美食 真好", body: "第一行\n今天 #美食 真好"},
+		{name: "没有话题", text: "只有正文", body: "只有正文"},
+		{name: "只有话题", text: "#旅行", body: "", tags: []string{"旅行"}},
+		{name: "空编辑器", text: "", body: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, tags := splitContentTags(tt.text)
+			assert.Equal(t, tt.body, body)
+			assert.Equal(t, tt.tags, tags)
+		})
+	}
+}
+
+func TestDiffNoteEdit(t *testing.T) {
+	current := noteState{Title: "周末探店", Body: "感谢 @小红薯 推荐", Tags: []string{"美食"}, Visibility: "公开可见"}
+
+	assert.Empty(t, diffNoteEdit(current, NoteEdit{
+		Title:      " 周末探店 ",
+		Content:    "感谢 @{小红薯} 推荐",
+		Tags:       []string{"#美食"},
+		Visibility: "公开可见",
+	}), "与现状一致时没有修改")
+
+	assert.Equal(t, []FieldChange{
+		{Field: "title", Before: "周末探店", After: "周末探店（更新）"},
+		{Field: "tags", Before: "#美食", After: ""},
+		{Field: "visibility", Before: "公开可见", After: "仅自己可见"},
+	}, diffNoteEdit(current, NoteEdit{Title: "周末探店（更新）", Tags: []string{}, Visibility: "仅自己可见"}), "空切片表示清空话题")
+
+	assert.Equal(t, []FieldChange{{Field: "content", Before: "感谢 @小红薯 推荐", After: "感谢推荐"}},
+		diffNoteEdit(current, NoteEdit{Content: "感谢推荐"}))
+}
+
+func TestVisibilityFromText(t *testing.T) {
+	assert.Equal(t, "仅自己可见", visibilityFromText(" 仅自己可见 \n"))
+	assert.Equal(t, "公开可见", visibilityFromText("公开可见"))
+	assert.Equal(t, "公开可见", visibilityFromText(""))
+}
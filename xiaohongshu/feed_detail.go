@@ -105,22 +105,7 @@ func (f *FeedDetailAction) GetFeedDetailWithConfig(ctx context.Context, feedID,
 	logrus.Infof("配置: 点击更多=%v, 回复阈值=%d, 最大评论数=%d, 滚动速度=%s",
 		config.ClickMoreReplies, config.MaxRepliesThreshold, config.MaxCommentItems, config.ScrollSpeed)
 
-	// 使用retry-go处理页面导航和DOM稳定等待
-	err := retry.Do(
-		func() error {
-			page.MustNavigate(url)
-			page.MustWaitDOMStable()
-			return nil
-		},
-		retry.Attempts(3),
-		retry.Delay(500*time.Millisecond),
-		retry.MaxJitter(1000*time.Millisecond),
-		retry.OnRetry(func(n uint, err error) {
-			logrus.Debugf("页面导航重试 #%d: %v", n, err)
-		}),
-	)
-	if err != nil {
-		logrus.Errorf("页面导航失败: %v", err)
+	if err := navigateFeedDetail(page, url); err != nil {
 		return nil, err
 	}
 	humanize.Delay(ctx, humanize.AfterNavigate)
@@ -143,6 +128,28 @@ func (f *FeedDetailAction) GetFeedDetailWithConfig(ctx context.Context, feedID,
 	return f.extractFeedDetail(page, feedID)
 }
 
+// navigateFeedDetail 打开详情页并等 DOM 稳定，失败时重试。
+func navigateFeedDetail(page *rod.Page, url string) error {
+	// 使用retry-go处理页面导航和DOM稳定等待
+	err := retry.Do(
+		func() error {
+			page.MustNavigate(url)
+			page.MustWaitDOMStable()
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(500*time.Millisecond),
+		retry.MaxJitter(1000*time.Millisecond),
+		retry.OnRetry(func(n uint, err error) {
+			logrus.Debugf("页面导航重试 #%d: %v", n, err)
+		}),
+	)
+	if err != nil {
+		logrus.Errorf("页面导航失败: %v", err)
+	}
+	return err
+}
+
 // ========== 评论加载器 ==========
 
 type commentLoader struct {
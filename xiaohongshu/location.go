@@ -0,0 +1,239 @@
+package xiaohongshu
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/humanize"
+)
+
+// LocationCandidate 地点搜索的一个候选
+type LocationCandidate struct {
+	Name    string `json:"name"`
+	Address string `json:"address,omitempty"`
+}
+
+func (c LocationCandidate) String() string {
+	if c.Address == "" {
+		return c.Name
+	}
+	return c.Name + "（" + c.Address + "）"
+}
+
+// LocationError 搜不到地点或没有足够确定的匹配，带上候选让调用方换个更准确的写法
+type LocationError struct {
+	Query      string
+	Candidates []LocationCandidate
+}
+
+func (e *LocationError) Error() string {
+	if len(e.Candidates) == 0 {
+		return fmt.Sprintf("没有搜索到地点 %q", e.Query)
+	}
+	names := make([]string, len(e.Candidates))
+	for i, c := range e.Candidates {
+		names[i] = c.String()
+	}
+	return fmt.Sprintf("地点 %q 没有足够确定的匹配，候选: %s", e.Query, strings.Join(names, "、"))
+}
+
+const (
+	// locationMinScore 最佳候选至少要有这个分数才算匹配
+	locationMinScore = 0.75
+	// locationMinGap 最佳与次佳至少拉开这么多，否则视为有歧义（如连锁店的多家分店）
+	locationMinGap = 0.1
+)
+
+// pickLocation 给候选打分，返回可信的最佳候选下标；没有可信匹配返回 -1
+func pickLocation(query string, candidates []LocationCandidate) int {
+	best, second := -1, 0.0
+	bestScore := 0.0
+	for i, c := range candidates {
+		s := scoreLocation(query, c.Name)
+		// 查询词比地点名长，多出来的多半是地址（"星巴克 建国门外大街"），再按名称+地址的覆盖度算一次
+		if c.Address != "" && len([]rune(normalizeLocation(query))) > len([]rune(normalizeLocation(c.Name))) {
+			s = max(s, bigramCoverage(normalizeLocation(query), normalizeLocation(c.Name+c.Address))*0.9)
+		}
+		if s > bestScore {
+			second = bestScore
+			best, bestScore = i, s
+		} else if s > second {
+			second = s
+		}
+	}
+	if best < 0 || bestScore < locationMinScore || bestScore-second < locationMinGap {
+		return -1
+	}
+	return best
+}
+
+// scoreLocation 查询词与地点名的相似度，0~1。
+// 去掉空白和标点后比较：完全相同 1 分；一方包含另一方按长度比例给分；其余用二元组 Dice 系数。
+func scoreLocation(query, name string) float64 {
+	q, n := normalizeLocation(query), normalizeLocation(name)
+	if q == "" || n == "" {
+		return 0
+	}
+	if q == n {
+		return 1
+	}
+
+	score := diceBigrams(q, n)
+	if strings.Contains(n, q) || strings.Contains(q, n) {
+		short, long := len([]rune(q)), len([]rune(n))
+		if short > long {
+			short, long = long, short
+		}
+		score = max(score, 0.5+0.5*float64(short)/float64(long))
+	}
+	return score
+}
+
+// normalizeLocation 转小写并去掉空白和标点，"星巴克 (国贸店)" 与 "星巴克国贸店" 视为相同
+func normalizeLocation(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// diceBigrams 按字符二元组计算 Dice 系数
+func diceBigrams(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < 2 || len(rb) < 2 {
+		return 0
+	}
+	return 2 * float64(commonBigrams(ra, rb)) / float64(len(ra)-1+len(rb)-1)
+}
+
+// bigramCoverage a 的二元组有多大比例出现在 b 里
+func bigramCoverage(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < 2 || len(rb) < 2 {
+		return 0
+	}
+	return float64(commonBigrams(ra, rb)) / float64(len(ra)-1)
+}
+
+// commonBigrams 两串共有的字符二元组个数（按多重集计）
+func commonBigrams(a, b []rune) int {
+	counts := make(map[string]int, len(a))
+	for i := 0; i+1 < len(a); i++ {
+		counts[string(a[i:i+2])]++
+	}
+	common := 0
+	for i := 0; i+1 < len(b); i++ {
+		bg := string(b[i : i+2])
+		if counts[bg] > 0 {
+			counts[bg]--
+			common++
+		}
+	}
+	return common
+}
+
+// setLocation 打开「添加地点」，搜索 query 并选中可信的最佳匹配；没有则收起下拉并返回 *LocationError
+func setLocation(ctx context.Context, page *rod.Page, query string) error {
+	if query == "" {
+		return nil
+	}
+
+	trigger, err := page.Timeout(5*time.Second).ElementR("div, span", `^\s*添加地点\s*$`)
+	if err != nil {
+		return errors.Wrap(err, "未找到添加地点入口")
+	}
+	if err := humanize.Click(trigger); err != nil {
+		return errors.Wrap(err, "点击添加地点失败")
+	}
+	humanize.Delay(ctx, humanize.AfterClick)
+
+	searchInput, err := page.Timeout(5 * time.Second).Element(`input[placeholder*="地点"], input[placeholder*="位置"]`)
+	if err != nil {
+		return errors.Wrap(err, "未找到地点搜索框")
+	}
+	if err := humanize.Type(ctx, searchInput, query); err != nil {
+		return errors.Wrap(err, "输入地点失败")
+	}
+	time.Sleep(2 * time.Second) // 技术等待：等地点搜索结果刷新
+
+	items, candidates := locationOptions(page)
+	idx := pickLocation(query, candidates)
+	if idx < 0 {
+		closeLocationDropdown(searchInput)
+		return &LocationError{Query: query, Candidates: candidates}
+	}
+
+	if err := humanize.Click(items[idx]); err != nil {
+		return errors.Wrap(err, "点击地点选项失败")
+	}
+	slog.Info("已添加地点", "query", query, "location", candidates[idx].String())
+	humanize.Delay(ctx, humanize.AfterClick)
+	return nil
+}
+
+// locationOptions 读取可见的地点下拉选项：第一行是地点名，第二行（如果有）是地址
+func locationOptions(page *rod.Page) ([]*rod.Element, []LocationCandidate) {
+	elems, err := page.Elements(".d-select-dropdown .d-option, .d-dropdown .d-option, .d-grid-item .item")
+	if err != nil {
+		return nil, nil
+	}
+
+	var items []*rod.Element
+	var candidates []LocationCandidate
+	for _, el := range elems {
+		if visible, _ := el.Visible(); !visible {
+			continue
+		}
+		text, err := el.Text()
+		if err != nil {
+			continue
+		}
+		c := parseLocationOption(text)
+		if c.Name == "" {
+			continue
+		}
+		items = append(items, el)
+		candidates = append(candidates, c)
+	}
+	return items, candidates
+}
+
+// parseLocationOption 选项文本按行拆成名称和地址
+func parseLocationOption(text string) LocationCandidate {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	var c LocationCandidate
+	if len(lines) > 0 {
+		c.Name = lines[0]
+	}
+	if len(lines) > 1 {
+		c.Address = lines[1]
+	}
+	return c
+}
+
+// closeLocationDropdown 没选中时按 Esc 收起下拉，避免挡住后续表单
+func closeLocationDropdown(elem *rod.Element) {
+	ka, err := elem.KeyActions()
+	if err != nil {
+		return
+	}
+	if err := ka.Press(input.Escape).Do(); err != nil {
+		slog.Warn("收起地点下拉失败", "error", err)
+	}
+}
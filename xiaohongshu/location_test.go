@@ -0,0 +1,83 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickLocation(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		candidates []LocationCandidate
+		want       int
+	}{
+		{
+			name:  "去掉标点后完全一致",
+			query: "星巴克 国贸店",
+			candidates: []LocationCandidate{
+				{Name: "星巴克(三里屯店)", Address: "朝阳区三里屯路"},
+				{Name: "星巴克(国贸店)", Address: "朝阳区建国门外大街1号"},
+			},
+			want: 1,
+		},
+		{
+			name:  "查询词是地点名的主体",
+			query: "故宫博物院",
+			candidates: []LocationCandidate{
+				{Name: "故宫博物院-午门", Address: "东城区景山前街4号"},
+				{Name: "景山公园", Address: "西城区景山西街44号"},
+			},
+			want: 0,
+		},
+		{
+			name:  "连锁店多家分店难分高下",
+			query: "星巴克",
+			candidates: []LocationCandidate{
+				{Name: "星巴克(国贸店)"},
+				{Name: "星巴克(三里屯店)"},
+			},
+			want: -1,
+		},
+		{
+			name:  "地址帮助区分",
+			query: "星巴克 建国门外大街",
+			candidates: []LocationCandidate{
+				{Name: "星巴克", Address: "朝阳区建国门外大街1号"},
+				{Name: "星巴克", Address: "海淀区中关村大街"},
+			},
+			want: 0,
+		},
+		{
+			name:       "分数不够",
+			query:      "外婆家",
+			candidates: []LocationCandidate{{Name: "南京大牌档"}, {Name: "绿茶餐厅"}},
+			want:       -1,
+		},
+		{name: "没有候选", query: "外婆家", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, pickLocation(tt.query, tt.candidates))
+		})
+	}
+}
+
+func TestScoreLocation(t *testing.T) {
+	assert.Equal(t, 1.0, scoreLocation("Wagas 国贸", "wagas(国贸)"), "忽略大小写和标点")
+	assert.Equal(t, 0.0, scoreLocation("", "星巴克"))
+	assert.Greater(t, scoreLocation("西湖", "西湖风景名胜区"), scoreLocation("西湖", "西溪湿地"))
+}
+
+func TestLocationError(t *testing.T) {
+	err := &LocationError{Query: "星巴克", Candidates: []LocationCandidate{{Name: "星巴克(国贸店)", Address: "建国门外大街1号"}, {Name: "星巴克(三里屯店)"}}}
+	assert.Equal(t, `地点 "星巴克" 没有足够确定的匹配，候选: 星巴克(国贸店)（建国门外大街1号）、星巴克(三里屯店)`, err.Error())
+
+	assert.Equal(t, `没有搜索到地点 "外婆家"`, (&LocationError{Query: "外婆家"}).Error())
+}
+
+func TestParseLocationOption(t *testing.T) {
+	assert.Equal(t, LocationCandidate{Name: "星巴克(国贸店)", Address: "建国门外大街1号"}, parseLocationOption("  星巴克(国贸店)\n\n建国门外大街1号\n1.2km"))
+	assert.Equal(t, LocationCandidate{}, parseLocationOption(" \n "))
+}
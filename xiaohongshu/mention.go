@@ -0,0 +1,184 @@
+package xiaohongshu
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/humanize"
+)
+
+// 正文里用 @{昵称} 标记要 @ 的用户。输入时敲 @ 和昵称，再从联想弹窗里点选对应用户，
+// 这样发出去的才是带链接的 @，而不是一段普通文本。
+
+// segment 正文拆分后的一段：普通文本或一个 @ 用户
+type segment struct {
+	Text    string
+	Mention string // 非空表示这一段是 @ 用户，值为昵称
+}
+
+// parseMentions 把正文拆成普通文本和 @{昵称} 段；花括号不成对或昵称为空时按原文保留
+func parseMentions(content string) []segment {
+	var segs []segment
+	var text strings.Builder
+
+	for rest := content; rest != ""; {
+		i := strings.Index(rest, "@{")
+		if i < 0 {
+			text.WriteString(rest)
+			break
+		}
+		end := strings.IndexByte(rest[i+2:], '}')
+		name := ""
+		if end >= 0 {
+			name = strings.TrimSpace(rest[i+2 : i+2+end])
+		}
+		if name == "" || strings.ContainsAny(name, "\n@") {
+			text.WriteString(rest[:i+2])
+			rest = rest[i+2:]
+			continue
+		}
+
+		text.WriteString(rest[:i])
+		if text.Len() > 0 {
+			segs = append(segs, segment{Text: text.String()})
+			text.Reset()
+		}
+		segs = append(segs, segment{Mention: name})
+		rest = rest[i+2+end+1:]
+	}
+
+	if text.Len() > 0 {
+		segs = append(segs, segment{Text: text.String()})
+	}
+	return segs
+}
+
+// MentionText 正文发出后的显示文本：@{昵称} 显示为 @昵称
+func MentionText(content string) string {
+	var b strings.Builder
+	for _, seg := range parseMentions(content) {
+		if seg.Mention != "" {
+			b.WriteString("@" + seg.Mention)
+		} else {
+			b.WriteString(seg.Text)
+		}
+	}
+	return b.String()
+}
+
+// mentionPopup 联想弹窗的选择器，创作中心和笔记详情页的评论框不一样
+type mentionPopup struct {
+	container string
+	item      string
+}
+
+var (
+	creatorMentionPopup = mentionPopup{container: "#creator-editor-mention-container", item: ".item"}
+	commentMentionPopup = mentionPopup{container: ".mention-container, .at-user-container", item: ".mention-item, .user-item, .item"}
+)
+
+// typeWithMentions 输入正文，遇到 @{昵称} 时从联想弹窗里选中该用户。
+// 没选中的 @ 保留为普通文本继续输入，昵称汇总到返回值里，由调用方上报。
+func typeWithMentions(ctx context.Context, elem *rod.Element, content string, popup mentionPopup) ([]string, error) {
+	var failed []string
+	for _, seg := range parseMentions(content) {
+		if seg.Mention == "" {
+			if err := humanize.Type(ctx, elem, seg.Text); err != nil {
+				return failed, err
+			}
+			continue
+		}
+
+		ok, err := inputMention(ctx, elem, seg.Mention, popup)
+		if err != nil {
+			return failed, errors.Wrapf(err, "输入 @%s 失败", seg.Mention)
+		}
+		if !ok {
+			failed = append(failed, seg.Mention)
+		}
+	}
+	return failed, nil
+}
+
+// inputMention 敲 @昵称 并点选联想结果里昵称完全一致的用户；找不到时按 Esc 收起弹窗，返回 false。
+// 不用补空格收起：多出的字符会让发出的文本和 MentionText 对不上
+func inputMention(ctx context.Context, elem *rod.Element, nickname string, popup mentionPopup) (bool, error) {
+	if err := humanize.Type(ctx, elem, "@"); err != nil {
+		return false, err
+	}
+	time.Sleep(300 * time.Millisecond) // 技术等待：等联想弹窗出现
+	if err := humanize.Type(ctx, elem, nickname); err != nil {
+		return false, err
+	}
+	time.Sleep(1500 * time.Millisecond) // 技术等待：等联想结果按昵称刷新
+
+	item := findMentionItem(elem.Page(), popup, nickname)
+	if item == nil {
+		slog.Warn("@ 用户未在联想列表中找到，按普通文本输入", "nickname", nickname)
+		closeMentionPopup(elem)
+		return false, nil
+	}
+
+	if err := humanize.Click(item); err != nil {
+		return false, errors.Wrap(err, "点击 @ 联想选项失败")
+	}
+	slog.Info("已选中 @ 用户", "nickname", nickname)
+	time.Sleep(500 * time.Millisecond) // 技术等待：等 @ 节点插入完成
+	return true, nil
+}
+
+// closeMentionPopup 按 Esc 收起联想弹窗，不改动已输入的文本
+func closeMentionPopup(elem *rod.Element) {
+	ka, err := elem.KeyActions()
+	if err != nil {
+		return
+	}
+	if err := ka.Press(input.Escape).Do(); err != nil {
+		slog.Warn("收起 @ 联想弹窗失败", "error", err)
+	}
+	time.Sleep(200 * time.Millisecond) // 技术等待：等弹窗收起
+}
+
+// findMentionItem 在可见的联想弹窗里找昵称完全一致的选项
+func findMentionItem(page *rod.Page, popup mentionPopup, nickname string) *rod.Element {
+	containers, err := page.Elements(popup.container)
+	if err != nil {
+		return nil
+	}
+	for _, container := range containers {
+		if visible, _ := container.Visible(); !visible {
+			continue
+		}
+		items, err := container.Elements(popup.item)
+		if err != nil {
+			continue
+		}
+		texts := make([]string, len(items))
+		for i, item := range items {
+			texts[i], _ = item.Text()
+		}
+		if i := matchMentionItem(texts, nickname); i >= 0 {
+			return items[i]
+		}
+	}
+	return nil
+}
+
+// matchMentionItem 选项文本里有一行与昵称完全相同才算命中，避免 @ 到同名前缀的其他人。
+// 选项通常是「昵称 / 小红书号 / 粉丝数」多行，昵称那行可能带 @ 前缀。
+func matchMentionItem(texts []string, nickname string) int {
+	want := strings.TrimSpace(nickname)
+	for i, text := range texts {
+		for _, line := range strings.Split(text, "\n") {
+			if strings.TrimPrefix(strings.TrimSpace(line), "@") == want {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,48 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []segment
+	}{
+		{name: "无 @", content: "今天天气不错", want: []segment{{Text: "今天天气不错"}}},
+		{
+			name:    "正文中的 @",
+			content: "感谢 @{小红薯} 和@{ 阿花 }的推荐",
+			want:    []segment{{Text: "感谢 "}, {Mention: "小红薯"}, {Text: " 和"}, {Mention: "阿花"}, {Text: "的推荐"}},
+		},
+		{name: "开头和结尾", content: "@{a}@{b}", want: []segment{{Mention: "a"}, {Mention: "b"}}},
+		{name: "普通 @ 不处理", content: "邮箱 me@example.com", want: []segment{{Text: "邮箱 me@example.com"}}},
+		{name: "花括号不成对", content: "@{没有结尾", want: []segment{{Text: "@{没有结尾"}}},
+		{name: "空昵称", content: "@{} 和 @{  }", want: []segment{{Text: "@{} 和 @{  }"}}},
+		{name: "昵称跨行", content: "@{a\nb}", want: []segment{{Text: "@{a\nb}"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseMentions(tt.content))
+		})
+	}
+}
+
+func TestMentionText(t *testing.T) {
+	assert.Equal(t, "感谢 @小红薯 的推荐", MentionText("感谢 @{小红薯} 的推荐"))
+	assert.Equal(t, "@{", MentionText("@{"))
+}
+
+func TestMatchMentionItem(t *testing.T) {
+	items := []string{
+		"小红薯官方\n小红书号：1\n粉丝 100万",
+		"@小红薯\n小红书号：2",
+		"小红薯",
+	}
+	assert.Equal(t, 1, matchMentionItem(items, "小红薯"), "取第一个昵称完全一致的，允许 @ 前缀")
+	assert.Equal(t, 0, matchMentionItem(items, " 小红薯官方 "))
+	assert.Equal(t, -1, matchMentionItem(items, "小红"), "前缀相同不算命中")
+}
@@ -0,0 +1,277 @@
+package xiaohongshu
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/humanize"
+)
+
+// NoteManageAction 创作中心笔记管理页上的操作
+type NoteManageAction struct {
+	page *rod.Page
+}
+
+// NewNoteManageAction 创建笔记管理操作
+func NewNoteManageAction(page *rod.Page) *NoteManageAction {
+	return &NoteManageAction{page: page}
+}
+
+// DeletedNote 已删除的笔记
+type DeletedNote struct {
+	NoteID string `json:"note_id"`
+	Title  string `json:"title"`
+}
+
+// Delete 删除自己的笔记。笔记必须能在笔记管理页的列表里找到（即属于当前账号），删除后重新打开列表确认已消失
+func (a *NoteManageAction) Delete(ctx context.Context, noteID string) (*DeletedNote, error) {
+	page := a.page.Context(ctx).Timeout(120 * time.Second)
+
+	note, err := findOwnNote(ctx, page, noteID)
+	if err != nil {
+		return nil, err
+	}
+	humanize.Delay(ctx, humanize.AfterNavigate)
+
+	card, err := findNoteCard(page, note)
+	if err != nil {
+		return nil, err
+	}
+	if err := humanize.Hover(card); err != nil {
+		return nil, errors.Wrap(err, "移动到笔记卡片失败")
+	}
+	btn, err := card.Timeout(5*time.Second).ElementR("span, div, button", `^\s*删除\s*$`)
+	if err != nil {
+		return nil, errors.Wrap(err, "未找到笔记的删除按钮")
+	}
+	if err := humanize.Click(btn); err != nil {
+		return nil, errors.Wrap(err, "点击删除按钮失败")
+	}
+
+	modal, err := waitForModal(page, "删除", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	confirm, err := modal.Timeout(5*time.Second).ElementR("button", `^\s*(确定|确认|删除)\s*$`)
+	if err != nil {
+		return nil, errors.Wrap(err, "未找到删除确认按钮")
+	}
+	if err := humanize.Click(confirm); err != nil {
+		return nil, errors.Wrap(err, "点击删除确认按钮失败")
+	}
+	if err := waitModalClosed(page, "删除", 10*time.Second); err != nil {
+		return nil, errors.Wrap(err, "删除未完成")
+	}
+
+	if err := waitNoteGone(ctx, page, noteID); err != nil {
+		return nil, err
+	}
+	slog.Info("笔记已删除", "note_id", noteID, "title", note.Title)
+	return &DeletedNote{NoteID: noteID, Title: note.Title}, nil
+}
+
+// postedNotes 打开笔记管理页并解析笔记列表接口
+func postedNotes(page *rod.Page) ([]postedNote, error) {
+	body, err := capturePostedNotes(page, 20*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return parsePostedNotes(body)
+}
+
+// ownNoteMaxPages 按 ID 找笔记时最多加载的列表页数，列表很长时不至于一直滚下去
+const ownNoteMaxPages = 50
+
+// findOwnNote 打开笔记管理页按 ID 找笔记，当前页没有就往下滚动加载下一页，直到找到或列表到底。
+// 找到时笔记卡片已在页面上，可以直接操作
+func findOwnNote(ctx context.Context, page *rod.Page, noteID string) (postedNote, error) {
+	bodies, stop := watchPostedNotes(page)
+	defer stop()
+
+	if err := page.Navigate(urlOfNoteManager); err != nil {
+		return postedNote{}, errors.Wrap(err, "打开笔记管理失败")
+	}
+
+	timeout := 20 * time.Second
+	for n := 0; n < ownNoteMaxPages; n++ {
+		var body []byte
+		select {
+		case <-ctx.Done():
+			return postedNote{}, ctx.Err()
+		case body = <-bodies:
+		case <-time.After(timeout):
+			if n == 0 {
+				return postedNote{}, errors.New("等待笔记列表接口超时")
+			}
+			// 滚动后没有再请求下一页，当作列表已到底
+			return postedNote{}, notOwnNoteError(noteID)
+		}
+
+		notes, more, err := parsePostedNotesPage(body)
+		if err != nil {
+			return postedNote{}, err
+		}
+		if i := findPostedNote(notes, noteID); i >= 0 {
+			return notes[i], nil
+		}
+		if !more {
+			break
+		}
+		humanize.Delay(ctx, humanize.BetweenScroll)
+		scrollNoteList(page)
+		timeout = 10 * time.Second
+	}
+	return postedNote{}, notOwnNoteError(noteID)
+}
+
+func notOwnNoteError(noteID string) error {
+	return errors.Errorf("笔记 %s 不在当前账号的笔记管理列表中（只能操作自己发布的笔记）", noteID)
+}
+
+// watchPostedNotes 持续截获笔记列表接口的响应体，直到调用 stop
+func watchPostedNotes(page *rod.Page) (<-chan []byte, func()) {
+	p, cancel := page.WithCancel()
+	bodies := make(chan []byte, ownNoteMaxPages)
+
+	pending := map[proto.NetworkRequestID]bool{}
+	wait := p.EachEvent(func(e *proto.NetworkResponseReceived) {
+		if strings.Contains(e.Response.URL, postedNotesAPI) {
+			pending[e.RequestID] = true
+		}
+	}, func(e *proto.NetworkLoadingFinished) {
+		if !pending[e.RequestID] {
+			return
+		}
+		delete(pending, e.RequestID)
+		body, err := responseBody(p, e.RequestID)
+		if err != nil {
+			slog.Warn("读取笔记列表响应失败", "error", err)
+			return
+		}
+		select {
+		case bodies <- body:
+		default:
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
+	return bodies, func() {
+		cancel()
+		<-done
+	}
+}
+
+// scrollNoteList 把最后一张笔记卡片滚进视口，再在列表上滚几格，触发加载下一页
+func scrollNoteList(page *rod.Page) {
+	cards, err := page.Elements(noteCardSelector)
+	if err != nil || len(cards) == 0 {
+		return
+	}
+	last := cards[len(cards)-1]
+	if err := last.ScrollIntoView(); err != nil {
+		return
+	}
+	// 指针落在列表上，滚轮才作用于列表所在的滚动容器
+	if err := humanize.Hover(last); err != nil {
+		return
+	}
+	for i := 0; i < 3; i++ {
+		if err := page.Mouse.Scroll(0, scrollNotchSize(), 1); err != nil {
+			return
+		}
+		time.Sleep(scrollNotchInterval())
+	}
+}
+
+// findPostedNote 按笔记 ID 在列表里找
+func findPostedNote(notes []postedNote, noteID string) int {
+	for i, n := range notes {
+		if n.ID == noteID {
+			return i
+		}
+	}
+	return -1
+}
+
+// waitNoteGone 重新打开列表，直到笔记不在里面；列表有缓存，删除后可能要刷新几次
+func waitNoteGone(ctx context.Context, page *rod.Page, noteID string) error {
+	for attempt := 0; attempt < resolveNoteAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(resolveNoteInterval):
+		}
+
+		notes, err := postedNotes(page)
+		if err != nil {
+			slog.Warn("删除后读取笔记列表失败", "error", err)
+			continue
+		}
+		if findPostedNote(notes, noteID) < 0 {
+			return nil
+		}
+	}
+	return errors.Errorf("已点击删除，但笔记 %s 仍在笔记列表中，请稍后确认", noteID)
+}
+
+// noteCardSelector 笔记管理页上的笔记卡片
+const noteCardSelector = `div.note, [class*="note-item"]`
+
+// findNoteCard 找笔记在页面上的卡片：优先按埋点属性里的笔记 ID，其次按标题且只命中一张
+func findNoteCard(page *rod.Page, note postedNote) (*rod.Element, error) {
+	card, err := page.Timeout(10 * time.Second).ElementByJS(rod.Eval(`(id) => {
+		for (const el of document.querySelectorAll('[data-impression]')) {
+			if (el.getAttribute('data-impression').includes(id)) return el
+		}
+		return null
+	}`, note.ID))
+	if err == nil {
+		return card, nil
+	}
+
+	cards, err := page.Elements(noteCardSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取笔记卡片失败")
+	}
+	var matched []*rod.Element
+	for _, c := range cards {
+		text, err := c.Text()
+		if err != nil {
+			continue
+		}
+		if noteCardHasTitle(text, note.Title) {
+			matched = append(matched, c)
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return nil, errors.Errorf("页面上未找到笔记「%s」的卡片", note.Title)
+	case 1:
+		return matched[0], nil
+	default:
+		return nil, errors.Errorf("页面上有 %d 篇标题为「%s」的笔记，无法确定要删除哪一篇", len(matched), note.Title)
+	}
+}
+
+// noteCardHasTitle 卡片文本里有一行与标题完全相同
+func noteCardHasTitle(text, title string) bool {
+	want := strings.TrimSpace(title)
+	if want == "" {
+		return false
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindPostedNote(t *testing.T) {
+	notes := []postedNote{{ID: "n2", Title: "周末探店"}, {ID: "n1", Title: "春日穿搭"}}
+
+	assert.Equal(t, 1, findPostedNote(notes, "n1"))
+	assert.Equal(t, -1, findPostedNote(notes, "n3"), "不在列表里视为不是自己的笔记")
+	assert.Equal(t, -1, findPostedNote(nil, "n1"))
+}
+
+func TestNoteCardHasTitle(t *testing.T) {
+	card := "周末探店\n发布于 2024年01月20日 10:30\n12\n3\n编辑\n删除"
+
+	assert.True(t, noteCardHasTitle(card, " 周末探店 "))
+	assert.False(t, noteCardHasTitle(card, "周末"), "只认整行相同")
+	assert.False(t, noteCardHasTitle(card, ""))
+}
@@ -0,0 +1,97 @@
+package xiaohongshu
+
+import (
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/jsonfile"
+)
+
+// 正文上限平台会调。每次发布都从发布页的「当前/上限」计数里读一遍，存到数据目录，
+// 发布前检查和 lint_post 用最近一次读到的值，重启后也不丢。
+
+// pageLimits 从发布页读到的限制
+type pageLimits struct {
+	ContentMaxLength int       `json:"content_max_length"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+var (
+	limitsMu     sync.Mutex
+	limitsLoaded bool
+	limits       pageLimits
+)
+
+func pageLimitsPath() string {
+	return filepath.Join(configs.GetDataPath(), "page_limits.json")
+}
+
+// loadPageLimits 第一次用时从文件读，调用方持有 limitsMu
+func loadPageLimits() {
+	if limitsLoaded {
+		return
+	}
+	limitsLoaded = true
+	if _, err := jsonfile.Load(pageLimitsPath(), &limits); err != nil {
+		slog.Warn("读取发布页限制失败，正文上限用默认值", "error", err)
+	}
+}
+
+// ContentMaxLength 最近一次从发布页读到的正文上限，没读到过返回 0 由调用方用默认值
+func ContentMaxLength() int {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+
+	loadPageLimits()
+	return limits.ContentMaxLength
+}
+
+// recordContentMaxLength 从「当前/上限」计数里记下上限，和已记的不同时写回文件
+func recordContentMaxLength(elemText string) {
+	parts := strings.Split(elemText, "/")
+	if len(parts) != 2 {
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil || n <= 0 {
+		return
+	}
+
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+
+	loadPageLimits()
+	if limits.ContentMaxLength == n {
+		return
+	}
+	slog.Info("发布页正文上限", "before", limits.ContentMaxLength, "after", n)
+	limits = pageLimits{ContentMaxLength: n, UpdatedAt: time.Now()}
+	if err := jsonfile.Save(pageLimitsPath(), limits); err != nil {
+		slog.Warn("保存发布页限制失败", "error", err)
+	}
+}
+
+// readContentCounter 找正文编辑区里「当前/上限」样式的字数计数，超长时的报错提示也是这个格式
+func readContentCounter(page *rod.Page) (string, bool) {
+	res, err := page.Eval(`() => {
+		const box = document.querySelector('div.edit-container');
+		if (!box) return '';
+		for (const el of box.querySelectorAll('div, span')) {
+			if (el.children.length > 0) continue;
+			const text = el.textContent.trim();
+			if (/^\d+\s*\/\s*\d+$/.test(text)) return text;
+		}
+		return '';
+	}`)
+	if err != nil {
+		return "", false
+	}
+	text := res.Value.Str()
+	return text, text != ""
+}
@@ -2,10 +2,12 @@ package xiaohongshu
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"math/rand"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,6 +29,28 @@ type PublishImageContent struct {
 	IsOriginal   bool       // 是否声明原创
 	Visibility   string     // 可见范围: "公开可见"(默认), "仅自己可见", "仅互关好友可见"
 	Products     []string   // 商品关键词列表，用于绑定带货商品
+	Location     string     // 地点，在「添加地点」里搜索并选中最匹配的一项
+
+	Collection       string // 加入的合集名称
+	CreateCollection bool   // 合集不存在时新建
+}
+
+// publishForm 发布页表单要填的内容，图文和视频共用
+type publishForm struct {
+	Title        string
+	Content      string
+	Tags         []string
+	ScheduleTime *time.Time
+	IsOriginal   bool
+	Visibility   string
+	Products     []string
+	Location     string
+
+	Collection       string
+	CreateCollection bool
+
+	// knownNotes 打开发布页前笔记列表里已有的笔记 ID，发布后只认不在里面的那篇
+	knownNotes map[string]bool
 }
 
 type PublishAction struct {
@@ -40,46 +64,58 @@ const (
 	contentElemTimeout = 10 * time.Second
 )
 
+// NewPublishImageAction 发布页在 Publish 时才打开：打开前要先记下笔记列表，这一步要用请求的 ctx
 func NewPublishImageAction(page *rod.Page) (*PublishAction, error) {
+	return &PublishAction{page: page}, nil
+}
 
-	pp := page.Timeout(300 * time.Second)
+// openPublishPage 记下笔记列表里已有的笔记，再进入发布页并切换到 tab
+func openPublishPage(page *rod.Page, tab string) (map[string]bool, error) {
+	known := snapshotPostedNotes(page)
 
-	if err := pp.Navigate(urlOfPublic); err != nil {
+	if err := page.Navigate(urlOfPublic); err != nil {
 		return nil, errors.Wrap(err, "导航到发布页面失败")
 	}
 
-	if err := pp.WaitLoad(); err != nil {
+	// 使用 WaitLoad 代替 WaitIdle（更宽松）
+	if err := page.WaitLoad(); err != nil {
 		logrus.Warnf("等待页面加载出现问题: %v，继续尝试", err)
 	}
 	time.Sleep(2 * time.Second)
 
-	if err := pp.WaitDOMStable(time.Second, 0.1); err != nil {
+	if err := page.WaitDOMStable(time.Second, 0.1); err != nil {
 		logrus.Warnf("等待 DOM 稳定出现问题: %v，继续尝试", err)
 	}
 	time.Sleep(1 * time.Second)
 
-	if err := mustClickPublishTab(pp, "上传图文"); err != nil {
-		logrus.Errorf("点击上传图文 TAB 失败: %v", err)
-		return nil, err
+	if err := mustClickPublishTab(page, tab); err != nil {
+		return nil, errors.Wrapf(err, "切换到%s失败", tab)
 	}
 
 	time.Sleep(1 * time.Second)
+	return known, nil
+}
 
-	return &PublishAction{
-		page: pp,
-	}, nil
+// PublishResult 发布结果
+type PublishResult struct {
+	FailedMentions []string       // 未能在联想列表里选中的 @ 用户，已按普通文本发出
+	Note           *PublishedNote // 发布后从笔记管理页找回的笔记，没找到时为 nil
 }
 
-func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent) error {
+func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent) (*PublishResult, error) {
 	if len(content.ImagePaths) == 0 {
-		return errors.New("图片不能为空")
+		return nil, errors.New("图片不能为空")
 	}
 
-	// 重设超时：.Context(ctx) 会替换掉 NewPublishImageAction 里 Timeout(300s) 的 deadline
 	page := p.page.Context(ctx).Timeout(300 * time.Second)
 
+	known, err := openPublishPage(page, "上传图文")
+	if err != nil {
+		return nil, err
+	}
+
 	if err := uploadImages(page, content.ImagePaths); err != nil {
-		return errors.Wrap(err, "小红书上传图片失败")
+		return nil, errors.Wrap(err, "小红书上传图片失败")
 	}
 
 	tags := content.Tags
@@ -90,11 +126,27 @@ func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent
 
 	logrus.Infof("发布内容: title=%s, images=%v, tags=%v, schedule=%v, original=%v, visibility=%s, products=%v", content.Title, len(content.ImagePaths), tags, content.ScheduleTime, content.IsOriginal, content.Visibility, content.Products)
 
-	if err := submitPublish(ctx, page, content.Title, content.Content, tags, content.ScheduleTime, content.IsOriginal, content.Visibility, content.Products); err != nil {
-		return errors.Wrap(err, "小红书发布失败")
+	form := publishForm{
+		Title:        content.Title,
+		Content:      content.Content,
+		Tags:         tags,
+		ScheduleTime: content.ScheduleTime,
+		IsOriginal:   content.IsOriginal,
+		Visibility:   content.Visibility,
+		Products:     content.Products,
+		Location:     content.Location,
+
+		Collection:       content.Collection,
+		CreateCollection: content.CreateCollection,
+
+		knownNotes: known,
+	}
+	result, err := submitPublish(ctx, page, form)
+	if err != nil {
+		return nil, errors.Wrap(err, "小红书发布失败")
 	}
 
-	return nil
+	return result, nil
 }
 
 // hasPopCover 当前页面是否还有挡人的浮层。
@@ -341,18 +393,19 @@ func waitForUploadComplete(page *rod.Page, expectedCount int) error {
 	return errors.Errorf("第%d张图片上传超时(60s)，请检查网络连接和图片大小", expectedCount)
 }
 
-func submitPublish(ctx context.Context, page *rod.Page, title, content string, tags []string, scheduleTime *time.Time, isOriginal bool, visibility string, products []string) error {
+// submitPublish 填写表单后点发布
+func submitPublish(ctx context.Context, page *rod.Page, form publishForm) (*PublishResult, error) {
 	titleElem, err := page.Element("div.d-input input")
 	if err != nil {
-		return errors.Wrap(err, "查找标题输入框失败")
+		return nil, errors.Wrap(err, "查找标题输入框失败")
 	}
-	if err := humanize.Type(ctx, titleElem, title); err != nil {
-		return errors.Wrap(err, "输入标题失败")
+	if err := humanize.Type(ctx, titleElem, form.Title); err != nil {
+		return nil, errors.Wrap(err, "输入标题失败")
 	}
 
 	humanize.Delay(ctx, humanize.AfterType)
 	if err := checkTitleMaxLength(page); err != nil {
-		return err
+		return nil, err
 	}
 	slog.Info("检查标题长度：通过")
 
@@ -360,55 +413,108 @@ func submitPublish(ctx context.Context, page *rod.Page, title, content string, t
 
 	contentElem, err := getContentElement(page, contentElemTimeout)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err := humanize.Type(ctx, contentElem, content); err != nil {
-		return errors.Wrap(err, "输入正文失败")
+	failedMentions, err := typeWithMentions(ctx, contentElem, form.Content, creatorMentionPopup)
+	if err != nil {
+		return nil, errors.Wrap(err, "输入正文失败")
 	}
 	if err := waitAndClickTitleInput(titleElem); err != nil {
-		return err
+		return nil, err
 	}
-	if err := inputTags(ctx, contentElem, tags); err != nil {
-		return err
+	if err := inputTags(ctx, contentElem, form.Tags); err != nil {
+		return nil, err
 	}
 
 	humanize.Delay(ctx, humanize.AfterType)
 
 	if err := checkContentMaxLength(page); err != nil {
-		return err
+		return nil, err
 	}
 	slog.Info("检查正文长度：通过")
 
-	if scheduleTime != nil {
-		if err := setSchedulePublish(ctx, page, *scheduleTime); err != nil {
-			return errors.Wrap(err, "设置定时发布失败")
+	if err := setLocation(ctx, page, form.Location); err != nil {
+		return nil, errors.Wrap(err, "添加地点失败")
+	}
+
+	if err := setCollection(ctx, page, form.Collection, form.CreateCollection); err != nil {
+		return nil, errors.Wrap(err, "加入合集失败")
+	}
+
+	if form.ScheduleTime != nil {
+		if err := setSchedulePublish(ctx, page, *form.ScheduleTime); err != nil {
+			return nil, errors.Wrap(err, "设置定时发布失败")
 		}
-		slog.Info("定时发布设置完成", "schedule_time", scheduleTime.Format("2006-01-02 15:04"))
+		slog.Info("定时发布设置完成", "schedule_time", form.ScheduleTime.Format("2006-01-02 15:04"))
 	}
 
-	if err := setVisibility(page, visibility); err != nil {
-		return errors.Wrap(err, "设置可见范围失败")
+	if err := setVisibility(page, form.Visibility); err != nil {
+		return nil, errors.Wrap(err, "设置可见范围失败")
 	}
 
 	// 处理原创声明：显式请求了原创但设置失败 → 报错中止，不静默发成非原创（避免"以为原创其实不是"）
-	if isOriginal {
+	if form.IsOriginal {
 		if err := setOriginal(page); err != nil {
-			return errors.Wrap(err, "设置原创声明失败（已请求原创，中止发布）")
+			return nil, errors.Wrap(err, "设置原创声明失败（已请求原创，中止发布）")
 		}
 		slog.Info("已声明原创")
 	}
 
-	if err := bindProducts(ctx, page, products); err != nil {
-		return errors.Wrap(err, "绑定商品失败")
+	if err := bindProducts(ctx, page, form.Products); err != nil {
+		return nil, errors.Wrap(err, "绑定商品失败")
 	}
 
 	if err := clickPublishButton(page); err != nil {
-		return err
+		return nil, err
 	}
 
 	// 校验发布真的成功：成功后创作平台会跳转离开发布页；未跳转则判定失败，
 	// 消除"点了发布按钮就算成功"的假阳性。
-	return waitPublishSuccess(page, 15*time.Second)
+	if err := waitPublishSuccess(page, 15*time.Second); err != nil {
+		return confirmPublished(ctx, page, form, failedMentions, err)
+	}
+	return publishedResult(ctx, page, form, failedMentions), nil
+}
+
+// UnconfirmedError 点了发布但没等到跳转，笔记可能已经发出去了，不能直接重发。
+// 带上发布前的笔记列表，调用方重试前用 FindPublishedNote 核对
+type UnconfirmedError struct {
+	Title      string
+	KnownNotes []string // 发布前列表里已有的笔记 ID，nil 表示没读到，无法核对
+	Err        error
+}
+
+func (e *UnconfirmedError) Error() string { return e.Err.Error() }
+
+func (e *UnconfirmedError) Unwrap() error { return e.Err }
+
+// confirmPublished 没等到跳转时去笔记列表看一眼：出现了新笔记就按成功返回，否则返回 *UnconfirmedError
+func confirmPublished(ctx context.Context, page *rod.Page, form publishForm, failedMentions []string, waitErr error) (*PublishResult, error) {
+	note, err := resolvePublishedNote(ctx, page, form.Title, form.knownNotes)
+	if err == nil {
+		slog.Warn("发布后未跳转，但笔记列表里已有新笔记，按发布成功处理", "note_id", note.NoteID)
+		return &PublishResult{FailedMentions: failedMentions, Note: note}, nil
+	}
+	slog.Warn("发布后未跳转，笔记列表里也没找到新笔记", "title", form.Title, "error", err)
+
+	var known []string
+	if form.knownNotes != nil {
+		known = make([]string, 0, len(form.knownNotes))
+		for id := range form.knownNotes {
+			known = append(known, id)
+		}
+		sort.Strings(known)
+	}
+	return nil, &UnconfirmedError{Title: form.Title, KnownNotes: known, Err: waitErr}
+}
+
+// publishedResult 发布已确认成功，再去笔记管理页找回笔记 ID；找不到只记日志，不能让调用方以为没发布而重试
+func publishedResult(ctx context.Context, page *rod.Page, form publishForm, failedMentions []string) *PublishResult {
+	note, err := resolvePublishedNote(ctx, page, form.Title, form.knownNotes)
+	if err != nil {
+		slog.Warn("笔记已发布，但未能获取笔记 ID", "title", form.Title, "error", err)
+	}
+	return &PublishResult{FailedMentions: failedMentions, Note: note}
 }
 
 // waitPublishSuccess 轮询等待发布成功的信号：小红书发布成功后会跳转离开发布表单页
@@ -615,6 +721,12 @@ func checkTitleMaxLength(page *rod.Page) error {
 }
 
 func checkContentMaxLength(page *rod.Page) error {
+	if counter, ok := readContentCounter(page); ok {
+		recordContentMaxLength(counter)
+	} else {
+		slog.Debug("发布页没有正文字数计数，正文上限沿用上次记下的值")
+	}
+
 	has, elem, err := page.Has(`div.edit-container div.length-error`)
 	if err != nil {
 		return errors.Wrap(err, "检查正文长度元素失败")
@@ -629,18 +741,25 @@ func checkContentMaxLength(page *rod.Page) error {
 		return errors.Wrap(err, "获取正文长度文本失败")
 	}
 
+	recordContentMaxLength(contentLength)
 	return makeMaxLengthError(contentLength)
 }
 
-func makeMaxLengthError(elemText string) error {
-	parts := strings.Split(elemText, "/")
+// LengthError 发布页提示标题或正文超长，不改内容重试也不会通过
+type LengthError struct {
+	Text string // 页面上「当前/上限」的提示
+}
+
+func (e *LengthError) Error() string {
+	parts := strings.Split(e.Text, "/")
 	if len(parts) != 2 {
-		return errors.Errorf("长度超过限制: %s", elemText)
+		return fmt.Sprintf("长度超过限制: %s", e.Text)
 	}
+	return fmt.Sprintf("当前输入长度为%s，最大长度为%s", parts[0], parts[1])
+}
 
-	currLen, maxLen := parts[0], parts[1]
-
-	return errors.Errorf("当前输入长度为%s，最大长度为%s", currLen, maxLen)
+func makeMaxLengthError(elemText string) error {
+	return errors.WithStack(&LengthError{Text: elemText})
 }
 
 // contentElemSelectors 正文输入框的候选选择器，按先后顺序尝试。
@@ -884,7 +1003,11 @@ func setVisibility(page *rod.Page, visibility string) error {
 	if !supported[visibility] {
 		return errors.Errorf("不支持的可见范围: %s，支持: 公开可见、仅自己可见、仅互关好友可见", visibility)
 	}
+	return selectVisibility(page, visibility)
+}
 
+// selectVisibility 打开可见范围下拉并选中 visibility，编辑笔记改回「公开可见」时也走这里
+func selectVisibility(page *rod.Page, visibility string) error {
 	dropdown, err := page.Element("div.permission-card-wrapper div.d-select-content")
 	if err != nil {
 		return errors.Wrap(err, "查找可见范围下拉框失败")
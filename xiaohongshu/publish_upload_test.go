@@ -26,3 +26,27 @@ func TestAcceptsImage(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordContentMaxLength(t *testing.T) {
+	t.Setenv("XHS_DATA_DIR", t.TempDir())
+	resetPageLimits := func() {
+		limitsMu.Lock()
+		limitsLoaded, limits = false, pageLimits{}
+		limitsMu.Unlock()
+	}
+	resetPageLimits()
+	t.Cleanup(resetPageLimits)
+
+	recordContentMaxLength("超出")
+	assert.Equal(t, 0, ContentMaxLength(), "认不出时不记")
+
+	recordContentMaxLength("120/1000")
+	assert.Equal(t, 1000, ContentMaxLength(), "没超长也记")
+
+	resetPageLimits()
+	assert.Equal(t, 1000, ContentMaxLength(), "重启后从文件读回")
+
+	recordContentMaxLength("1024/800")
+	resetPageLimits()
+	assert.Equal(t, 800, ContentMaxLength(), "平台调整后以新值为准")
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/go-rod/rod"
 	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/humanize"
 )
 
@@ -21,53 +20,57 @@ type PublishVideoContent struct {
 	ScheduleTime *time.Time // 定时发布时间，nil 表示立即发布
 	Visibility   string     // 可见范围: "公开可见"(默认), "仅自己可见", "仅互关好友可见"
 	Products     []string   // 商品关键词列表，用于绑定带货商品
+	Cover        VideoCover // 自定义封面，零值表示沿用平台默认帧
+	Location     string     // 地点，在「添加地点」里搜索并选中最匹配的一项
+
+	Collection       string // 加入的合集名称
+	CreateCollection bool   // 合集不存在时新建
 }
 
-// NewPublishVideoAction 进入发布页并切换到"上传视频"
+// NewPublishVideoAction 发布页在 PublishVideo 时才打开并切换到"上传视频"
 func NewPublishVideoAction(page *rod.Page) (*PublishAction, error) {
-	pp := page.Timeout(300 * time.Second)
-
-	if err := pp.Navigate(urlOfPublic); err != nil {
-		return nil, errors.Wrap(err, "导航到发布页面失败")
-	}
-
-	// 使用 WaitLoad 代替 WaitIdle（更宽松）
-	if err := pp.WaitLoad(); err != nil {
-		logrus.Warnf("等待页面加载出现问题: %v，继续尝试", err)
-	}
-	time.Sleep(2 * time.Second)
-
-	if err := pp.WaitDOMStable(time.Second, 0.1); err != nil {
-		logrus.Warnf("等待 DOM 稳定出现问题: %v，继续尝试", err)
-	}
-	time.Sleep(1 * time.Second)
-
-	if err := mustClickPublishTab(pp, "上传视频"); err != nil {
-		return nil, errors.Wrap(err, "切换到上传视频失败")
-	}
-
-	time.Sleep(1 * time.Second)
-
-	return &PublishAction{page: pp}, nil
+	return &PublishAction{page: page}, nil
 }
 
 // PublishVideo 上传视频并提交
-func (p *PublishAction) PublishVideo(ctx context.Context, content PublishVideoContent) error {
+func (p *PublishAction) PublishVideo(ctx context.Context, content PublishVideoContent) (*PublishResult, error) {
 	if content.VideoPath == "" {
-		return errors.New("视频不能为空")
+		return nil, errors.New("视频不能为空")
+	}
+	if err := content.Cover.Validate(); err != nil {
+		return nil, err
 	}
 
-	// 重设超时：.Context(ctx) 会替换掉 NewPublishVideoAction 里 Timeout(300s) 的 deadline
 	page := p.page.Context(ctx).Timeout(300 * time.Second)
 
+	known, err := openPublishPage(page, "上传视频")
+	if err != nil {
+		return nil, err
+	}
+
 	if err := uploadVideo(page, content.VideoPath); err != nil {
-		return errors.Wrap(err, "小红书上传视频失败")
+		return nil, errors.Wrap(err, "小红书上传视频失败")
 	}
 
-	if err := submitPublishVideo(ctx, page, content.Title, content.Content, content.Tags, content.ScheduleTime, content.Visibility, content.Products); err != nil {
-		return errors.Wrap(err, "小红书发布失败")
+	form := publishForm{
+		Title:        content.Title,
+		Content:      content.Content,
+		Tags:         content.Tags,
+		ScheduleTime: content.ScheduleTime,
+		Visibility:   content.Visibility,
+		Products:     content.Products,
+		Location:     content.Location,
+
+		Collection:       content.Collection,
+		CreateCollection: content.CreateCollection,
+
+		knownNotes: known,
 	}
-	return nil
+	result, err := submitPublishVideo(ctx, page, form, content.Cover)
+	if err != nil {
+		return nil, errors.Wrap(err, "小红书发布失败")
+	}
+	return result, nil
 }
 
 // uploadVideo 上传单个本地视频
@@ -100,57 +103,74 @@ func uploadVideo(page *rod.Page, videoPath string) error {
 	return nil
 }
 
-// submitPublishVideo 填写标题、正文、标签并点击发布（等待按钮可点击后再提交）
-func submitPublishVideo(ctx context.Context, page *rod.Page, title, content string, tags []string, scheduleTime *time.Time, visibility string, products []string) error {
+// submitPublishVideo 设置封面，填写标题、正文、标签并点击发布（等待按钮可点击后再提交）
+func submitPublishVideo(ctx context.Context, page *rod.Page, form publishForm, cover VideoCover) (*PublishResult, error) {
+	// 封面放在最前：封面弹窗会遮住表单
+	if err := setVideoCover(ctx, page, cover); err != nil {
+		return nil, errors.Wrap(err, "设置视频封面失败")
+	}
+
 	// 标题
 	titleElem, err := page.Element("div.d-input input")
 	if err != nil {
-		return errors.Wrap(err, "查找标题输入框失败")
+		return nil, errors.Wrap(err, "查找标题输入框失败")
 	}
-	if err := humanize.Type(ctx, titleElem, title); err != nil {
-		return errors.Wrap(err, "输入标题失败")
+	if err := humanize.Type(ctx, titleElem, form.Title); err != nil {
+		return nil, errors.Wrap(err, "输入标题失败")
 	}
 	humanize.Delay(ctx, humanize.AfterType)
 
 	// 正文 + 标签
 	contentElem, err := getContentElement(page, contentElemTimeout)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err := humanize.Type(ctx, contentElem, content); err != nil {
-		return errors.Wrap(err, "输入正文失败")
+	failedMentions, err := typeWithMentions(ctx, contentElem, form.Content, creatorMentionPopup)
+	if err != nil {
+		return nil, errors.Wrap(err, "输入正文失败")
 	}
 	if err := waitAndClickTitleInput(titleElem); err != nil {
-		return err
+		return nil, err
 	}
-	if err := inputTags(ctx, contentElem, tags); err != nil {
-		return err
+	if err := inputTags(ctx, contentElem, form.Tags); err != nil {
+		return nil, err
 	}
 
 	humanize.Delay(ctx, humanize.AfterType)
 
+	if err := setLocation(ctx, page, form.Location); err != nil {
+		return nil, errors.Wrap(err, "添加地点失败")
+	}
+
+	if err := setCollection(ctx, page, form.Collection, form.CreateCollection); err != nil {
+		return nil, errors.Wrap(err, "加入合集失败")
+	}
+
 	// 处理定时发布
-	if scheduleTime != nil {
-		if err := setSchedulePublish(ctx, page, *scheduleTime); err != nil {
-			return errors.Wrap(err, "设置定时发布失败")
+	if form.ScheduleTime != nil {
+		if err := setSchedulePublish(ctx, page, *form.ScheduleTime); err != nil {
+			return nil, errors.Wrap(err, "设置定时发布失败")
 		}
-		slog.Info("定时发布设置完成", "schedule_time", scheduleTime.Format("2006-01-02 15:04"))
+		slog.Info("定时发布设置完成", "schedule_time", form.ScheduleTime.Format("2006-01-02 15:04"))
 	}
 
 	// 设置可见范围
-	if err := setVisibility(page, visibility); err != nil {
-		return errors.Wrap(err, "设置可见范围失败")
+	if err := setVisibility(page, form.Visibility); err != nil {
+		return nil, errors.Wrap(err, "设置可见范围失败")
 	}
 
 	// 绑定商品
-	if err := bindProducts(ctx, page, products); err != nil {
-		return errors.Wrap(err, "绑定商品失败")
+	if err := bindProducts(ctx, page, form.Products); err != nil {
+		return nil, errors.Wrap(err, "绑定商品失败")
 	}
 
 	if err := clickPublishButton(page); err != nil {
-		return err
+		return nil, err
 	}
 
 	// 校验发布真的成功（成功跳转离开发布页），未跳转判失败——消除假成功
-	return waitPublishSuccess(page, 15*time.Second)
+	if err := waitPublishSuccess(page, 15*time.Second); err != nil {
+		return confirmPublished(ctx, page, form, failedMentions, err)
+	}
+	return publishedResult(ctx, page, form, failedMentions), nil
 }
@@ -0,0 +1,193 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+// 发布成功页不带笔记 ID，只能回到创作中心的笔记管理页，从它请求的笔记列表接口里找刚发的那篇。
+// 只按标题找会认错同名的旧笔记，所以打开发布页前先记下列表里已有的笔记 ID，发布后只认新出现的。
+// 新笔记偶尔要过几秒才出现在列表里，所以会重新打开几次。
+
+// postedNotesAPI 笔记管理页拉取已发布笔记的接口路径片段
+const postedNotesAPI = "/note/user/posted"
+
+const (
+	resolveNoteAttempts = 3
+	resolveNoteInterval = 3 * time.Second
+)
+
+// PublishedNote 刚发布的笔记，可直接用于 get_feed_detail 等后续操作
+type PublishedNote struct {
+	NoteID    string `json:"note_id"`
+	XsecToken string `json:"xsec_token"`
+	URL       string `json:"url"`
+}
+
+// postedNote 笔记列表接口里的一条笔记，只取需要的字段
+type postedNote struct {
+	ID        string `json:"id"`
+	Title     string `json:"display_title"`
+	XsecToken string `json:"xsec_token"`
+}
+
+type postedNotesResponse struct {
+	Success bool   `json:"success"`
+	Msg     string `json:"msg"`
+	Data    struct {
+		Notes []postedNote `json:"notes"`
+		// Page 下一页的页码，列表到底时为 -1
+		Page int `json:"page"`
+	} `json:"data"`
+}
+
+// parsePostedNotes 解析笔记列表接口的响应
+func parsePostedNotes(body []byte) ([]postedNote, error) {
+	notes, _, err := parsePostedNotesPage(body)
+	return notes, err
+}
+
+// parsePostedNotesPage 解析笔记列表接口的一页，more 表示后面还有
+func parsePostedNotesPage(body []byte) (notes []postedNote, more bool, err error) {
+	var resp postedNotesResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, false, errors.Wrap(err, "解析笔记列表失败")
+	}
+	if !resp.Success {
+		return nil, false, errors.Errorf("笔记列表接口返回失败: %s", resp.Msg)
+	}
+	return resp.Data.Notes, resp.Data.Page >= 0 && len(resp.Data.Notes) > 0, nil
+}
+
+// snapshotPostedNotes 打开发布页前记下笔记列表里已有的笔记 ID；读取失败返回 nil，发布后就不认领笔记 ID
+func snapshotPostedNotes(page *rod.Page) map[string]bool {
+	notes, err := postedNotes(page)
+	if err != nil {
+		slog.Warn("发布前读取笔记列表失败，发布后将无法确认笔记 ID", "error", err)
+		return nil
+	}
+	known := make(map[string]bool, len(notes))
+	for _, n := range notes {
+		known[n.ID] = true
+	}
+	return known
+}
+
+// matchPostedNote 列表按发布时间倒序，取第一篇标题一致且发布前不在列表里的笔记
+func matchPostedNote(notes []postedNote, title string, known map[string]bool) int {
+	want := strings.TrimSpace(title)
+	if want == "" {
+		return -1
+	}
+	for i, n := range notes {
+		if strings.TrimSpace(n.Title) == want && n.ID != "" && !known[n.ID] {
+			return i
+		}
+	}
+	return -1
+}
+
+// resolvePublishedNote 发布成功后找回笔记 ID 和 xsec_token。笔记已经发出去了，找不到只返回错误由调用方记日志。
+// known 为 nil 说明发布前没读到列表，分不清新旧，宁可不返回也不返回同名旧笔记的 ID
+func resolvePublishedNote(ctx context.Context, page *rod.Page, title string, known map[string]bool) (*PublishedNote, error) {
+	if known == nil {
+		return nil, errors.New("发布前未能读取笔记列表，无法区分同名的旧笔记")
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < resolveNoteAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(resolveNoteInterval):
+			}
+		}
+
+		notes, err := postedNotes(page)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if i := matchPostedNote(notes, title, known); i >= 0 {
+			n := notes[i]
+			slog.Info("已找到刚发布的笔记", "note_id", n.ID, "attempt", attempt+1)
+			return &PublishedNote{NoteID: n.ID, XsecToken: n.XsecToken, URL: makeFeedDetailURL(n.ID, n.XsecToken)}, nil
+		}
+		lastErr = errors.Errorf("笔记列表里暂未出现标题为 %q 的新笔记", title)
+	}
+	return nil, lastErr
+}
+
+// FindPublishedNote 在 page 上打开笔记管理页，找发布前不在 known 里、标题为 title 的笔记。
+// 没找到返回 nil, nil；读不到列表返回错误，这时分不清发没发出去
+func FindPublishedNote(page *rod.Page, title string, known []string) (*PublishedNote, error) {
+	notes, err := postedNotes(page)
+	if err != nil {
+		return nil, err
+	}
+	knownSet := make(map[string]bool, len(known))
+	for _, id := range known {
+		knownSet[id] = true
+	}
+	i := matchPostedNote(notes, title, knownSet)
+	if i < 0 {
+		return nil, nil
+	}
+	n := notes[i]
+	return &PublishedNote{NoteID: n.ID, XsecToken: n.XsecToken, URL: makeFeedDetailURL(n.ID, n.XsecToken)}, nil
+}
+
+// capturePostedNotes 打开笔记管理页，截获笔记列表接口的响应体
+func capturePostedNotes(page *rod.Page, timeout time.Duration) ([]byte, error) {
+	p := page.Timeout(timeout)
+
+	var requestID proto.NetworkRequestID
+	var body []byte
+	var bodyErr error
+	wait := p.EachEvent(func(e *proto.NetworkResponseReceived) {
+		if requestID == "" && strings.Contains(e.Response.URL, postedNotesAPI) {
+			requestID = e.RequestID
+		}
+	}, func(e *proto.NetworkLoadingFinished) bool {
+		if requestID == "" || e.RequestID != requestID {
+			return false
+		}
+		// 响应体要在 Network 域关闭前取，wait 结束后就拿不到了
+		body, bodyErr = responseBody(p, requestID)
+		return true
+	})
+
+	if err := p.Navigate(urlOfNoteManager); err != nil {
+		return nil, errors.Wrap(err, "打开笔记管理失败")
+	}
+	wait()
+
+	if bodyErr != nil {
+		return nil, errors.Wrap(bodyErr, "读取笔记列表响应失败")
+	}
+	if body == nil {
+		return nil, errors.New("等待笔记列表接口超时")
+	}
+	return body, nil
+}
+
+// responseBody 取一次请求的响应体
+func responseBody(page *rod.Page, requestID proto.NetworkRequestID) ([]byte, error) {
+	res, err := proto.NetworkGetResponseBody{RequestID: requestID}.Call(page)
+	if err != nil {
+		return nil, err
+	}
+	if res.Base64Encoded {
+		return base64.StdEncoding.DecodeString(res.Body)
+	}
+	return []byte(res.Body), nil
+}
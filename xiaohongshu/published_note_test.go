@@ -0,0 +1,60 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePostedNotes(t *testing.T) {
+	body := []byte(`{"code":0,"success":true,"msg":"成功","data":{"notes":[
+		{"id":"66a1b2c3d4e5f6a7b8c9d0e1","display_title":"周末探店","xsec_token":"ABxyz=","type":"normal","time":"2024-01-20 10:30"},
+		{"id":"66a1b2c3d4e5f6a7b8c9d0e0","display_title":"春日穿搭","xsec_token":"ABabc="}
+	],"page":1}}`)
+
+	notes, err := parsePostedNotes(body)
+	require.NoError(t, err)
+	assert.Equal(t, []postedNote{
+		{ID: "66a1b2c3d4e5f6a7b8c9d0e1", Title: "周末探店", XsecToken: "ABxyz="},
+		{ID: "66a1b2c3d4e5f6a7b8c9d0e0", Title: "春日穿搭", XsecToken: "ABabc="},
+	}, notes)
+
+	_, err = parsePostedNotes([]byte(`{"code":-1,"success":false,"msg":"登录已过期"}`))
+	assert.ErrorContains(t, err, "登录已过期")
+
+	_, err = parsePostedNotes([]byte(`<html>`))
+	assert.Error(t, err)
+}
+
+func TestParsePostedNotesPage(t *testing.T) {
+	_, more, err := parsePostedNotesPage([]byte(`{"success":true,"data":{"notes":[{"id":"n1"}],"page":1}}`))
+	require.NoError(t, err)
+	assert.True(t, more)
+
+	_, more, err = parsePostedNotesPage([]byte(`{"success":true,"data":{"notes":[{"id":"n1"}],"page":-1}}`))
+	require.NoError(t, err)
+	assert.False(t, more, "page 为 -1 表示列表到底")
+
+	_, more, err = parsePostedNotesPage([]byte(`{"success":true,"data":{"notes":[],"page":2}}`))
+	require.NoError(t, err)
+	assert.False(t, more, "空页也当作到底")
+}
+
+func TestMatchPostedNote(t *testing.T) {
+	notes := []postedNote{
+		{ID: "n4", Title: "春日穿搭"},
+		{ID: "n3", Title: "周末探店"},
+		{ID: "n2", Title: "周末探店 "},
+		{ID: "", Title: "春日穿搭"},
+		{ID: "n1", Title: "春日穿搭"},
+	}
+	known := map[string]bool{"n1": true, "n2": true, "n3": true}
+
+	assert.Equal(t, -1, matchPostedNote(notes, " 周末探店", known), "同名的旧笔记发布前就在列表里，不能当成新发的")
+	assert.Equal(t, 0, matchPostedNote(notes, "春日穿搭", known), "只认发布前不在列表里的")
+	assert.Equal(t, 1, matchPostedNote(notes, "周末探店", map[string]bool{"n2": true}), "新笔记排在同名旧笔记前面")
+	assert.Equal(t, -1, matchPostedNote(notes[3:4], "春日穿搭", map[string]bool{}), "跳过没有 ID 的条目")
+	assert.Equal(t, -1, matchPostedNote(notes, "周末", known))
+	assert.Equal(t, -1, matchPostedNote(notes, "", known))
+}
@@ -83,6 +83,12 @@ func collectFilters(filters []FilterOption) ([]pendingFilter, error) {
 	return pending, nil
 }
 
+// ValidateFilters 只校验筛选取值，不打开页面。保存搜索时用来提前挡掉写错的值
+func ValidateFilters(filters ...FilterOption) error {
+	_, err := collectFilters(filters)
+	return err
+}
+
 type SearchAction struct {
 	page *rod.Page
 }
@@ -1,6 +1,10 @@
 package xiaohongshu
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/xhsutil"
+)
 
 // 小红书 Feed 相关的数据结构定义
 
@@ -66,7 +70,8 @@ type User struct {
 	Avatar   string `json:"avatar"`
 }
 
-// InteractInfo 表示互动信息
+// InteractInfo 表示互动信息。
+// *Count 是页面的展示字符串（如 "1.2万"），*CountNum 是解析后的数值，解析不了时为 nil。
 type InteractInfo struct {
 	Liked      bool   `json:"liked"`
 	LikedCount string `json:"likedCount"`
@@ -76,6 +81,11 @@ type InteractInfo struct {
 
 	CollectedCount string `json:"collectedCount"`
 	Collected      bool   `json:"collected"`
+
+	LikedCountNum     *xhsutil.Count `json:"likedCountNum,omitempty"`
+	SharedCountNum    *xhsutil.Count `json:"sharedCountNum,omitempty"`
+	CommentCountNum   *xhsutil.Count `json:"commentCountNum,omitempty"`
+	CollectedCountNum *xhsutil.Count `json:"collectedCountNum,omitempty"`
 }
 
 // Cover 表示封面信息
@@ -87,6 +97,7 @@ type Cover struct {
 	URLPre     string      `json:"urlPre"`
 	URLDefault string      `json:"urlDefault"`
 	InfoList   []ImageInfo `json:"infoList"`
+	FileURL    string      `json:"fileUrl,omitempty"` // 由 FileID 拼出的 CDN 地址，见 FillCDNURLs
 }
 
 // ImageInfo 表示图片信息
@@ -125,9 +136,17 @@ type FeedDetail struct {
 	User         User              `json:"user"`
 	InteractInfo InteractInfo      `json:"interactInfo"`
 	ImageList    []DetailImageInfo `json:"imageList"`
+	TagList      []Tag             `json:"tagList"`
 	Video        *VideoDetail      `json:"video,omitempty"` // 视频笔记才有，图文笔记为 nil
 }
 
+// Tag 笔记的话题标签，正文里以 #name[话题]# 的形式出现
+type Tag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"` // topic 等
+}
+
 // VideoDetail 详情页的视频信息，按页面 note.video 原样映射，不替调用方挑档位。
 type VideoDetail struct {
 	Image VideoImage      `json:"image"`
@@ -166,10 +185,12 @@ func (v *VideoDetail) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// VideoImage 视频的首帧与缩略图，只有 fileid，需自行拼 CDN 地址。
+// VideoImage 视频的首帧与缩略图。页面只给 fileid，地址由 FillCDNURLs 拼出。
 type VideoImage struct {
 	FirstFrameFileID string `json:"firstFrameFileid"`
 	ThumbnailFileID  string `json:"thumbnailFileid"`
+	FirstFrameURL    string `json:"firstFrameUrl,omitempty"`
+	ThumbnailURL     string `json:"thumbnailUrl,omitempty"`
 }
 
 // VideoMedia 视频媒体信息。
@@ -244,6 +265,27 @@ type DetailImageInfo struct {
 	URLDefault string `json:"urlDefault"`
 	URLPre     string `json:"urlPre"`
 	LivePhoto  bool   `json:"livePhoto,omitempty"`
+	// Stream 实况图的视频部分，结构与 VideoMedia.Stream 相同；普通图片为 nil。
+	Stream map[string][]VideoStream `json:"stream,omitempty"`
+}
+
+// UnmarshalJSON 去掉 stream 里的空桶。
+// 普通图片也会带 stream，只是每个编码下都是空数组，原样保留会让每张图多出一段噪音。
+func (d *DetailImageInfo) UnmarshalJSON(data []byte) error {
+	type alias DetailImageInfo // 借别名避免递归调用本方法
+	if err := json.Unmarshal(data, (*alias)(d)); err != nil {
+		return err
+	}
+
+	for codec, streams := range d.Stream {
+		if len(streams) == 0 {
+			delete(d.Stream, codec)
+		}
+	}
+	if len(d.Stream) == 0 {
+		d.Stream = nil
+	}
+	return nil
 }
 
 // CommentList 表示评论列表
@@ -266,6 +308,9 @@ type Comment struct {
 	SubCommentCount string    `json:"subCommentCount"`
 	SubComments     []Comment `json:"subComments"`
 	ShowTags        []string  `json:"showTags"`
+
+	LikeCountNum       *xhsutil.Count `json:"likeCountNum,omitempty"`
+	SubCommentCountNum *xhsutil.Count `json:"subCommentCountNum,omitempty"`
 }
 
 // UserProfileResponse 用户详情页完整响应
@@ -299,4 +344,6 @@ type UserInteractions struct {
 	Type  string `json:"type"`  // follows fans interaction
 	Name  string `json:"name"`  // 关注 粉丝 获赞与收藏
 	Count string `json:"count"` // 数量
+
+	CountNum *xhsutil.Count `json:"countNum,omitempty"`
 }
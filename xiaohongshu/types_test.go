@@ -1,6 +1,7 @@
 package xiaohongshu
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -50,3 +51,22 @@ func TestOnlyNotes(t *testing.T) {
 		assert.Empty(t, got)
 	})
 }
+
+// TestDetailImageInfo_Stream 只有实况图保留 stream，普通图片的空桶要去掉。
+func TestDetailImageInfo_Stream(t *testing.T) {
+	t.Run("普通图片的空桶置为 nil", func(t *testing.T) {
+		var img DetailImageInfo
+		err := json.Unmarshal([]byte(`{"urlDefault":"u","stream":{"h264":[],"h265":[],"av1":[]}}`), &img)
+		assert.NoError(t, err)
+		assert.Nil(t, img.Stream)
+	})
+
+	t.Run("实况图保留非空桶", func(t *testing.T) {
+		var img DetailImageInfo
+		err := json.Unmarshal([]byte(`{"livePhoto":true,"stream":{"h264":[{"masterUrl":"m","backupUrls":["b"]}],"h265":[]}}`), &img)
+		assert.NoError(t, err)
+		assert.True(t, img.LivePhoto)
+		assert.Len(t, img.Stream, 1)
+		assert.Equal(t, "m", img.Stream["h264"][0].MasterURL)
+	})
+}
@@ -0,0 +1,209 @@
+package xiaohongshu
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/humanize"
+)
+
+// VideoCover 视频封面设置，Image 与 Time 二选一，都为空表示沿用平台默认帧
+type VideoCover struct {
+	Image string         // 本地封面图片路径
+	Time  *time.Duration // 从视频中截取该时间点的帧作为封面
+}
+
+// IsZero 未指定封面
+func (c VideoCover) IsZero() bool {
+	return c.Image == "" && c.Time == nil
+}
+
+// Validate 校验封面参数
+func (c VideoCover) Validate() error {
+	if c.Image != "" && c.Time != nil {
+		return errors.New("封面图片和封面时间点只能二选一")
+	}
+	if c.Time != nil && *c.Time < 0 {
+		return errors.New("封面时间点不能为负数")
+	}
+	if c.Image != "" {
+		if _, err := os.Stat(c.Image); err != nil {
+			return errors.Wrapf(err, "封面图片不存在: %s", c.Image)
+		}
+	}
+	return nil
+}
+
+// coverModalTimeout 等待封面编辑弹窗出现/关闭的超时
+const coverModalTimeout = 10 * time.Second
+
+// setVideoCover 打开封面编辑器，上传封面图或按时间点截帧，确认后等弹窗关闭
+func setVideoCover(ctx context.Context, page *rod.Page, cover VideoCover) error {
+	if cover.IsZero() {
+		return nil
+	}
+
+	entry, err := page.Timeout(coverModalTimeout).ElementR("div, span, button", `^(设置|修改|编辑)封面$`)
+	if err != nil {
+		return errors.Wrap(err, "未找到封面编辑入口")
+	}
+	if err := humanize.Click(entry); err != nil {
+		return errors.Wrap(err, "点击封面编辑入口失败")
+	}
+
+	modal, err := waitForModal(page, "封面", coverModalTimeout)
+	if err != nil {
+		return err
+	}
+	humanize.Delay(ctx, humanize.AfterClick)
+
+	if cover.Image != "" {
+		err = uploadCoverImage(ctx, modal, cover.Image)
+	} else {
+		err = pickCoverFrame(ctx, page, modal, *cover.Time)
+	}
+	if err != nil {
+		return err
+	}
+
+	confirm, err := modal.Timeout(coverModalTimeout).ElementR("button", `^\s*(确定|确认|完成)\s*$`)
+	if err != nil {
+		return errors.Wrap(err, "未找到封面确认按钮")
+	}
+	if err := humanize.Click(confirm); err != nil {
+		return errors.Wrap(err, "点击封面确认按钮失败")
+	}
+
+	if err := waitModalClosed(page, "封面", coverModalTimeout); err != nil {
+		return errors.Wrap(err, "封面可能未保存")
+	}
+	slog.Info("视频封面设置完成", "image", cover.Image, "time", cover.Time)
+	return nil
+}
+
+// waitForModal 等待文本含 keyword 的弹窗可见
+func waitForModal(page *rod.Page, keyword string, timeout time.Duration) (*rod.Element, error) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if modal := findModal(page, keyword); modal != nil {
+			return modal, nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return nil, errors.Errorf("等待%s弹窗超时", keyword)
+}
+
+// waitModalClosed 等待文本含 keyword 的弹窗消失，不消失多半是还在处理或校验失败
+func waitModalClosed(page *rod.Page, keyword string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if findModal(page, keyword) == nil {
+			return nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return errors.Errorf("%s弹窗未关闭", keyword)
+}
+
+// findModal 返回当前可见且文本含 keyword 的弹窗，没有返回 nil
+func findModal(page *rod.Page, keyword string) *rod.Element {
+	modals, err := page.Elements(".d-modal")
+	if err != nil {
+		return nil
+	}
+	for _, modal := range modals {
+		visible, _ := modal.Visible()
+		if !visible {
+			continue
+		}
+		text, err := modal.Text()
+		if err == nil && strings.Contains(text, keyword) {
+			return modal
+		}
+	}
+	return nil
+}
+
+// uploadCoverImage 切到"上传封面"并把图片塞进弹窗内的文件输入框
+func uploadCoverImage(ctx context.Context, modal *rod.Element, path string) error {
+	if tab, err := modal.Timeout(3*time.Second).ElementR("div, span", `^上传封面$`); err == nil {
+		if err := humanize.Click(tab); err != nil {
+			return errors.Wrap(err, "切换到上传封面失败")
+		}
+		humanize.Delay(ctx, humanize.AfterClick)
+	}
+
+	input, err := modal.Timeout(5 * time.Second).Element(`input[type="file"]`)
+	if err != nil {
+		return errors.Wrap(err, "未找到封面上传输入框")
+	}
+	if err := input.SetFiles([]string{path}); err != nil {
+		return errors.Wrap(err, "上传封面图片失败")
+	}
+
+	// 上传后弹窗内会出现预览图，等它出来再确认
+	if _, err := modal.Timeout(30 * time.Second).Element("img"); err != nil {
+		return errors.Wrap(err, "等待封面预览超时")
+	}
+	time.Sleep(time.Second)
+	return nil
+}
+
+// pickCoverFrame 在截帧时间轴上点到 at 对应的位置
+func pickCoverFrame(ctx context.Context, page *rod.Page, modal *rod.Element, at time.Duration) error {
+	if tab, err := modal.Timeout(3*time.Second).ElementR("div, span", `^截取封面$`); err == nil {
+		if err := humanize.Click(tab); err != nil {
+			return errors.Wrap(err, "切换到截取封面失败")
+		}
+		humanize.Delay(ctx, humanize.AfterClick)
+	}
+
+	video, err := modal.Timeout(5 * time.Second).Element("video")
+	if err != nil {
+		return errors.Wrap(err, "未找到封面预览视频")
+	}
+	res, err := video.Eval(`() => this.duration`)
+	if err != nil {
+		return errors.Wrap(err, "读取视频时长失败")
+	}
+	duration := time.Duration(res.Value.Num() * float64(time.Second))
+	if duration <= 0 {
+		return errors.New("视频时长未就绪")
+	}
+	if at > duration {
+		return errors.Errorf("封面时间点 %s 超出视频时长 %s", at, duration.Round(time.Millisecond))
+	}
+
+	track, err := modal.Timeout(5 * time.Second).Element(`[class*="slider"], [class*="timeline"], [class*="track"]`)
+	if err != nil {
+		return errors.Wrap(err, "未找到截帧时间轴")
+	}
+	shape, err := track.Shape()
+	if err != nil || len(shape.Quads) == 0 {
+		return errors.New("读取截帧时间轴位置失败")
+	}
+	box := shape.Box()
+
+	pt := proto.Point{X: coverSliderX(box.X, box.Width, at, duration), Y: box.Y + box.Height/2}
+	if err := humanize.ClickAt(page, pt); err != nil {
+		return errors.Wrap(err, "点击截帧时间轴失败")
+	}
+	time.Sleep(time.Second) // 等预览帧刷新
+	return nil
+}
+
+// coverSliderX 时间点在时间轴上对应的横坐标，两端各收 1px 避免点到边框外
+func coverSliderX(left, width float64, at, duration time.Duration) float64 {
+	if width <= 2 || duration <= 0 {
+		return left + width/2
+	}
+	ratio := float64(at) / float64(duration)
+	ratio = min(max(ratio, 0), 1)
+	return left + 1 + ratio*(width-2)
+}
@@ -0,0 +1,52 @@
+package xiaohongshu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVideoCoverValidate(t *testing.T) {
+	img := filepath.Join(t.TempDir(), "cover.jpg")
+	require.NoError(t, os.WriteFile(img, []byte("x"), 0o644))
+
+	at := 3 * time.Second
+	negative := -time.Second
+
+	tests := []struct {
+		name    string
+		cover   VideoCover
+		wantErr bool
+	}{
+		{name: "未指定", cover: VideoCover{}},
+		{name: "封面图", cover: VideoCover{Image: img}},
+		{name: "时间点", cover: VideoCover{Time: &at}},
+		{name: "二者同时指定", cover: VideoCover{Image: img, Time: &at}, wantErr: true},
+		{name: "负时间点", cover: VideoCover{Time: &negative}, wantErr: true},
+		{name: "封面图不存在", cover: VideoCover{Image: filepath.Join(t.TempDir(), "missing.jpg")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cover.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCoverSliderX(t *testing.T) {
+	d := 10 * time.Second
+
+	assert.InDelta(t, 101, coverSliderX(100, 202, 0, d), 1e-9, "起点收 1px")
+	assert.InDelta(t, 201, coverSliderX(100, 202, 5*time.Second, d), 1e-9)
+	assert.InDelta(t, 301, coverSliderX(100, 202, d, d), 1e-9, "终点收 1px")
+	assert.InDelta(t, 301, coverSliderX(100, 202, 20*time.Second, d), 1e-9, "超出时长夹到终点")
+	assert.InDelta(t, 201, coverSliderX(100, 202, time.Second, 0), 1e-9, "时长未知取中点")
+}